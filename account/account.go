@@ -0,0 +1,150 @@
+/*
+Package account looks after users as a whole, across every car share they belong to.
+*/
+package account
+
+import (
+	"fmt"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// Merger moves everything belonging to one user over to another
+type Merger struct {
+	UserStorage     storage.UserStorage
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	RouteStorage    storage.RouteStorage
+	ScheduleStorage storage.ScheduleStorage
+	VehicleStorage  storage.VehicleStorage
+	ExpenseStorage  storage.ExpenseStorage
+}
+
+// Merge a user into another, typically a placeholder created for a car share into the real
+// user who has since signed up. Every car share the user belongs to, and its trips, routes,
+// schedules, vehicles and expenses, is rewritten to refer to the other user instead, the
+// scores rebuilt and the merged user deleted.
+func (m Merger) Merge(from model.User, into model.User, ctx api2go.APIContexter) error {
+
+	carShares, err := m.CarShareStorage.GetAll(from.GetID(), ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving car shares of user %s, %s", from.GetID(), err)
+	}
+
+	// placeholders belong to the car share they are linked to, even if they were never made members
+	if from.LinkedCarShareID != "" && !containsCarShare(carShares, from.LinkedCarShareID) {
+		carShare, err := m.CarShareStorage.GetOne(from.LinkedCarShareID, ctx)
+		switch err {
+		case nil:
+			carShares = append(carShares, carShare)
+		case storage.ErrNotFound:
+			break
+		default:
+			return fmt.Errorf("error retrieving linked car share %s, %s", from.LinkedCarShareID, err)
+		}
+	}
+
+	for _, carShare := range carShares {
+		err = m.mergeCarShare(carShare, from.GetID(), into.GetID(), ctx)
+		if err != nil {
+			return fmt.Errorf("error merging user %s into %s in car share %s, %s", from.GetID(), into.GetID(), carShare.GetID(), err)
+		}
+	}
+
+	err = m.UserStorage.Delete(from.GetID(), ctx)
+	if err != nil {
+		return fmt.Errorf("error deleting merged user %s, %s", from.GetID(), err)
+	}
+
+	return nil
+}
+
+// mergeCarShare replaces one user with another throughout a car share, only updating what
+// refers to them
+func (m Merger) mergeCarShare(carShare model.CarShare, from string, to string, ctx api2go.APIContexter) error {
+
+	if carShare.ReplaceUser(from, to) {
+		err := m.CarShareStorage.Update(carShare, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := m.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		if !trip.ReplaceUser(from, to) {
+			return nil
+		}
+		return m.TripStorage.Update(trip, ctx)
+	}, ctx)
+	if err != nil {
+		return err
+	}
+
+	routes, err := m.RouteStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		if route.ReplaceUser(from, to) {
+			err = m.RouteStorage.Update(route, ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	schedules, err := m.ScheduleStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		if schedule.ReplaceUser(from, to) {
+			err = m.ScheduleStorage.Update(schedule, ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	vehicles, err := m.VehicleStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, vehicle := range vehicles {
+		if vehicle.ReplaceUser(from, to) {
+			err = m.VehicleStorage.Update(vehicle, ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	expenses, err := m.ExpenseStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, expense := range expenses {
+		if expense.ReplaceUser(from, to) {
+			err = m.ExpenseStorage.Update(expense, ctx)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// the running scores of users who were both on a trip need working out again
+	return ledger.Rebuild(carShare, m.TripStorage, ctx)
+}
+
+// containsCarShare returns true if the car share is in the list
+func containsCarShare(carShares []model.CarShare, ID string) bool {
+	for _, carShare := range carShares {
+		if carShare.GetID() == ID {
+			return true
+		}
+	}
+	return false
+}
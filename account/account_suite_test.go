@@ -0,0 +1,13 @@
+package account
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAccount(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Account Suite")
+}
@@ -0,0 +1,153 @@
+package account
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Merger", func() {
+
+	var (
+		merger      Merger
+		context     *api2go.APIContext
+		carShareID  string
+		placeholder model.User
+		user        model.User
+		other       model.User
+		tripIDs     []string
+		routeID     string
+		vehicleID   string
+		expenseID   string
+		err         error
+	)
+
+	insertUser := func(u model.User) model.User {
+		id, err := merger.UserStorage.Insert(u, context)
+		Expect(err).ToNot(HaveOccurred())
+		u.SetID(id)
+		return u
+	}
+
+	BeforeEach(func() {
+		merger = Merger{
+			UserStorage:     memory.NewUserStorage(),
+			CarShareStorage: memory.NewCarShareStorage(),
+			TripStorage:     memory.NewTripStorage(),
+			RouteStorage:    memory.NewRouteStorage(),
+			ScheduleStorage: memory.NewScheduleStorage(),
+			VehicleStorage:  memory.NewVehicleStorage(),
+			ExpenseStorage:  memory.NewExpenseStorage(),
+		}
+		context = &api2go.APIContext{}
+		tripIDs = nil
+
+		carShareID, err = merger.CarShareStorage.Insert(model.CarShare{}, context)
+		Expect(err).ToNot(HaveOccurred())
+		placeholder = insertUser(model.User{DisplayName: "Sam", LinkedCarShareID: carShareID})
+		user = insertUser(model.User{FirebaseUID: "samFirebaseUID"})
+		other = insertUser(model.User{FirebaseUID: "otherFirebaseUID"})
+
+		carShare, _ := merger.CarShareStorage.GetOne(carShareID, context)
+		carShare.MemberIDs = []string{placeholder.GetID(), other.GetID()}
+		carShare.AdminIDs = []string{placeholder.GetID()}
+		Expect(merger.CarShareStorage.Update(carShare, context)).To(Succeed())
+
+		for day, trip := range []model.Trip{
+			{Metres: 1000, DriverID: placeholder.GetID(), PassengerIDs: []string{other.GetID()}},
+			{Metres: 2000, DriverID: other.GetID(), PassengerIDs: []string{placeholder.GetID()}, ConfirmedBy: []string{other.GetID(), placeholder.GetID()}},
+		} {
+			trip.CarShareID = carShareID
+			trip.TimeStamp = time.Date(2017, 11, day+1, 0, 0, 0, 0, time.UTC)
+			id, err := merger.TripStorage.Insert(trip, context)
+			Expect(err).ToNot(HaveOccurred())
+			tripIDs = append(tripIDs, id)
+		}
+		Expect(ledger.Rebuild(carShare, merger.TripStorage, context)).To(Succeed())
+
+		routeID, err = merger.RouteStorage.Insert(model.Route{CarShareID: carShareID, DriverID: placeholder.GetID()}, context)
+		Expect(err).ToNot(HaveOccurred())
+		vehicleID, err = merger.VehicleStorage.Insert(model.Vehicle{CarShareID: carShareID, OwnerID: placeholder.GetID()}, context)
+		Expect(err).ToNot(HaveOccurred())
+		expenseID, err = merger.ExpenseStorage.Insert(model.Expense{
+			Amount:         600,
+			CarShareID:     carShareID,
+			PayerID:        other.GetID(),
+			ParticipantIDs: []string{other.GetID(), placeholder.GetID()},
+			Shares:         map[string]int{other.GetID(): 300, placeholder.GetID(): 300},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		err = merger.Merge(placeholder, user, context)
+	})
+
+	It("should not throw an error", func() {
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should make the user a member and admin in place of the placeholder", func() {
+		carShare, err := merger.CarShareStorage.GetOne(carShareID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(carShare.MemberIDs).To(ConsistOf(user.GetID(), other.GetID()))
+		Expect(carShare.AdminIDs).To(Equal([]string{user.GetID()}))
+	})
+
+	It("should move the placeholder's trips and scores to the user", func() {
+		first, err := merger.TripStorage.GetOne(tripIDs[0], context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(first.DriverID).To(Equal(user.GetID()))
+		last, err := merger.TripStorage.GetOne(tripIDs[1], context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(last.PassengerIDs).To(Equal([]string{user.GetID()}))
+		Expect(last.ConfirmedBy).To(ConsistOf(other.GetID(), user.GetID()))
+		Expect(last.Scores).ToNot(HaveKey(placeholder.GetID()))
+		Expect(last.Scores[user.GetID()].MetresAsDriver).To(Equal(1000))
+		Expect(last.Scores[user.GetID()].MetresAsPassenger).To(Equal(2000))
+	})
+
+	It("should move the placeholder's routes, vehicles and expenses to the user", func() {
+		route, err := merger.RouteStorage.GetOne(routeID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(route.DriverID).To(Equal(user.GetID()))
+		vehicle, err := merger.VehicleStorage.GetOne(vehicleID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vehicle.OwnerID).To(Equal(user.GetID()))
+		expense, err := merger.ExpenseStorage.GetOne(expenseID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expense.ParticipantIDs).To(Equal([]string{other.GetID(), user.GetID()}))
+		Expect(expense.Shares).To(Equal(map[string]int{other.GetID(): 300, user.GetID(): 300}))
+	})
+
+	It("should delete the placeholder", func() {
+		_, err := merger.UserStorage.GetOne(placeholder.GetID(), context)
+		Expect(err).To(Equal(storage.ErrNotFound))
+	})
+
+	Context("into a user who was already on the same trip", func() {
+
+		BeforeEach(func() {
+			trip, _ := merger.TripStorage.GetOne(tripIDs[0], context)
+			trip.PassengerIDs = append(trip.PassengerIDs, user.GetID())
+			Expect(merger.TripStorage.Update(trip, context)).To(Succeed())
+		})
+
+		It("should only count them once, as the driver", func() {
+			trip, err := merger.TripStorage.GetOne(tripIDs[0], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.PassengerIDs).To(Equal([]string{other.GetID()}))
+			Expect(trip.Scores[user.GetID()]).To(Equal(model.Score{MetresAsDriver: 1000, PointsAsDriver: 1000}))
+		})
+
+	})
+
+})
@@ -0,0 +1,254 @@
+package account
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// ErasedName is the display name of the anonymous users that erased users are replaced by
+const ErasedName = "Deleted user"
+
+// ErrSoleAdmin is returned when erasing the only admin of a car share that has no other member
+// who signed up to take over from them
+var ErrSoleAdmin = errors.New("sole admin of a car share with no member to take over")
+
+// Eraser deletes users and everything that identifies them
+type Eraser struct {
+	UserStorage     storage.UserStorage
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	RouteStorage    storage.RouteStorage
+	ScheduleStorage storage.ScheduleStorage
+	VehicleStorage  storage.VehicleStorage
+	ExpenseStorage  storage.ExpenseStorage
+}
+
+// Erase a user who wants their account deleted. They are removed from the members and admins of
+// every car share they belong to, and from the routes and schedules of planned trips. Their part
+// in past trips, vehicles and expenses is taken over by an anonymous user in each car share so
+// the scores and balances of the other members stay the same. Car shares they were the only
+// member of are deleted outright. The only admin of a car share is succeeded by the next member
+// who signed up, and if there is none ErrSoleAdmin is returned before anything is erased.
+func (e Eraser) Erase(user model.User, ctx api2go.APIContexter) error {
+
+	carShares, err := e.CarShareStorage.GetAll(user.GetID(), ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving car shares of user %s, %s", user.GetID(), err)
+	}
+
+	// work out who takes over as admin first, so a car share without one stops the erasure
+	successors := make(map[string]string)
+	for _, carShare := range carShares {
+		if len(carShare.MemberIDs) == 1 || !carShare.IsAdmin(user.GetID()) || len(carShare.AdminIDs) > 1 {
+			continue
+		}
+		successor, err := e.successor(carShare, user.GetID(), ctx)
+		if err == ErrSoleAdmin {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("error finding successor to user %s in car share %s, %s", user.GetID(), carShare.GetID(), err)
+		}
+		successors[carShare.GetID()] = successor
+	}
+
+	for _, carShare := range carShares {
+		if len(carShare.MemberIDs) == 1 {
+			err = e.deleteCarShare(carShare, ctx)
+		} else {
+			err = e.eraseFromCarShare(carShare, user.GetID(), successors[carShare.GetID()], ctx)
+		}
+		if err != nil {
+			return fmt.Errorf("error erasing user %s from car share %s, %s", user.GetID(), carShare.GetID(), err)
+		}
+	}
+
+	err = e.UserStorage.Delete(user.GetID(), ctx)
+	if err != nil {
+		return fmt.Errorf("error deleting user %s, %s", user.GetID(), err)
+	}
+
+	return nil
+}
+
+// successor finds the first other member of a car share who signed up for themselves
+func (e Eraser) successor(carShare model.CarShare, userID string, ctx api2go.APIContexter) (string, error) {
+	for _, memberID := range carShare.MemberIDs {
+		if memberID == userID {
+			continue
+		}
+		member, err := e.UserStorage.GetOne(memberID, ctx)
+		switch err {
+		case nil:
+			if member.FirebaseUID != "" {
+				return memberID, nil
+			}
+		case storage.ErrNotFound:
+			break
+		default:
+			return "", err
+		}
+	}
+	return "", ErrSoleAdmin
+}
+
+// eraseFromCarShare removes a user from a car share, handing admin over to the successor if
+// there is one, and replaces them with an anonymous user everywhere they remain
+func (e Eraser) eraseFromCarShare(carShare model.CarShare, userID string, successor string, ctx api2go.APIContexter) error {
+
+	carShare.MemberIDs = without(carShare.MemberIDs, userID)
+	carShare.AdminIDs = without(carShare.AdminIDs, userID)
+	if successor != "" {
+		carShare.AdminIDs = append(carShare.AdminIDs, successor)
+	}
+	err := e.CarShareStorage.Update(carShare, ctx)
+	if err != nil {
+		return err
+	}
+
+	// planned trips are no longer theirs to take
+	routes, err := e.RouteStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		switch {
+		case route.DriverID == userID:
+			err = e.RouteStorage.Delete(route.GetID(), ctx)
+		case contains(route.PassengerIDs, userID):
+			route.PassengerIDs = without(route.PassengerIDs, userID)
+			err = e.RouteStorage.Update(route, ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	schedules, err := e.ScheduleStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		switch {
+		case schedule.DriverID == userID:
+			err = e.ScheduleStorage.Delete(schedule.GetID(), ctx)
+		case contains(schedule.PassengerIDs, userID):
+			schedule.PassengerIDs = without(schedule.PassengerIDs, userID)
+			err = e.ScheduleStorage.Update(schedule, ctx)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	anonymous := model.User{
+		DisplayName:      ErasedName,
+		LinkedCarShareID: carShare.GetID(),
+		Erased:           true,
+	}
+	anonymousID, err := e.UserStorage.Insert(anonymous, ctx)
+	if err != nil {
+		return err
+	}
+
+	merger := Merger{
+		UserStorage:     e.UserStorage,
+		CarShareStorage: e.CarShareStorage,
+		TripStorage:     e.TripStorage,
+		RouteStorage:    e.RouteStorage,
+		ScheduleStorage: e.ScheduleStorage,
+		VehicleStorage:  e.VehicleStorage,
+		ExpenseStorage:  e.ExpenseStorage,
+	}
+	return merger.mergeCarShare(carShare, userID, anonymousID, ctx)
+}
+
+// deleteCarShare and everything in it
+func (e Eraser) deleteCarShare(carShare model.CarShare, ctx api2go.APIContexter) error {
+
+	tripIDs := []string{}
+	err := e.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		tripIDs = append(tripIDs, trip.GetID())
+		return nil
+	}, ctx)
+	if err != nil {
+		return err
+	}
+	for _, tripID := range tripIDs {
+		err = e.TripStorage.Delete(tripID, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	routes, err := e.RouteStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, route := range routes {
+		err = e.RouteStorage.Delete(route.GetID(), ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	schedules, err := e.ScheduleStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, schedule := range schedules {
+		err = e.ScheduleStorage.Delete(schedule.GetID(), ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	vehicles, err := e.VehicleStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, vehicle := range vehicles {
+		err = e.VehicleStorage.Delete(vehicle.GetID(), ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	expenses, err := e.ExpenseStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+	for _, expense := range expenses {
+		err = e.ExpenseStorage.Delete(expense.GetID(), ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	return e.CarShareStorage.Delete(carShare.GetID(), ctx)
+}
+
+// contains returns true if the ID is in the list
+func contains(IDs []string, ID string) bool {
+	for _, candidate := range IDs {
+		if candidate == ID {
+			return true
+		}
+	}
+	return false
+}
+
+// without returns the list of IDs less the given ID
+func without(IDs []string, ID string) []string {
+	result := []string{}
+	for _, candidate := range IDs {
+		if candidate != ID {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
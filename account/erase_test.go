@@ -0,0 +1,199 @@
+package account
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Eraser", func() {
+
+	var (
+		eraser      Eraser
+		context     *api2go.APIContext
+		carShareID  string
+		soloShareID string
+		user        model.User
+		other       model.User
+		placeholder model.User
+		tripIDs     []string
+		routeID     string
+		scheduleID  string
+		vehicleID   string
+		expenseID   string
+		soloTripID  string
+		err         error
+	)
+
+	insertUser := func(u model.User) model.User {
+		id, err := eraser.UserStorage.Insert(u, context)
+		Expect(err).ToNot(HaveOccurred())
+		u.SetID(id)
+		return u
+	}
+
+	anonymousFor := func(tripID string) model.User {
+		trip, err := eraser.TripStorage.GetOne(tripID, context)
+		Expect(err).ToNot(HaveOccurred())
+		anonymous, err := eraser.UserStorage.GetOne(trip.DriverID, context)
+		Expect(err).ToNot(HaveOccurred())
+		return anonymous
+	}
+
+	BeforeEach(func() {
+		eraser = Eraser{
+			UserStorage:     memory.NewUserStorage(),
+			CarShareStorage: memory.NewCarShareStorage(),
+			TripStorage:     memory.NewTripStorage(),
+			RouteStorage:    memory.NewRouteStorage(),
+			ScheduleStorage: memory.NewScheduleStorage(),
+			VehicleStorage:  memory.NewVehicleStorage(),
+			ExpenseStorage:  memory.NewExpenseStorage(),
+		}
+		context = &api2go.APIContext{}
+		tripIDs = nil
+
+		user = insertUser(model.User{FirebaseUID: "userFirebaseUID", DisplayName: "Sam", Email: "sam@example.com"})
+		other = insertUser(model.User{FirebaseUID: "otherFirebaseUID"})
+
+		carShareID, err = eraser.CarShareStorage.Insert(model.CarShare{
+			MemberIDs: []string{user.GetID(), other.GetID()},
+			AdminIDs:  []string{user.GetID()},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+		placeholder = insertUser(model.User{DisplayName: "Alex", LinkedCarShareID: carShareID})
+
+		for day, trip := range []model.Trip{
+			{Metres: 1000, DriverID: user.GetID(), PassengerIDs: []string{other.GetID()}},
+			{Metres: 2000, DriverID: other.GetID(), PassengerIDs: []string{user.GetID()}},
+		} {
+			trip.CarShareID = carShareID
+			trip.TimeStamp = time.Date(2017, 11, day+1, 0, 0, 0, 0, time.UTC)
+			id, err := eraser.TripStorage.Insert(trip, context)
+			Expect(err).ToNot(HaveOccurred())
+			tripIDs = append(tripIDs, id)
+		}
+		carShare, _ := eraser.CarShareStorage.GetOne(carShareID, context)
+		Expect(ledger.Rebuild(carShare, eraser.TripStorage, context)).To(Succeed())
+
+		routeID, err = eraser.RouteStorage.Insert(model.Route{CarShareID: carShareID, DriverID: user.GetID()}, context)
+		Expect(err).ToNot(HaveOccurred())
+		scheduleID, err = eraser.ScheduleStorage.Insert(model.Schedule{CarShareID: carShareID, DriverID: other.GetID(), PassengerIDs: []string{user.GetID()}}, context)
+		Expect(err).ToNot(HaveOccurred())
+		vehicleID, err = eraser.VehicleStorage.Insert(model.Vehicle{CarShareID: carShareID, OwnerID: user.GetID()}, context)
+		Expect(err).ToNot(HaveOccurred())
+		expenseID, err = eraser.ExpenseStorage.Insert(model.Expense{
+			Amount:         600,
+			CarShareID:     carShareID,
+			PayerID:        user.GetID(),
+			ParticipantIDs: []string{user.GetID(), other.GetID()},
+			Shares:         map[string]int{user.GetID(): 300, other.GetID(): 300},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		soloShareID, err = eraser.CarShareStorage.Insert(model.CarShare{
+			MemberIDs: []string{user.GetID()},
+			AdminIDs:  []string{user.GetID()},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+		soloTripID, err = eraser.TripStorage.Insert(model.Trip{CarShareID: soloShareID, Metres: 500, DriverID: user.GetID()}, context)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		err = eraser.Erase(user, context)
+	})
+
+	It("should not throw an error", func() {
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should delete the user", func() {
+		_, err := eraser.UserStorage.GetOne(user.GetID(), context)
+		Expect(err).To(Equal(storage.ErrNotFound))
+	})
+
+	It("should hand admin over to the other member", func() {
+		carShare, err := eraser.CarShareStorage.GetOne(carShareID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(carShare.MemberIDs).To(Equal([]string{other.GetID()}))
+		Expect(carShare.AdminIDs).To(Equal([]string{other.GetID()}))
+	})
+
+	It("should replace them in past trips with an anonymous user", func() {
+		anonymous := anonymousFor(tripIDs[0])
+		Expect(anonymous.GetID()).ToNot(Equal(user.GetID()))
+		Expect(anonymous.DisplayName).To(Equal(ErasedName))
+		Expect(anonymous.Email).To(BeEmpty())
+		Expect(anonymous.Erased).To(BeTrue())
+		Expect(anonymous.IsPlaceholder()).To(BeFalse())
+
+		last, err := eraser.TripStorage.GetOne(tripIDs[1], context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(last.PassengerIDs).To(Equal([]string{anonymous.GetID()}))
+		Expect(last.Scores).ToNot(HaveKey(user.GetID()))
+		Expect(last.Scores[anonymous.GetID()].MetresAsDriver).To(Equal(1000))
+		Expect(last.Scores[other.GetID()]).To(Equal(model.Score{
+			MetresAsDriver:    2000,
+			MetresAsPassenger: 1000,
+			PointsAsDriver:    2000,
+			PointsAsPassenger: 1000,
+		}))
+	})
+
+	It("should remove them from planned trips", func() {
+		_, err := eraser.RouteStorage.GetOne(routeID, context)
+		Expect(err).To(Equal(storage.ErrNotFound))
+		schedule, err := eraser.ScheduleStorage.GetOne(scheduleID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule.PassengerIDs).To(BeEmpty())
+	})
+
+	It("should keep their vehicles and expenses under the anonymous user", func() {
+		anonymous := anonymousFor(tripIDs[0])
+		vehicle, err := eraser.VehicleStorage.GetOne(vehicleID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(vehicle.OwnerID).To(Equal(anonymous.GetID()))
+		expense, err := eraser.ExpenseStorage.GetOne(expenseID, context)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(expense.PayerID).To(Equal(anonymous.GetID()))
+		Expect(expense.Shares).To(Equal(map[string]int{anonymous.GetID(): 300, other.GetID(): 300}))
+	})
+
+	It("should delete car shares they were the only member of", func() {
+		_, err := eraser.CarShareStorage.GetOne(soloShareID, context)
+		Expect(err).To(Equal(storage.ErrNotFound))
+		_, err = eraser.TripStorage.GetOne(soloTripID, context)
+		Expect(err).To(Equal(storage.ErrNotFound))
+	})
+
+	Context("sole admin with no member who signed up", func() {
+
+		BeforeEach(func() {
+			carShare, _ := eraser.CarShareStorage.GetOne(carShareID, context)
+			carShare.MemberIDs = []string{user.GetID(), placeholder.GetID()}
+			Expect(eraser.CarShareStorage.Update(carShare, context)).To(Succeed())
+		})
+
+		It("should return ErrSoleAdmin", func() {
+			Expect(err).To(Equal(ErrSoleAdmin))
+		})
+
+		It("should not erase anything", func() {
+			_, err := eraser.UserStorage.GetOne(user.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = eraser.CarShareStorage.GetOne(soloShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+	})
+
+})
@@ -5,12 +5,24 @@ package main
 
 import (
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/smtp"
 	"os"
+	"time"
 
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/export"
 	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/notification"
+	"github.com/LewisWatson/carshare-back/push"
 	"github.com/LewisWatson/carshare-back/resource"
+	"github.com/LewisWatson/carshare-back/rpc"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"github.com/LewisWatson/carshare-back/scheduler"
 	"github.com/LewisWatson/carshare-back/storage/mongodb"
+	"github.com/LewisWatson/carshare-back/webhook"
 	"github.com/LewisWatson/firebase-jwt-auth"
 	"github.com/alecthomas/kingpin"
 	"github.com/benbjohnson/clock"
@@ -19,24 +31,46 @@ import (
 	"github.com/manyminds/api2go/routing"
 	"github.com/op/go-logging"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 
 	"gopkg.in/mgo.v2"
 )
 
 var (
 	port              = kingpin.Flag("port", "Set port to bind to").Default("31415").Envar("CARSHARE_PORT").Int()
+	grpcPort          = kingpin.Flag("grpcPort", "Set port to serve the gRPC CarShareService on").Default("31416").Envar("CARSHARE_GRPC_PORT").Int()
 	mgoURL            = kingpin.Flag("mgoURL", "URL to MongoDB server or seed server(s) for clusters").Default("localhost").Envar("CARSHARE_MGO_URL").URL()
+	mgoDB             = kingpin.Flag("mgoDB", "MongoDB database to use, overrides any database in mgoURL").PlaceHolder("NAME").Envar("CARSHARE_MGO_DB").String()
+	mgoPrefix         = kingpin.Flag("mgoPrefix", "Prefix applied to all MongoDB collection names").PlaceHolder("PREFIX").Envar("CARSHARE_MGO_PREFIX").String()
 	firebaseProjectID = kingpin.Flag("firebase", "Firebase project to use for authentication").Default("ridesharelogger").Envar("CARSHARE_FIREBASE_PROJECT").String()
 	acao              = kingpin.Flag("cors", "Enable HTTP Access Control (CORS) for the specified URI").PlaceHolder("URI").Envar("CARSHARE_CORS_URI").String()
+	scheduleInterval  = kingpin.Flag("scheduleInterval", "How often to check for scheduled trips that have fallen due").Default("1m").Envar("CARSHARE_SCHEDULE_INTERVAL").Duration()
+	deliveryInterval  = kingpin.Flag("deliveryInterval", "How often to send queued webhook deliveries").Default("10s").Envar("CARSHARE_DELIVERY_INTERVAL").Duration()
+	smtpAddr          = kingpin.Flag("smtp", "SMTP server to send emails through, emails aren't sent without one").PlaceHolder("HOST:PORT").Envar("CARSHARE_SMTP").String()
+	smtpUser          = kingpin.Flag("smtpUser", "User to authenticate with the SMTP server as").PlaceHolder("USER").Envar("CARSHARE_SMTP_USER").String()
+	smtpPassword      = kingpin.Flag("smtpPassword", "Password to authenticate with the SMTP server with").PlaceHolder("PASSWORD").Envar("CARSHARE_SMTP_PASSWORD").String()
+	mailFrom          = kingpin.Flag("mailFrom", "Address emails are sent from, carshare@localhost by default").Default("carshare@localhost").PlaceHolder("ADDRESS").Envar("CARSHARE_MAIL_FROM").String()
+	digestDay         = kingpin.Flag("digestDay", "Day of the week the weekly digest is sent").Default("monday").Envar("CARSHARE_DIGEST_DAY").Enum("sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday")
+	digestTime        = kingpin.Flag("digestTime", "Time of day the weekly digest is sent").Default("08:00").PlaceHolder("HH:MM").Envar("CARSHARE_DIGEST_TIME").String()
+	digestTimeZone    = kingpin.Flag("digestTimeZone", "Time zone of the digest day and time").Default("UTC").Envar("CARSHARE_DIGEST_TIMEZONE").String()
+	fcmServerKey      = kingpin.Flag("fcmServerKey", "Firebase Cloud Messaging server key to push notifications with, notifications aren't pushed without one").PlaceHolder("KEY").Envar("CARSHARE_FCM_SERVER_KEY").String()
+	fcmEndpoint       = kingpin.Flag("fcmEndpoint", "FCM compatible HTTP endpoint to push notifications through").Default(push.DefaultFCMEndpoint).PlaceHolder("URL").Envar("CARSHARE_FCM_ENDPOINT").String()
+	notifyInterval    = kingpin.Flag("notifyInterval", "How often to check for digests and reminders that have fallen due").Default("1m").Envar("CARSHARE_NOTIFY_INTERVAL").Duration()
+	fuelPrices        = kingpin.Flag("fuelPrice", "Price of a fuel per litre (or kWh) in the smallest unit of currency, used to cost trips. Repeat for each fuel").Default("petrol=130", "diesel=135", "lpg=60", "electric=15").PlaceHolder("FUEL=PRICE").Envar("CARSHARE_FUEL_PRICE").StringMap()
+
+	serveCmd = kingpin.Command("serve", "Serve the API (default)").Default()
+
+	exportCmd        = kingpin.Command("export", "Export a complete archive of a car share")
+	exportCarShareID = exportCmd.Arg("carShare", "ID of the car share to export").Required().String()
+	exportFormat     = exportCmd.Flag("format", "Archive format").Default("json").Enum("json", "csv")
+	exportOut        = exportCmd.Flag("out", "File to write the archive to instead of stdout").PlaceHolder("FILE").String()
+
+	command string
 
 	log    = logging.MustGetLogger("main")
 	format = logging.MustStringFormatter(
 		`%{color}%{time:2006-01-02T15:04:05.999} %{level:.4s} %{id:03x}%{color:reset} %{message}`,
 	)
-
-	userStorage     = &mongodb.UserStorage{}
-	carShareStorage = &mongodb.CarShareStorage{}
-	tripStorage     = &mongodb.TripStorage{}
 )
 
 func init() {
@@ -45,24 +79,142 @@ func init() {
 
 	kingpin.UsageTemplate(kingpin.CompactUsageTemplate).Version("0.4.0").Author("Lewis Watson")
 	kingpin.CommandLine.Help = "API for tracking car shares"
-	kingpin.Parse()
+	command = kingpin.Parse()
 }
 
 func main() {
 
 	log.Infof("connecting to mongodb server %s%s", (*mgoURL).Host, (*mgoURL).Path)
-	db, err := mgo.Dial((*mgoURL).String())
+	dialInfo, err := mgo.ParseURL((*mgoURL).String())
+	if err != nil {
+		log.Fatalf("error parsing mongodb url: %s", err)
+	}
+	db, err := mgo.DialWithInfo(dialInfo)
 	if err != nil {
 		log.Fatalf("error connecting to mongodb server: %s", err)
 	}
 
+	// database named on the command line wins over one named in the url
+	mgoConfig := mongodb.Config{DBName: dialInfo.Database, CollPrefix: *mgoPrefix}
+	if *mgoDB != "" {
+		mgoConfig.DBName = *mgoDB
+	}
+	if mgoConfig.DBName == "" {
+		mgoConfig.DBName = mongodb.CarShareDB
+	}
+	log.Infof("using mongodb database \"%s\" with collection prefix \"%s\"", mgoConfig.DBName, mgoConfig.CollPrefix)
+
+	userStorage := &mongodb.UserStorage{Config: mgoConfig}
+	carShareStorage := &mongodb.CarShareStorage{Config: mgoConfig}
+	tripStorage := &mongodb.TripStorage{Config: mgoConfig, CarshareStorage: carShareStorage}
+	routeStorage := &mongodb.RouteStorage{Config: mgoConfig}
+	scheduleStorage := &mongodb.ScheduleStorage{Config: mgoConfig}
+	expenseStorage := &mongodb.ExpenseStorage{Config: mgoConfig}
+	vehicleStorage := &mongodb.VehicleStorage{Config: mgoConfig}
+	webhookStorage := &mongodb.WebhookStorage{Config: mgoConfig}
+	deliveryStorage := &mongodb.DeliveryStorage{Config: mgoConfig}
+	deviceStorage := &mongodb.DeviceStorage{Config: mgoConfig}
+
+	switch command {
+	case exportCmd.FullCommand():
+		exportCarShare(db, carShareStorage, tripStorage, userStorage)
+		return
+	case serveCmd.FullCommand():
+		break
+	}
+
 	log.Infof("using firebase project \"%s\" for authentication", *firebaseProjectID)
 	tokenVerifier, err := fireauth.New(*firebaseProjectID)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	r := gin.Default()
+	clk := clock.New()
+
+	prices, err := model.ParseFuelPrices(*fuelPrices)
+	if err != nil {
+		log.Fatalf("error parsing fuel prices: %s", err)
+	}
+	log.Infof("costing trips with fuel prices %v", prices)
+
+	// materialise scheduled trips in the background for as long as we are serving
+	schedulerCtx := &api2go.APIContext{}
+	schedulerCtx.Set("db", db)
+	tripScheduler := scheduler.Scheduler{
+		ScheduleStorage: scheduleStorage,
+		TripStorage:     tripStorage,
+		CarShareStorage: carShareStorage,
+		Clock:           clk,
+	}
+	log.Infof("checking for scheduled trips every %s", *scheduleInterval)
+	go tripScheduler.Run(*scheduleInterval, schedulerCtx, nil)
+
+	// events are queued for webhooks as requests are served and delivered in the background
+	webhooks := &webhook.Notifier{
+		WebhookStorage:  webhookStorage,
+		DeliveryStorage: deliveryStorage,
+		Clock:           clk,
+	}
+	deliverer := webhook.Deliverer{
+		WebhookStorage:  webhookStorage,
+		DeliveryStorage: deliveryStorage,
+		Client:          &http.Client{Timeout: 10 * time.Second},
+		Clock:           clk,
+	}
+	log.Infof("sending webhook deliveries every %s", *deliveryInterval)
+	go deliverer.Run(*deliveryInterval, schedulerCtx, nil)
+
+	// digests and reminders are emailed in the background, when there is a server to send them
+	if *smtpAddr != "" {
+		var auth smtp.Auth
+		if *smtpUser != "" {
+			host, _, err := net.SplitHostPort(*smtpAddr)
+			if err != nil {
+				log.Fatalf("error parsing smtp server address: %s", err)
+			}
+			auth = smtp.PlainAuth("", *smtpUser, *smtpPassword, host)
+		}
+		notifier := notification.Notifier{
+			CarShareStorage: carShareStorage,
+			TripStorage:     tripStorage,
+			UserStorage:     userStorage,
+			Mailer:          notification.SMTPMailer{Addr: *smtpAddr, From: *mailFrom, Auth: auth},
+			Clock:           clk,
+			Digest:          model.Reminder{Weekdays: []string{*digestDay}, Time: *digestTime, TimeZone: *digestTimeZone},
+		}
+		err = notifier.Digest.Validate()
+		if err != nil {
+			log.Fatalf("error parsing digest time: %s", err)
+		}
+		log.Infof("emailing digests and reminders through %s every %s", *smtpAddr, *notifyInterval)
+		go notifier.Run(*notifyInterval, schedulerCtx, nil)
+	} else {
+		log.Infof("no smtp server given, digests and reminders won't be emailed")
+	}
+
+	// trip and membership events are pushed to members' devices as requests are served
+	var pusher *push.Pusher
+	if *fcmServerKey != "" {
+		pusher = &push.Pusher{
+			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
+			DeviceStorage:   deviceStorage,
+			Sender: push.FCMSender{
+				Endpoint:  *fcmEndpoint,
+				ServerKey: *fcmServerKey,
+				Client:    &http.Client{Timeout: 10 * time.Second},
+			},
+		}
+		log.Infof("pushing notifications through %s", *fcmEndpoint)
+	} else {
+		log.Infof("no fcm server key given, notifications won't be pushed")
+	}
+
+	// changes are published as requests are served to members streaming their car shares
+	bus := events.NewBus(events.DefaultHistory, clk)
+
+	r := gin.New()
+	r.Use(requestLogger, gin.Recovery())
 	api := api2go.NewAPIWithRouting(
 		"v0",
 		api2go.NewStaticResolver("/"),
@@ -77,45 +229,332 @@ func main() {
 		func(c api2go.APIContexter, w http.ResponseWriter, r *http.Request) {
 			// ensure the db connection is always available in the context
 			c.Set("db", db)
-			if *acao != "" {
-				w.Header().Set("Access-Control-Allow-Origin", *acao)
-				w.Header().Set("Access-Control-Allow-Headers", "Authorization,content-type")
-				w.Header().Set("Access-Control-Allow-Methods", "GET,PATCH,DELETE,OPTIONS")
-			}
+			setCORSHeaders(w)
 		},
 	)
 
+	userResource := resource.UserResource{
+		UserStorage:     userStorage,
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		RouteStorage:    routeStorage,
+		ScheduleStorage: scheduleStorage,
+		VehicleStorage:  vehicleStorage,
+		ExpenseStorage:  expenseStorage,
+		TokenVerifier:   tokenVerifier,
+		Webhooks:        webhooks,
+		Push:            pusher,
+		Events:          bus,
+	}
+	api.AddResource(model.User{}, userResource)
+	tripResource := resource.TripResource{
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		CarShareStorage: carShareStorage,
+		RouteStorage:    routeStorage,
+		VehicleStorage:  vehicleStorage,
+		TokenVerifier:   tokenVerifier,
+		Clock:           clk,
+		FuelPrices:      prices,
+		Webhooks:        webhooks,
+		Push:            pusher,
+		Events:          bus,
+	}
+	api.AddResource(model.Trip{}, tripResource)
+	carShareResource := resource.CarShareResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		RouteStorage:    routeStorage,
+		ExpenseStorage:  expenseStorage,
+		VehicleStorage:  vehicleStorage,
+		TokenVerifier:   tokenVerifier,
+		Webhooks:        webhooks,
+		Push:            pusher,
+		Events:          bus,
+	}
+	api.AddResource(model.CarShare{}, carShareResource)
+	api.AddResource(
+		model.Route{},
+		resource.RouteResource{
+			RouteStorage:    routeStorage,
+			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
+			TokenVerifier:   tokenVerifier,
+			Events:          bus,
+		},
+	)
 	api.AddResource(
-		model.User{},
-		resource.UserResource{
+		model.Schedule{},
+		resource.ScheduleResource{
+			ScheduleStorage: scheduleStorage,
+			CarShareStorage: carShareStorage,
+			RouteStorage:    routeStorage,
 			UserStorage:     userStorage,
+			TokenVerifier:   tokenVerifier,
+			Events:          bus,
+			Clock:           clk,
+		},
+	)
+	api.AddResource(
+		model.Vehicle{},
+		resource.VehicleResource{
+			VehicleStorage:  vehicleStorage,
 			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
 			TokenVerifier:   tokenVerifier,
+			Events:          bus,
 		},
 	)
 	api.AddResource(
-		model.Trip{},
-		resource.TripResource{
+		model.Expense{},
+		resource.ExpenseResource{
+			ExpenseStorage:  expenseStorage,
 			TripStorage:     tripStorage,
-			UserStorage:     userStorage,
 			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
 			TokenVerifier:   tokenVerifier,
-			Clock:           clock.New(),
+			Events:          bus,
+			Clock:           clk,
 		},
 	)
 	api.AddResource(
-		model.CarShare{},
-		resource.CarShareResource{
+		model.Balance{},
+		resource.BalanceResource{
+			ExpenseStorage:  expenseStorage,
 			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
+			TokenVerifier:   tokenVerifier,
+		},
+	)
+	api.AddResource(
+		model.Standings{},
+		resource.StandingsResource{
 			TripStorage:     tripStorage,
+			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
+			TokenVerifier:   tokenVerifier,
+			Clock:           clk,
+		},
+	)
+	api.AddResource(
+		model.Device{},
+		resource.DeviceResource{
+			DeviceStorage: deviceStorage,
+			UserStorage:   userStorage,
+			TokenVerifier: tokenVerifier,
+			Clock:         clk,
+		},
+	)
+	api.AddResource(
+		model.Webhook{},
+		resource.WebhookResource{
+			WebhookStorage:  webhookStorage,
+			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
+			TokenVerifier:   tokenVerifier,
+		},
+	)
+	api.AddResource(
+		model.Delivery{},
+		resource.DeliveryResource{
+			DeliveryStorage: deliveryStorage,
+			WebhookStorage:  webhookStorage,
+			CarShareStorage: carShareStorage,
 			UserStorage:     userStorage,
 			TokenVerifier:   tokenVerifier,
 		},
 	)
 
+	// endpoints that don't fit the {json:api} resource model are served directly by gin
+	carShareExportResource := resource.CarShareExportResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+	}
+	r.GET("/v0/carShares/:id/export", func(c *gin.Context) {
+		carShareExportResource.Export(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	eventsResource := resource.EventsResource{
+		CarShareStorage: carShareStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+		Bus:             bus,
+		Tickets:         events.NewTickets(events.DefaultTicketTTL, clk),
+		Clock:           clk,
+	}
+	r.POST("/v0/carShares/:id/events/tickets", func(c *gin.Context) {
+		eventsResource.IssueTicket(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	r.GET("/v0/carShares/:id/events", func(c *gin.Context) {
+		eventsResource.Stream(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	userExportResource := resource.UserExportResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+	}
+	r.GET("/v0/users/:id/export", func(c *gin.Context) {
+		userExportResource.Export(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	userStatsResource := resource.UserStatsResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+	}
+	r.GET("/v0/users/:id/stats", func(c *gin.Context) {
+		userStatsResource.Stats(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	tripImportResource := resource.TripImportResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+	}
+	r.POST("/v0/carShares/:id/import", func(c *gin.Context) {
+		tripImportResource.Import(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	guestResource := resource.GuestResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+		Webhooks:        webhooks,
+		Push:            pusher,
+		Events:          bus,
+	}
+	r.POST("/v0/carShares/:id/guests/promote", func(c *gin.Context) {
+		guestResource.Promote(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	userMergeResource := resource.UserMergeResource{
+		UserStorage:     userStorage,
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		RouteStorage:    routeStorage,
+		ScheduleStorage: scheduleStorage,
+		VehicleStorage:  vehicleStorage,
+		ExpenseStorage:  expenseStorage,
+		TokenVerifier:   tokenVerifier,
+	}
+	r.POST("/v0/users/:id/claim", func(c *gin.Context) {
+		userMergeResource.Claim(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	r.POST("/v0/users/:id/merge", func(c *gin.Context) {
+		userMergeResource.Merge(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	r.POST("/v0/trips/:id/confirm", func(c *gin.Context) {
+		tripResource.Confirm(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	r.POST("/v0/trips/:id/dispute", func(c *gin.Context) {
+		tripResource.Dispute(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	r.POST("/v0/trips/:id/cancel", func(c *gin.Context) {
+		tripResource.Cancel(c.Param("id"), c.Writer, apiRequest(c, db))
+	})
+	graphQLResource := resource.GraphQLResource{
+		CarShareStorage: carShareStorage,
+		TripStorage:     tripStorage,
+		UserStorage:     userStorage,
+		TokenVerifier:   tokenVerifier,
+	}
+	r.GET("/v0/graphql", func(c *gin.Context) {
+		graphQLResource.Query(c.Writer, apiRequest(c, db))
+	})
+	r.POST("/v0/graphql", func(c *gin.Context) {
+		graphQLResource.Query(c.Writer, apiRequest(c, db))
+	})
+
 	// handler for metrics
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// other services in the organisation call on the same resources over gRPC
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", *grpcPort))
+	if err != nil {
+		log.Fatalf("error listening for gRPC calls: %s", err)
+	}
+	grpcServer := grpc.NewServer()
+	carsharepb.RegisterCarShareServiceServer(grpcServer, rpc.Server{
+		Users:     userResource,
+		CarShares: carShareResource,
+		Trips:     tripResource,
+		Events:    bus,
+		NewContext: func() api2go.APIContexter {
+			ctx := &api2go.APIContext{}
+			ctx.Set("db", db)
+			return ctx
+		},
+	})
+	log.Infof("Listening and serving gRPC on :%d", *grpcPort)
+	go func() {
+		log.Fatal(grpcServer.Serve(listener))
+	}()
+
 	log.Infof("Listening and serving HTTP on :%d", *port)
 	log.Fatal(r.Run(fmt.Sprintf(":%d", *port)))
 }
+
+// setCORSHeaders if CORS has been enabled
+func setCORSHeaders(w http.ResponseWriter) {
+	if *acao != "" {
+		w.Header().Set("Access-Control-Allow-Origin", *acao)
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization,content-type")
+		w.Header().Set("Access-Control-Allow-Methods", "GET,PATCH,DELETE,OPTIONS")
+	}
+}
+
+// requestLogger logs each request served. Unlike gin's logger it leaves out the query string,
+// which can carry tickets for event streams.
+func requestLogger(c *gin.Context) {
+	start := time.Now()
+	c.Next()
+	log.Infof("%3d | %13v | %15s | %-7s %s", c.Writer.Status(), time.Since(start), c.ClientIP(), c.Request.Method, c.Request.URL.Path)
+}
+
+// apiRequest wraps a gin request for handlers in the resource package, giving them the
+// same context the api2go middleware provides
+func apiRequest(c *gin.Context, db *mgo.Session) api2go.Request {
+	ctx := &api2go.APIContext{}
+	ctx.Set("db", db)
+	setCORSHeaders(c.Writer)
+	return api2go.Request{
+		PlainRequest: c.Request,
+		QueryParams:  c.Request.URL.Query(),
+		Header:       c.Request.Header,
+		Context:      ctx,
+	}
+}
+
+// exportCarShare writes an archive of a car share for the export command
+func exportCarShare(db *mgo.Session, carShareStorage *mongodb.CarShareStorage, tripStorage *mongodb.TripStorage, userStorage *mongodb.UserStorage) {
+
+	ctx := &api2go.APIContext{}
+	ctx.Set("db", db)
+
+	carShare, err := carShareStorage.GetOne(*exportCarShareID, ctx)
+	if err != nil {
+		log.Fatalf("error retrieving car share %s: %s", *exportCarShareID, err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *exportOut != "" {
+		file, err := os.Create(*exportOut)
+		if err != nil {
+			log.Fatalf("error creating %s: %s", *exportOut, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	exporter := export.CarShareExporter{
+		TripStorage: tripStorage,
+		UserStorage: userStorage,
+	}
+	err = exporter.Export(carShare, export.Format(*exportFormat), out, ctx)
+	if err != nil {
+		log.Fatalf("error exporting car share %s: %s", carShare.GetID(), err)
+	}
+	log.Infof("exported car share %s", carShare.GetID())
+}
@@ -0,0 +1,191 @@
+/*
+Package events is an in process bus of changes to car shares, for streaming to clients as they
+happen. Recent events are kept so clients that reconnect can catch up on what they missed.
+*/
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// Actions that can be taken on resources
+const (
+	Created = "created"
+	Updated = "updated"
+	Deleted = "deleted"
+)
+
+// Reset is the type of event sent in place of events that are no longer kept, telling the
+// client to reload the car share
+const Reset = "reset"
+
+// DefaultHistory is how many events the bus keeps for catching up reconnecting clients
+const DefaultHistory = 1000
+
+// subscriberBuffer is how many events a subscriber can fall behind by before it is dropped
+const subscriberBuffer = 64
+
+// Event is a change to a resource of a car share. Data is a {json:api} document of the
+// resource as it was after the change, or before it for deletions.
+type Event struct {
+	ID         uint64
+	Type       string
+	CarShareID string
+	Data       json.RawMessage
+}
+
+// EventID formats the ID of the event for clients
+func (e Event) EventID() string {
+	return strconv.FormatUint(e.ID, 10)
+}
+
+// Subscription to the events of a car share. Replay holds the events missed since the last
+// event the client saw, or a single Reset event if some of them are no longer kept. Events is
+// closed if the subscriber falls too far behind, or when the subscription is closed.
+type Subscription struct {
+	Events <-chan Event
+	Replay []Event
+
+	events     chan Event
+	carShareID string
+	bus        *Bus
+}
+
+// Close the subscription
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus publishes events to the subscribers of their car share. Event IDs increase for the
+// life of the process and start from the time it started, so IDs from before a restart are
+// recognised as too old to catch up from.
+type Bus struct {
+	mu          sync.Mutex
+	first       uint64
+	last        uint64
+	history     []Event
+	size        int
+	subscribers map[*Subscription]struct{}
+}
+
+// NewBus creates a bus keeping the given number of recent events
+func NewBus(history int, clk clock.Clock) *Bus {
+	start := uint64(clk.Now().UnixNano())
+	return &Bus{
+		first:       start + 1,
+		last:        start,
+		size:        history,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish a change to a resource of a car share to everyone subscribed to it
+func (b *Bus) Publish(carShareID, action string, resource jsonapi.MarshalIdentifier) error {
+
+	document, err := jsonapi.MarshalToStruct(resource, nil)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s event, %s", action, err)
+	}
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s event, %s", action, err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.last++
+	event := Event{
+		ID:         b.last,
+		Type:       document.Data.DataObject.Type + "." + action,
+		CarShareID: carShareID,
+		Data:       data,
+	}
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+
+	for subscriber := range b.subscribers {
+		if subscriber.carShareID != carShareID {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+			// a slow client would hold up everyone else, it can catch up from the history
+			// when it reconnects
+			log.Warningf("dropping subscriber to car share %s that has fallen behind", carShareID)
+			b.drop(subscriber)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe to the events of a car share. If the client has seen events before, the ID of
+// the last one it saw is given so those it has missed since can be replayed.
+func (b *Bus) Subscribe(carShareID, lastEventID string) *Subscription {
+
+	events := make(chan Event, subscriberBuffer)
+	subscription := &Subscription{
+		Events:     events,
+		events:     events,
+		carShareID: carShareID,
+		bus:        b,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if lastEventID != "" {
+		subscription.Replay = b.since(carShareID, lastEventID)
+	}
+
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// since returns the events of a car share after the given one, or a Reset event if it isn't
+// one the bus can catch up from. The Reset event has the ID of the latest event so the client
+// can carry on from there once it has reloaded.
+func (b *Bus) since(carShareID, lastEventID string) (missed []Event) {
+
+	reset := []Event{{ID: b.last, Type: Reset, CarShareID: carShareID}}
+	lastID, err := strconv.ParseUint(lastEventID, 10, 64)
+	if err != nil || lastID < b.first-1 || lastID > b.last {
+		return reset
+	}
+	if len(b.history) > 0 && lastID+1 < b.history[0].ID {
+		return reset
+	}
+
+	for _, event := range b.history {
+		if event.ID > lastID && event.CarShareID == carShareID {
+			missed = append(missed, event)
+		}
+	}
+	return missed
+}
+
+func (b *Bus) unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(subscription)
+}
+
+// drop a subscriber, closing its channel. The lock must be held.
+func (b *Bus) drop(subscription *Subscription) {
+	if _, ok := b.subscribers[subscription]; !ok {
+		return
+	}
+	delete(b.subscribers, subscription)
+	close(subscription.events)
+}
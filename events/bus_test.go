@@ -0,0 +1,116 @@
+package events
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/benbjohnson/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bus", func() {
+
+	var (
+		bus          *Bus
+		subscription *Subscription
+		trip         model.Trip
+	)
+
+	BeforeEach(func() {
+		mockClock := clock.NewMock()
+		mockClock.Set(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC))
+		bus = NewBus(3, mockClock)
+		trip = model.Trip{Metres: 1000, CarShareID: "carShare1"}
+		trip.SetID("5a0b7a8b9bee1b0012345678")
+		subscription = bus.Subscribe("carShare1", "")
+	})
+
+	AfterEach(func() {
+		subscription.Close()
+	})
+
+	It("should publish changes to subscribers of the car share", func() {
+		Expect(bus.Publish("carShare1", Created, trip)).To(Succeed())
+		var event Event
+		Eventually(subscription.Events).Should(Receive(&event))
+		Expect(event.Type).To(Equal("trips.created"))
+		Expect(event.CarShareID).To(Equal("carShare1"))
+
+		var document struct {
+			Data struct {
+				Type string `json:"type"`
+				ID   string `json:"id"`
+			} `json:"data"`
+		}
+		Expect(json.Unmarshal(event.Data, &document)).To(Succeed())
+		Expect(document.Data.Type).To(Equal("trips"))
+		Expect(document.Data.ID).To(Equal(trip.GetID()))
+	})
+
+	It("should not publish changes to subscribers of other car shares", func() {
+		Expect(bus.Publish("carShare2", Created, trip)).To(Succeed())
+		Consistently(subscription.Events).ShouldNot(Receive())
+	})
+
+	It("should close the events once the subscription is closed", func() {
+		subscription.Close()
+		Eventually(subscription.Events).Should(BeClosed())
+	})
+
+	It("should drop subscribers that fall too far behind", func() {
+		for i := 0; i <= subscriberBuffer; i++ {
+			Expect(bus.Publish("carShare1", Updated, trip)).To(Succeed())
+		}
+		for i := 0; i < subscriberBuffer; i++ {
+			Expect(subscription.Events).To(Receive())
+		}
+		Expect(subscription.Events).To(BeClosed())
+	})
+
+	Context("reconnecting", func() {
+
+		var lastEventID string
+
+		BeforeEach(func() {
+			Expect(bus.Publish("carShare1", Created, trip)).To(Succeed())
+			var event Event
+			Expect(subscription.Events).To(Receive(&event))
+			lastEventID = event.EventID()
+			subscription.Close()
+			Expect(bus.Publish("carShare1", Updated, trip)).To(Succeed())
+			Expect(bus.Publish("carShare2", Created, trip)).To(Succeed())
+		})
+
+		It("should replay the events missed since the last one seen", func() {
+			subscription = bus.Subscribe("carShare1", lastEventID)
+			Expect(subscription.Replay).To(HaveLen(1))
+			Expect(subscription.Replay[0].Type).To(Equal("trips.updated"))
+		})
+
+		It("should reset once the missed events are no longer kept", func() {
+			Expect(bus.Publish("carShare1", Deleted, trip)).To(Succeed())
+			Expect(bus.Publish("carShare1", Created, trip)).To(Succeed())
+			subscription = bus.Subscribe("carShare1", lastEventID)
+			Expect(subscription.Replay).To(HaveLen(1))
+			Expect(subscription.Replay[0].Type).To(Equal(Reset))
+		})
+
+		It("should reset for events from before the bus started", func() {
+			id, err := strconv.ParseUint(lastEventID, 10, 64)
+			Expect(err).ToNot(HaveOccurred())
+			subscription = bus.Subscribe("carShare1", strconv.FormatUint(id-10, 10))
+			Expect(subscription.Replay[0].Type).To(Equal(Reset))
+		})
+
+		It("should reset for an ID that isn't one of ours", func() {
+			subscription = bus.Subscribe("carShare1", "not an id")
+			Expect(subscription.Replay[0].Type).To(Equal(Reset))
+		})
+
+	})
+
+})
@@ -0,0 +1,7 @@
+package events
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("events")
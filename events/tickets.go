@@ -0,0 +1,96 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+// DefaultTicketTTL is how long a ticket can be redeemed for once issued
+const DefaultTicketTTL = time.Minute
+
+// ticketBytes is the number of random bytes in the ID of a ticket
+const ticketBytes = 32
+
+// Ticket lets a member open one stream of a car share. Browsers can't set headers on event
+// streams or WebSockets, so they are given a ticket in the URL in place of their token.
+type Ticket struct {
+	ID         string    `json:"-"`
+	CarShareID string    `json:"-"`
+	UserID     string    `json:"-"`
+	Expires    time.Time `json:"expires"`
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (t Ticket) GetID() string {
+	return t.ID
+}
+
+// Tickets issued to members of car shares, each redeemable once before it expires. Tickets
+// are kept in memory, so they don't survive a restart.
+type Tickets struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	clock   clock.Clock
+	tickets map[string]Ticket
+}
+
+// NewTickets creates a store of tickets that expire after the given time
+func NewTickets(ttl time.Duration, clk clock.Clock) *Tickets {
+	return &Tickets{
+		ttl:     ttl,
+		clock:   clk,
+		tickets: make(map[string]Ticket),
+	}
+}
+
+// Issue a ticket for the user to stream the car share
+func (t *Tickets) Issue(carShareID, userID string) (Ticket, error) {
+
+	id := make([]byte, ticketBytes)
+	_, err := rand.Read(id)
+	if err != nil {
+		return Ticket{}, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	for key, ticket := range t.tickets {
+		if !now.Before(ticket.Expires) {
+			delete(t.tickets, key)
+		}
+	}
+
+	ticket := Ticket{
+		ID:         hex.EncodeToString(id),
+		CarShareID: carShareID,
+		UserID:     userID,
+		Expires:    now.Add(t.ttl),
+	}
+	t.tickets[ticket.ID] = ticket
+	return ticket, nil
+}
+
+// Redeem a ticket to stream the car share, returning the user it was issued to. A ticket
+// can't be redeemed again, whether or not it was for the car share.
+func (t *Tickets) Redeem(id, carShareID string) (userID string, ok bool) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ticket, ok := t.tickets[id]
+	if !ok {
+		return "", false
+	}
+	delete(t.tickets, id)
+
+	if ticket.CarShareID != carShareID || !t.clock.Now().Before(ticket.Expires) {
+		return "", false
+	}
+	return ticket.UserID, true
+}
@@ -0,0 +1,75 @@
+package events
+
+import (
+	"time"
+
+	"github.com/benbjohnson/clock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Tickets", func() {
+
+	var (
+		mockClock *clock.Mock
+		tickets   *Tickets
+		ticket    Ticket
+	)
+
+	BeforeEach(func() {
+		mockClock = clock.NewMock()
+		mockClock.Set(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC))
+		tickets = NewTickets(DefaultTicketTTL, mockClock)
+		var err error
+		ticket, err = tickets.Issue("carShare1", "user1")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should issue unguessable tickets that expire", func() {
+		Expect(ticket.ID).To(HaveLen(2 * ticketBytes))
+		Expect(ticket.Expires).To(Equal(mockClock.Now().Add(DefaultTicketTTL)))
+		other, err := tickets.Issue("carShare1", "user1")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(other.ID).ToNot(Equal(ticket.ID))
+	})
+
+	It("should redeem a ticket for the user it was issued to", func() {
+		userID, ok := tickets.Redeem(ticket.ID, "carShare1")
+		Expect(ok).To(BeTrue())
+		Expect(userID).To(Equal("user1"))
+	})
+
+	It("should only redeem a ticket once", func() {
+		tickets.Redeem(ticket.ID, "carShare1")
+		_, ok := tickets.Redeem(ticket.ID, "carShare1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not redeem a ticket for another car share", func() {
+		_, ok := tickets.Redeem(ticket.ID, "carShare2")
+		Expect(ok).To(BeFalse())
+		_, ok = tickets.Redeem(ticket.ID, "carShare1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not redeem an expired ticket", func() {
+		mockClock.Add(DefaultTicketTTL)
+		_, ok := tickets.Redeem(ticket.ID, "carShare1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should not redeem a ticket that wasn't issued", func() {
+		_, ok := tickets.Redeem("not-a-ticket", "carShare1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should forget expired tickets", func() {
+		mockClock.Add(DefaultTicketTTL)
+		_, err := tickets.Issue("carShare1", "user2")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(tickets.tickets).To(HaveLen(1))
+		Expect(tickets.tickets).ToNot(HaveKey(ticket.ID))
+	})
+
+})
@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// writeCSV writes one row per trip, oldest first. Each member and admin gets a pair
+// of columns holding their running score as of that trip.
+func (e CarShareExporter) writeCSV(carShare model.CarShare, users []model.User, w io.Writer, ctx api2go.APIContexter) error {
+
+	names := make(map[string]string)
+	header := []string{"trip", "timestamp", "metres", "driver", "passengers"}
+	for _, user := range users {
+		names[user.GetID()] = displayName(user)
+		header = append(header,
+			displayName(user)+" metres as driver",
+			displayName(user)+" metres as passenger",
+		)
+	}
+
+	// users who are no longer members can still appear on old trips
+	nameOf := func(userID string) string {
+		name, ok := names[userID]
+		if !ok {
+			return userID
+		}
+		return name
+	}
+
+	out := csv.NewWriter(w)
+	err := out.Write(header)
+	if err != nil {
+		return err
+	}
+
+	err = e.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		passengers := []string{}
+		for _, passengerID := range trip.PassengerIDs {
+			passengers = append(passengers, nameOf(passengerID))
+		}
+		driver := ""
+		if trip.DriverID != "" {
+			driver = nameOf(trip.DriverID)
+		}
+		row := []string{
+			trip.GetID(),
+			trip.TimeStamp.UTC().Format(time.RFC3339),
+			strconv.Itoa(trip.Metres),
+			driver,
+			strings.Join(passengers, "; "),
+		}
+		for _, user := range users {
+			score := trip.Scores[user.GetID()]
+			row = append(row,
+				strconv.Itoa(score.MetresAsDriver),
+				strconv.Itoa(score.MetresAsPassenger),
+			)
+		}
+		return out.Write(row)
+	}, ctx)
+	if err != nil {
+		return err
+	}
+
+	out.Flush()
+	return out.Error()
+}
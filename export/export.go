@@ -0,0 +1,141 @@
+/*
+Package export writes complete archives of a car share so that members can take
+their history elsewhere.
+*/
+package export
+
+import (
+	"errors"
+	"io"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// Format of a car share archive
+type Format string
+
+const (
+	// JSON archive as a {json:api} compatible document
+	JSON Format = "json"
+
+	// CSV archive with one row per trip, suitable for spreadsheets
+	CSV Format = "csv"
+)
+
+// ErrUnknownFormat indicates that the requested archive format is not supported
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// ParseFormat converts a format name (e.g. from a query parameter) into a Format.
+// An empty name defaults to JSON.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", JSON:
+		return JSON, nil
+	case CSV:
+		return CSV, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// ContentType to use when serving an archive in this format over HTTP
+func (f Format) ContentType() string {
+	if f == CSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/vnd.api+json"
+}
+
+// CarShareExporter writes complete archives of a car share
+type CarShareExporter struct {
+	TripStorage storage.TripStorage
+	UserStorage storage.UserStorage
+}
+
+// Export writes an archive of the car share to w. Members and admins are looked
+// up up-front, trips are streamed from trip storage one at a time.
+func (e CarShareExporter) Export(carShare model.CarShare, format Format, w io.Writer, ctx api2go.APIContexter) error {
+	archive, err := e.Prepare(carShare, format, ctx)
+	if err != nil {
+		return err
+	}
+	return archive.Write(w, ctx)
+}
+
+// Archive of a car share, ready to be written
+type Archive struct {
+	exporter CarShareExporter
+	carShare model.CarShare
+	format   Format
+	users    []model.User
+}
+
+// errTripsReadable stops the check that a car share's trips can be read at its first trip
+var errTripsReadable = errors.New("trips can be read")
+
+// Prepare an archive of the car share, looking up its members and admins and checking its
+// trips can be read. Callers answering requests can still report failures until the
+// archive is written.
+func (e CarShareExporter) Prepare(carShare model.CarShare, format Format, ctx api2go.APIContexter) (Archive, error) {
+
+	if format != JSON && format != CSV {
+		return Archive{}, ErrUnknownFormat
+	}
+
+	users, err := e.users(carShare, ctx)
+	if err != nil {
+		return Archive{}, err
+	}
+
+	err = e.TripStorage.Iterate(carShare.GetID(), func(model.Trip) error {
+		return errTripsReadable
+	}, ctx)
+	if err != nil && err != errTripsReadable {
+		return Archive{}, err
+	}
+
+	return Archive{
+		exporter: e,
+		carShare: carShare,
+		format:   format,
+		users:    users,
+	}, nil
+}
+
+// Write the archive to w, streaming trips from trip storage one at a time
+func (a Archive) Write(w io.Writer, ctx api2go.APIContexter) error {
+	switch a.format {
+	case JSON:
+		return a.exporter.writeJSON(a.carShare, a.users, w, ctx)
+	default:
+		return a.exporter.writeCSV(a.carShare, a.users, w, ctx)
+	}
+}
+
+// users returns the members and admins of a car share, without duplicates
+func (e CarShareExporter) users(carShare model.CarShare, ctx api2go.APIContexter) ([]model.User, error) {
+	result := []model.User{}
+	seen := make(map[string]bool)
+	for _, userID := range append(append([]string{}, carShare.MemberIDs...), carShare.AdminIDs...) {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+		user, err := e.UserStorage.GetOne(userID, ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+// displayName of a user, falling back to their id for users without one
+func displayName(user model.User) string {
+	if user.DisplayName != "" {
+		return user.DisplayName
+	}
+	return user.GetID()
+}
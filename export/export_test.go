@@ -0,0 +1,246 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// unreadableTripStorage fails to iterate over trips
+type unreadableTripStorage struct {
+	*memory.TripStorage
+}
+
+func (s unreadableTripStorage) Iterate(carShareID string, fn func(model.Trip) error, context api2go.APIContexter) error {
+	return errors.New("trips unavailable")
+}
+
+var _ = Describe("Car Share Exporter", func() {
+
+	var (
+		exporter    CarShareExporter
+		context     *api2go.APIContext
+		carShare    model.CarShare
+		tripStorage *memory.TripStorage
+		userStorage *memory.UserStorage
+		driverID    string
+		passengerID string
+		out         *bytes.Buffer
+		err         error
+	)
+
+	BeforeEach(func() {
+		context = &api2go.APIContext{}
+		tripStorage = memory.NewTripStorage()
+		userStorage = memory.NewUserStorage()
+		exporter = CarShareExporter{
+			TripStorage: tripStorage,
+			UserStorage: userStorage,
+		}
+
+		driverID, err = userStorage.Insert(model.User{DisplayName: "Driver"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		passengerID, err = userStorage.Insert(model.User{DisplayName: "Passenger"}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		carShare = model.CarShare{Name: "Commute"}
+		carShare.SetID("58a7b3b4e4b0e1b5c0a1a001")
+		carShare.MemberIDs = []string{driverID, passengerID}
+		carShare.AdminIDs = []string{driverID}
+
+		// inserted newest first to check trips come out oldest first
+		for _, metres := range []int{2000, 1000} {
+			trip := model.Trip{
+				Metres:       metres,
+				TimeStamp:    time.Date(2017, 11, metres/1000, 8, 0, 0, 0, time.UTC),
+				CarShareID:   carShare.GetID(),
+				DriverID:     driverID,
+				PassengerIDs: []string{passengerID},
+				Scores: map[string]model.Score{
+					driverID:    {MetresAsDriver: metres},
+					passengerID: {MetresAsPassenger: metres},
+				},
+			}
+			id, err := tripStorage.Insert(trip, context)
+			Expect(err).ToNot(HaveOccurred())
+			carShare.TripIDs = append(carShare.TripIDs, id)
+		}
+
+		out = &bytes.Buffer{}
+	})
+
+	Describe("json", func() {
+
+		var document struct {
+			Data struct {
+				Type          string                     `json:"type"`
+				Relationships map[string]json.RawMessage `json:"relationships"`
+			} `json:"data"`
+			Included []struct {
+				Type       string                 `json:"type"`
+				ID         string                 `json:"id"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"included"`
+		}
+
+		BeforeEach(func() {
+			err = exporter.Export(carShare, JSON, out, context)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should produce a valid {json:api} document", func() {
+			Expect(json.Unmarshal(out.Bytes(), &document)).To(Succeed())
+			Expect(document.Data.Type).To(Equal("carShares"))
+			Expect(document.Data.Relationships).To(HaveKey("members"))
+			Expect(document.Data.Relationships).To(HaveKey("trips"))
+		})
+
+		It("should include each user once followed by every trip", func() {
+			Expect(json.Unmarshal(out.Bytes(), &document)).To(Succeed())
+			Expect(document.Included).To(HaveLen(4))
+			Expect(document.Included[0].Type).To(Equal("users"))
+			Expect(document.Included[1].Type).To(Equal("users"))
+			Expect(document.Included[2].Type).To(Equal("trips"))
+			Expect(document.Included[2].Attributes["metres"]).To(BeEquivalentTo(1000))
+			Expect(document.Included[3].Attributes["metres"]).To(BeEquivalentTo(2000))
+		})
+
+	})
+
+	Describe("csv", func() {
+
+		var rows [][]string
+
+		BeforeEach(func() {
+			err = exporter.Export(carShare, CSV, out, context)
+			Expect(err).ToNot(HaveOccurred())
+			rows, err = csv.NewReader(out).ReadAll()
+		})
+
+		It("should produce valid csv", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should have a header and one row per trip", func() {
+			Expect(rows).To(HaveLen(3))
+			Expect(rows[0]).To(Equal([]string{
+				"trip", "timestamp", "metres", "driver", "passengers",
+				"Driver metres as driver", "Driver metres as passenger",
+				"Passenger metres as driver", "Passenger metres as passenger",
+			}))
+		})
+
+		It("should list trips oldest first with driver and passenger names", func() {
+			Expect(rows[1][1:]).To(Equal([]string{"2017-11-01T08:00:00Z", "1000", "Driver", "Passenger", "1000", "0", "0", "1000"}))
+			Expect(rows[2][2]).To(Equal("2000"))
+		})
+
+	})
+
+	Describe("unknown format", func() {
+
+		BeforeEach(func() {
+			err = exporter.Export(carShare, Format("xml"), out, context)
+		})
+
+		It("should throw an ErrUnknownFormat error", func() {
+			Expect(err).To(Equal(ErrUnknownFormat))
+		})
+
+	})
+
+	Describe("missing member", func() {
+
+		BeforeEach(func() {
+			carShare.MemberIDs = append(carShare.MemberIDs, "58a7b3b4e4b0e1b5c0a1a0ff")
+			err = exporter.Export(carShare, JSON, out, context)
+		})
+
+		It("should throw an error", func() {
+			Expect(err).To(HaveOccurred())
+		})
+
+	})
+
+	Describe("prepare", func() {
+
+		var archive Archive
+
+		Context("with readable trips", func() {
+
+			BeforeEach(func() {
+				archive, err = exporter.Prepare(carShare, CSV, context)
+			})
+
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should not write anything", func() {
+				Expect(out.Len()).To(BeZero())
+			})
+
+			It("should write the same archive as export", func() {
+				Expect(archive.Write(out, context)).To(Succeed())
+				expected := &bytes.Buffer{}
+				Expect(exporter.Export(carShare, CSV, expected, context)).To(Succeed())
+				Expect(out.String()).To(Equal(expected.String()))
+			})
+
+		})
+
+		Context("with a missing member", func() {
+
+			BeforeEach(func() {
+				carShare.MemberIDs = append(carShare.MemberIDs, "58a7b3b4e4b0e1b5c0a1a0ff")
+				_, err = exporter.Prepare(carShare, JSON, context)
+			})
+
+			It("should throw an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+
+		})
+
+		Context("with unreadable trips", func() {
+
+			BeforeEach(func() {
+				exporter.TripStorage = unreadableTripStorage{tripStorage}
+				_, err = exporter.Prepare(carShare, JSON, context)
+			})
+
+			It("should throw an error", func() {
+				Expect(err).To(MatchError("trips unavailable"))
+			})
+
+		})
+
+		Context("with no trips", func() {
+
+			BeforeEach(func() {
+				carShare.SetID("58a7b3b4e4b0e1b5c0a1a002")
+				_, err = exporter.Prepare(carShare, JSON, context)
+			})
+
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+		})
+
+	})
+
+})
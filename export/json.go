@@ -0,0 +1,86 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// writeJSON writes the car share as the primary data of a {json:api} document,
+// with its users and trips as included resources
+func (e CarShareExporter) writeJSON(carShare model.CarShare, users []model.User, w io.Writer, ctx api2go.APIContexter) error {
+
+	byID := make(map[string]*model.User)
+	for i := range users {
+		byID[users[i].GetID()] = &users[i]
+	}
+	carShare.Members = nil
+	for _, memberID := range carShare.MemberIDs {
+		carShare.Members = append(carShare.Members, byID[memberID])
+	}
+	carShare.Admins = nil
+	for _, adminID := range carShare.AdminIDs {
+		carShare.Admins = append(carShare.Admins, byID[adminID])
+	}
+	// trips are only needed for their ids here, the full trips are streamed below
+	carShare.Trips = nil
+	for _, tripID := range carShare.TripIDs {
+		trip := model.Trip{}
+		trip.SetID(tripID)
+		carShare.Trips = append(carShare.Trips, trip)
+	}
+
+	data, err := marshalData(carShare)
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteString(`{"data":`)
+	out.Write(data)
+	out.WriteString(`,"included":[`)
+
+	first := true
+	writeIncluded := func(element jsonapi.MarshalIdentifier) error {
+		data, err := marshalData(element)
+		if err != nil {
+			return err
+		}
+		if !first {
+			out.WriteString(",")
+		}
+		first = false
+		_, err = out.Write(data)
+		return err
+	}
+
+	for _, user := range users {
+		err = writeIncluded(user)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = e.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		return writeIncluded(trip)
+	}, ctx)
+	if err != nil {
+		return err
+	}
+
+	out.WriteString("]}\n")
+	return out.Flush()
+}
+
+// marshalData returns the {json:api} resource object for a single element
+func marshalData(element jsonapi.MarshalIdentifier) ([]byte, error) {
+	document, err := jsonapi.MarshalToStruct(element, nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document.Data)
+}
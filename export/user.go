@@ -0,0 +1,81 @@
+package export
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// UserExporter writes archives of everything stored about a user, across every car share they
+// belong to
+type UserExporter struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+}
+
+// Export writes the user, including their private details, as the primary data of a {json:api}
+// document to w. The car shares they are a member of and every trip they drove or were a
+// passenger on are included, each trip only carrying the user's own score.
+func (e UserExporter) Export(user model.User, w io.Writer, ctx api2go.APIContexter) error {
+
+	carShares, err := e.CarShareStorage.GetAll(user.GetID(), ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := marshalData(user.Private())
+	if err != nil {
+		return err
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteString(`{"data":`)
+	out.Write(data)
+	out.WriteString(`,"included":[`)
+
+	first := true
+	writeIncluded := func(element jsonapi.MarshalIdentifier) error {
+		data, err := marshalData(element)
+		if err != nil {
+			return err
+		}
+		if !first {
+			out.WriteString(",")
+		}
+		first = false
+		_, err = out.Write(data)
+		return err
+	}
+
+	for _, carShare := range carShares {
+		err = writeIncluded(carShare)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, carShare := range carShares {
+		err = e.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+			if !trip.IsParticipant(user.GetID()) {
+				return nil
+			}
+			// other members' scores are theirs, not the user's
+			score, scored := trip.Scores[user.GetID()]
+			trip.Scores = make(map[string]model.Score)
+			if scored {
+				trip.Scores[user.GetID()] = score
+			}
+			return writeIncluded(trip)
+		}, ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	out.WriteString("]}\n")
+	return out.Flush()
+}
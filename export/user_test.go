@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("User Exporter", func() {
+
+	var (
+		exporter UserExporter
+		context  *api2go.APIContext
+		user     model.User
+		otherID  string
+		out      *bytes.Buffer
+		err      error
+		document struct {
+			Data struct {
+				Type       string                 `json:"type"`
+				ID         string                 `json:"id"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"data"`
+			Included []struct {
+				Type       string                 `json:"type"`
+				ID         string                 `json:"id"`
+				Attributes map[string]interface{} `json:"attributes"`
+			} `json:"included"`
+		}
+	)
+
+	BeforeEach(func() {
+		context = &api2go.APIContext{}
+		carShareStorage := memory.NewCarShareStorage()
+		tripStorage := memory.NewTripStorage()
+		userStorage := memory.NewUserStorage()
+		exporter = UserExporter{
+			CarShareStorage: carShareStorage,
+			TripStorage:     tripStorage,
+		}
+
+		user = model.User{DisplayName: "Sam", Email: "sam@example.com", FirebaseUID: "samFirebaseUID"}
+		userID, err := userStorage.Insert(user, context)
+		Expect(err).ToNot(HaveOccurred())
+		user.SetID(userID)
+		otherID, err = userStorage.Insert(model.User{DisplayName: "Alex"}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		for _, name := range []string{"Commute", "Not theirs"} {
+			carShare := model.CarShare{Name: name, MemberIDs: []string{otherID}}
+			if name == "Commute" {
+				carShare.MemberIDs = append(carShare.MemberIDs, userID)
+			}
+			carShareID, err := carShareStorage.Insert(carShare, context)
+			Expect(err).ToNot(HaveOccurred())
+
+			for day, trip := range []model.Trip{
+				{Metres: 1000, DriverID: userID, PassengerIDs: []string{otherID}},
+				{Metres: 2000, DriverID: otherID},
+			} {
+				trip.CarShareID = carShareID
+				trip.TimeStamp = time.Date(2017, 11, day+1, 8, 0, 0, 0, time.UTC)
+				trip.Scores = map[string]model.Score{
+					userID:  {MetresAsDriver: 1000},
+					otherID: {MetresAsPassenger: 1000},
+				}
+				_, err := tripStorage.Insert(trip, context)
+				Expect(err).ToNot(HaveOccurred())
+			}
+		}
+
+		out = &bytes.Buffer{}
+		err = exporter.Export(user, out, context)
+	})
+
+	It("should not throw an error", func() {
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should have the user with their private details as its data", func() {
+		Expect(json.Unmarshal(out.Bytes(), &document)).To(Succeed())
+		Expect(document.Data.Type).To(Equal("users"))
+		Expect(document.Data.ID).To(Equal(user.GetID()))
+		Expect(document.Data.Attributes["email"]).To(Equal("sam@example.com"))
+		Expect(document.Data.Attributes["firebase-uid"]).To(Equal("samFirebaseUID"))
+	})
+
+	It("should include only their car shares and the trips they were on", func() {
+		Expect(json.Unmarshal(out.Bytes(), &document)).To(Succeed())
+		Expect(document.Included).To(HaveLen(2))
+		Expect(document.Included[0].Type).To(Equal("carShares"))
+		Expect(document.Included[0].Attributes["name"]).To(Equal("Commute"))
+		Expect(document.Included[1].Type).To(Equal("trips"))
+		Expect(document.Included[1].Attributes["metres"]).To(BeEquivalentTo(1000))
+	})
+
+	It("should only include their own scores", func() {
+		Expect(json.Unmarshal(out.Bytes(), &document)).To(Succeed())
+		scores := document.Included[1].Attributes["scores"]
+		Expect(scores).To(HaveKey(user.GetID()))
+		Expect(scores).ToNot(HaveKey(otherID))
+	})
+
+})
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+)
+
+// ErrForbidden indicates the requesting user isn't allowed to see what they asked for
+var ErrForbidden = errors.New("forbidden")
+
+// The rules match those of the resource package: car shares and their trips are only seen by
+// their members, and users by themselves and anyone they share a car share with. Only users
+// looking at themselves see their email.
+
+// carShare the requesting user is a member of
+func (req *request) carShare(ctx context.Context, id string) (model.CarShare, error) {
+	carShare, err := req.loaders.carShare(ctx, id)()
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		return carShare, fmt.Errorf("unable to find car share %s", id)
+	default:
+		return carShare, fmt.Errorf("error retrieving car share %s, %s", id, err)
+	}
+	if !carShare.IsMember(req.requestingUser.GetID()) {
+		return model.CarShare{}, ErrForbidden
+	}
+	return carShare, nil
+}
+
+// trip of a car share the requesting user is a member of
+func (req *request) trip(ctx context.Context, id string) (model.Trip, error) {
+	trip, err := req.loaders.trip(ctx, id)()
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		return trip, fmt.Errorf("unable to find trip %s", id)
+	default:
+		return trip, fmt.Errorf("error retrieving trip %s, %s", id, err)
+	}
+	carShare, err := req.loaders.carShare(ctx, trip.CarShareID)()
+	if err != nil {
+		return model.Trip{}, fmt.Errorf("error retrieving car share %s of trip %s, %s", trip.CarShareID, id, err)
+	}
+	if !carShare.IsMember(req.requestingUser.GetID()) {
+		return model.Trip{}, ErrForbidden
+	}
+	return trip, nil
+}
+
+// user who is either the requesting user or shares a car share with them
+func (req *request) user(ctx context.Context, id string) (model.User, error) {
+	if id == req.requestingUser.GetID() {
+		return req.requestingUser, nil
+	}
+	user, err := req.loaders.user(ctx, id)()
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		return user, fmt.Errorf("unable to find user %s", id)
+	default:
+		return user, fmt.Errorf("error retrieving user %s, %s", id, err)
+	}
+	carShares, err := req.memberCarShares()
+	if err != nil {
+		return model.User{}, err
+	}
+	for _, carShare := range carShares {
+		if carShare.IsMember(user.GetID()) || carShare.GetID() == user.LinkedCarShareID {
+			return user, nil
+		}
+	}
+	return model.User{}, ErrForbidden
+}
+
+// memberCarShares are the car shares the requesting user is a member of
+func (req *request) memberCarShares() ([]model.CarShare, error) {
+	if req.carShares != nil {
+		return req.carShares, nil
+	}
+	carShares, err := req.CarShareStorage.GetAll(req.requestingUser.GetID(), req.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving car shares of user %s, %s", req.requestingUser.GetID(), err)
+	}
+	req.carShares = carShares
+	return carShares, nil
+}
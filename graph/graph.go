@@ -0,0 +1,69 @@
+/*
+Package graph answers GraphQL queries over car shares, their trips, members and scores. Users
+see the same car shares, trips and users through it as they do through the {json:api}
+resources.
+*/
+package graph
+
+import (
+	"context"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/graphql-go/graphql"
+	"github.com/manyminds/api2go"
+)
+
+// Params of a GraphQL request, as posted by clients
+type Params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Executor runs queries against the stores on behalf of a user
+type Executor struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+}
+
+// request being resolved, threaded through to the resolvers in the context
+type request struct {
+	Executor
+	requestingUser model.User
+	ctx            api2go.APIContexter
+	loaders        loaders
+
+	// car shares of the requesting user, once they are needed
+	carShares []model.CarShare
+}
+
+type requestKey struct{}
+
+// requestFrom the context of a resolver
+func requestFrom(ctx context.Context) *request {
+	return ctx.Value(requestKey{}).(*request)
+}
+
+// Do runs a query as the requesting user. Problems resolving parts of the query are reported
+// in the errors of the result alongside whatever could be resolved.
+func (e Executor) Do(requestingUser model.User, params Params, ctx api2go.APIContexter) *graphql.Result {
+	req := &request{
+		Executor:       e,
+		requestingUser: requestingUser,
+		ctx:            ctx,
+		loaders:        newLoaders(e.CarShareStorage, e.TripStorage, e.UserStorage, ctx),
+	}
+	result := graphql.Do(graphql.Params{
+		Schema:         schema,
+		RequestString:  params.Query,
+		OperationName:  params.OperationName,
+		VariableValues: params.Variables,
+		Context:        context.WithValue(context.Background(), requestKey{}, req),
+	})
+	for _, err := range result.Errors {
+		log.Infof("error resolving query for user %s, %s", requestingUser.GetID(), err.Message)
+	}
+	return result
+}
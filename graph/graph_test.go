@@ -0,0 +1,298 @@
+package graph
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+	"github.com/graphql-go/graphql"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// countingUserStorage counts how many times each user is retrieved
+type countingUserStorage struct {
+	*memory.UserStorage
+	mutex sync.Mutex
+	gets  map[string]int
+}
+
+func (s *countingUserStorage) GetOne(id string, context api2go.APIContexter) (model.User, error) {
+	s.mutex.Lock()
+	s.gets[id]++
+	s.mutex.Unlock()
+	return s.UserStorage.GetOne(id, context)
+}
+
+var _ = Describe("Executor", func() {
+
+	var (
+		executor        Executor
+		context         *api2go.APIContext
+		carShareStorage *memory.CarShareStorage
+		tripStorage     *memory.TripStorage
+		userStorage     *countingUserStorage
+		alice           model.User
+		bobID           string
+		carolID         string
+		commuteID       string
+		schoolRunID     string
+		tripIDs         []string
+		result          *graphql.Result
+	)
+
+	query := func(user model.User, query string, variables map[string]interface{}) {
+		result = executor.Do(user, Params{Query: query, Variables: variables}, context)
+	}
+
+	data := func() string {
+		document, err := json.Marshal(result.Data)
+		Expect(err).ToNot(HaveOccurred())
+		return string(document)
+	}
+
+	BeforeEach(func() {
+		context = &api2go.APIContext{}
+		carShareStorage = memory.NewCarShareStorage()
+		tripStorage = memory.NewTripStorage()
+		userStorage = &countingUserStorage{UserStorage: memory.NewUserStorage(), gets: make(map[string]int)}
+		executor = Executor{
+			CarShareStorage: carShareStorage,
+			TripStorage:     tripStorage,
+			UserStorage:     userStorage,
+		}
+
+		alice = model.User{DisplayName: "Alice", Email: "alice@example.com"}
+		aliceID, err := userStorage.Insert(alice, context)
+		Expect(err).ToNot(HaveOccurred())
+		alice.SetID(aliceID)
+		bobID, err = userStorage.Insert(model.User{DisplayName: "Bob", Email: "bob@example.com"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		carolID, err = userStorage.Insert(model.User{DisplayName: "Carol"}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		commute := model.CarShare{
+			Name:      "Commute",
+			AdminIDs:  []string{aliceID},
+			MemberIDs: []string{aliceID, bobID},
+		}
+		commuteID, err = carShareStorage.Insert(commute, context)
+		Expect(err).ToNot(HaveOccurred())
+		schoolRunID, err = carShareStorage.Insert(model.CarShare{Name: "School run", MemberIDs: []string{carolID}}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		tripIDs = nil
+		for day := 6; day <= 8; day++ {
+			id, err := tripStorage.Insert(model.Trip{
+				CarShareID:   commuteID,
+				TimeStamp:    time.Date(2017, time.March, day, 8, 30, 0, 0, time.UTC),
+				Metres:       16093,
+				Status:       model.TripConfirmed,
+				DriverID:     aliceID,
+				PassengerIDs: []string{bobID},
+				Scores: map[string]model.Score{
+					aliceID: {MetresAsDriver: 16093 * (day - 5), PointsAsDriver: 16093 * (day - 5)},
+					bobID:   {MetresAsPassenger: 16093 * (day - 5), PointsAsPassenger: 16093 * (day - 5)},
+				},
+			}, context)
+			Expect(err).ToNot(HaveOccurred())
+			tripIDs = append(tripIDs, id)
+		}
+		commute, err = carShareStorage.GetOne(commuteID, context)
+		Expect(err).ToNot(HaveOccurred())
+		commute.TripIDs = tripIDs
+		Expect(carShareStorage.Update(commute, context)).To(Succeed())
+	})
+
+	Describe("the requesting user", func() {
+
+		BeforeEach(func() {
+			query(alice, `{ me { displayName email carShares { name } } }`, nil)
+		})
+
+		It("should see themselves, email and all", func() {
+			Expect(result.Errors).To(BeEmpty())
+			Expect(data()).To(MatchJSON(`{"me": {"displayName": "Alice", "email": "alice@example.com", "carShares": [{"name": "Commute"}]}}`))
+		})
+
+	})
+
+	Describe("a car share", func() {
+
+		const carShareQuery = `query($id: ID!, $after: String) {
+			carShare(id: $id) {
+				name
+				unit
+				admins { displayName }
+				members { displayName email }
+				trips(first: 2, after: $after) {
+					totalCount
+					edges {
+						cursor
+						node { distance driver { displayName } passengers { displayName } }
+					}
+					pageInfo { hasNextPage endCursor }
+				}
+				scores { user { displayName } pointsAsDriver pointsAsPassenger }
+			}
+		}`
+
+		Context("the requesting user is a member of", func() {
+
+			BeforeEach(func() {
+				query(alice, carShareQuery, map[string]interface{}{"id": commuteID})
+			})
+
+			It("should be resolved with its members and the latest page of trips", func() {
+				Expect(result.Errors).To(BeEmpty())
+				Expect(data()).To(MatchJSON(`{"carShare": {
+					"name": "Commute",
+					"unit": "km",
+					"admins": [{"displayName": "Alice"}],
+					"members": [{"displayName": "Alice", "email": "alice@example.com"}, {"displayName": "Bob", "email": null}],
+					"trips": {
+						"totalCount": 3,
+						"edges": [
+							{"cursor": "` + tripIDs[2] + `", "node": {"distance": 16.093, "driver": {"displayName": "Alice"}, "passengers": [{"displayName": "Bob"}]}},
+							{"cursor": "` + tripIDs[1] + `", "node": {"distance": 16.093, "driver": {"displayName": "Alice"}, "passengers": [{"displayName": "Bob"}]}}
+						],
+						"pageInfo": {"hasNextPage": true, "endCursor": "` + tripIDs[1] + `"}
+					},
+					"scores": [
+						{"user": {"displayName": "Alice"}, "pointsAsDriver": 48279, "pointsAsPassenger": 0},
+						{"user": {"displayName": "Bob"}, "pointsAsDriver": 0, "pointsAsPassenger": 48279}
+					]
+				}}`))
+			})
+
+			It("should only retrieve each user once", func() {
+				Expect(userStorage.gets[bobID]).To(Equal(1))
+			})
+
+		})
+
+		Context("paged through", func() {
+
+			BeforeEach(func() {
+				query(alice, carShareQuery, map[string]interface{}{"id": commuteID, "after": tripIDs[1]})
+			})
+
+			It("should continue from the cursor", func() {
+				Expect(result.Errors).To(BeEmpty())
+				trips := result.Data.(map[string]interface{})["carShare"].(map[string]interface{})["trips"].(map[string]interface{})
+				Expect(trips["edges"]).To(HaveLen(1))
+				Expect(trips["pageInfo"]).To(Equal(map[string]interface{}{"hasNextPage": false, "endCursor": tripIDs[0]}))
+			})
+
+		})
+
+		Context("with a guest among the scores", func() {
+
+			BeforeEach(func() {
+				trip, err := tripStorage.GetOne(tripIDs[2], context)
+				Expect(err).ToNot(HaveOccurred())
+				trip.Scores[model.Guest{Name: "Visitor"}.Key()] = model.Score{MetresAsPassenger: 16093, PointsAsPassenger: 16093}
+				Expect(tripStorage.Update(trip, context)).To(Succeed())
+				query(alice, `query($id: ID!) { carShare(id: $id) { scores { user { displayName } } } }`, map[string]interface{}{"id": commuteID})
+			})
+
+			It("should only give the scores of members", func() {
+				Expect(result.Errors).To(BeEmpty())
+				Expect(data()).To(MatchJSON(`{"carShare": {"scores": [{"user": {"displayName": "Alice"}}, {"user": {"displayName": "Bob"}}]}}`))
+			})
+
+		})
+
+		Context("the requesting user isn't a member of", func() {
+
+			BeforeEach(func() {
+				query(alice, carShareQuery, map[string]interface{}{"id": schoolRunID})
+			})
+
+			It("should be forbidden", func() {
+				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Message).To(Equal(ErrForbidden.Error()))
+				Expect(data()).To(MatchJSON(`{"carShare": null}`))
+			})
+
+		})
+
+		Context("that doesn't exist", func() {
+
+			BeforeEach(func() {
+				query(alice, carShareQuery, map[string]interface{}{"id": "missing"})
+			})
+
+			It("should not be found", func() {
+				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Message).To(Equal("unable to find car share missing"))
+			})
+
+		})
+
+		Context("paged through too many trips at a time", func() {
+
+			BeforeEach(func() {
+				query(alice, `query($id: ID!) { carShare(id: $id) { trips(first: 101) { totalCount } } }`, map[string]interface{}{"id": commuteID})
+			})
+
+			It("should be refused", func() {
+				Expect(result.Errors).To(HaveLen(1))
+				Expect(result.Errors[0].Message).To(Equal("first must be between 1 and 100"))
+			})
+
+		})
+
+	})
+
+	Describe("a trip", func() {
+
+		const tripQuery = `query($id: ID!) { trip(id: $id) { status distance(unit: "miles") carShare { name } scores { metresAsPassenger } } }`
+
+		It("should be seen by members of its car share", func() {
+			query(alice, tripQuery, map[string]interface{}{"id": tripIDs[0]})
+			Expect(result.Errors).To(BeEmpty())
+			trip := result.Data.(map[string]interface{})["trip"].(map[string]interface{})
+			Expect(trip["distance"]).To(BeNumerically("~", 10, 0.001))
+			delete(trip, "distance")
+			Expect(data()).To(MatchJSON(`{"trip": {
+				"status": "confirmed",
+				"carShare": {"name": "Commute"},
+				"scores": [{"metresAsPassenger": 0}, {"metresAsPassenger": 16093}]
+			}}`))
+		})
+
+		It("should be forbidden to anyone else", func() {
+			carol, err := userStorage.GetOne(carolID, context)
+			Expect(err).ToNot(HaveOccurred())
+			query(carol, tripQuery, map[string]interface{}{"id": tripIDs[0]})
+			Expect(result.Errors).To(HaveLen(1))
+			Expect(result.Errors[0].Message).To(Equal(ErrForbidden.Error()))
+		})
+
+	})
+
+	Describe("another user", func() {
+
+		const userQuery = `query($id: ID!) { user(id: $id) { displayName email } }`
+
+		It("should be seen without their email by those they share a car share with", func() {
+			query(alice, userQuery, map[string]interface{}{"id": bobID})
+			Expect(result.Errors).To(BeEmpty())
+			Expect(data()).To(MatchJSON(`{"user": {"displayName": "Bob", "email": null}}`))
+		})
+
+		It("should be forbidden to anyone else", func() {
+			query(alice, userQuery, map[string]interface{}{"id": carolID})
+			Expect(result.Errors).To(HaveLen(1))
+			Expect(result.Errors[0].Message).To(Equal(ErrForbidden.Error()))
+		})
+
+	})
+
+})
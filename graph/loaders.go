@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/graph-gophers/dataloader"
+	"github.com/manyminds/api2go"
+)
+
+// loaders batch and cache the users, car shares and trips a query refers to for the length of
+// one request, so a user who appears in every trip of a page is only retrieved once
+type loaders struct {
+	users     *dataloader.Loader
+	carShares *dataloader.Loader
+	trips     *dataloader.Loader
+}
+
+// newLoaders over the stores, retrieving with the context of the request
+func newLoaders(carShareStorage storage.CarShareStorage, tripStorage storage.TripStorage, userStorage storage.UserStorage, ctx api2go.APIContexter) loaders {
+	return loaders{
+		users: dataloader.NewBatchedLoader(batch(func(id string) (interface{}, error) {
+			return userStorage.GetOne(id, ctx)
+		})),
+		carShares: dataloader.NewBatchedLoader(batch(func(id string) (interface{}, error) {
+			return carShareStorage.GetOne(id, ctx)
+		})),
+		trips: dataloader.NewBatchedLoader(batch(func(id string) (interface{}, error) {
+			return tripStorage.GetOne(id, ctx)
+		})),
+	}
+}
+
+// batch function retrieving each of the distinct keys gathered since the last batch. The
+// stores only retrieve by ID, so batching saves repeated rather than separate retrievals.
+func batch(get func(id string) (interface{}, error)) dataloader.BatchFunc {
+	return func(_ context.Context, keys dataloader.Keys) []*dataloader.Result {
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			data, err := get(key.String())
+			results[i] = &dataloader.Result{Data: data, Error: err}
+		}
+		return results
+	}
+}
+
+// user with the given ID, once the batch it is part of has been retrieved
+func (l loaders) user(ctx context.Context, id string) func() (model.User, error) {
+	thunk := l.users.Load(ctx, dataloader.StringKey(id))
+	return func() (model.User, error) {
+		data, err := thunk()
+		if err != nil {
+			return model.User{}, err
+		}
+		return data.(model.User), nil
+	}
+}
+
+// carShare with the given ID, once the batch it is part of has been retrieved
+func (l loaders) carShare(ctx context.Context, id string) func() (model.CarShare, error) {
+	thunk := l.carShares.Load(ctx, dataloader.StringKey(id))
+	return func() (model.CarShare, error) {
+		data, err := thunk()
+		if err != nil {
+			return model.CarShare{}, err
+		}
+		return data.(model.CarShare), nil
+	}
+}
+
+// trip with the given ID, once the batch it is part of has been retrieved
+func (l loaders) trip(ctx context.Context, id string) func() (model.Trip, error) {
+	thunk := l.trips.Load(ctx, dataloader.StringKey(id))
+	return func() (model.Trip, error) {
+		data, err := thunk()
+		if err != nil {
+			return model.Trip{}, err
+		}
+		return data.(model.Trip), nil
+	}
+}
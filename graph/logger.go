@@ -0,0 +1,7 @@
+package graph
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("graph")
@@ -0,0 +1,475 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/graphql-go/graphql"
+)
+
+// Limits on the number of trips in a page
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// score of one user, as listed by car shares and trips
+type score struct {
+	userID string
+	model.Score
+}
+
+// tripPage of a car share's trips, most recently logged first
+type tripPage struct {
+	tripIDs     []string
+	hasNextPage bool
+	totalCount  int
+}
+
+var (
+	userType     *graphql.Object
+	carShareType *graphql.Object
+	tripType     *graphql.Object
+	scoreType    *graphql.Object
+
+	schema graphql.Schema
+)
+
+func init() {
+
+	userType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "A member of car shares",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          userField(graphql.NewNonNull(graphql.ID), func(u model.User) interface{} { return u.GetID() }),
+				"displayName": userField(graphql.String, func(u model.User) interface{} { return u.DisplayName }),
+				"photoURL":    userField(graphql.String, func(u model.User) interface{} { return u.PhotoURL }),
+				"isAnon":      userField(graphql.NewNonNull(graphql.Boolean), func(u model.User) interface{} { return u.IsAnon }),
+				"erased":      userField(graphql.NewNonNull(graphql.Boolean), func(u model.User) interface{} { return u.Erased }),
+				"email": &graphql.Field{
+					Type:        graphql.String,
+					Description: "Only given to users looking at themselves",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						user := p.Source.(model.User)
+						if user.GetID() != requestFrom(p.Context).requestingUser.GetID() {
+							return nil, nil
+						}
+						return user.Email, nil
+					},
+				},
+				"carShares": &graphql.Field{
+					Type:        nonNullList(carShareType),
+					Description: "Car shares of the user that the requesting user is a member of too",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						user := p.Source.(model.User)
+						carShares, err := requestFrom(p.Context).memberCarShares()
+						if err != nil {
+							return nil, err
+						}
+						shared := []model.CarShare{}
+						for _, carShare := range carShares {
+							if carShare.IsMember(user.GetID()) {
+								shared = append(shared, carShare)
+							}
+						}
+						return shared, nil
+					},
+				},
+			}
+		}),
+	})
+
+	scoreType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Score",
+		Description: "Distances travelled by a user as a driver and as a passenger, and the points and contributions they are worth",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"user": &graphql.Field{
+					Type: graphql.NewNonNull(userType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadUser(p.Context, p.Source.(score).userID), nil
+					},
+				},
+				"metresAsDriver":    scoreField(func(s score) int { return s.MetresAsDriver }),
+				"metresAsPassenger": scoreField(func(s score) int { return s.MetresAsPassenger }),
+				"pointsAsDriver":    scoreField(func(s score) int { return s.PointsAsDriver }),
+				"pointsAsPassenger": scoreField(func(s score) int { return s.PointsAsPassenger }),
+				"costAsDriver":      scoreField(func(s score) int { return s.CostAsDriver }),
+				"costAsPassenger":   scoreField(func(s score) int { return s.CostAsPassenger }),
+			}
+		}),
+	})
+
+	tripType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Trip",
+		Description: "A single instance of a car share",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           tripField(graphql.NewNonNull(graphql.ID), func(t model.Trip) interface{} { return t.GetID() }),
+				"metres":       tripField(graphql.NewNonNull(graphql.Int), func(t model.Trip) interface{} { return t.Metres }),
+				"timestamp":    tripField(graphql.NewNonNull(graphql.DateTime), func(t model.Trip) interface{} { return t.TimeStamp }),
+				"cost":         tripField(graphql.NewNonNull(graphql.Int), func(t model.Trip) interface{} { return t.Cost }),
+				"contribution": tripField(graphql.NewNonNull(graphql.Int), func(t model.Trip) interface{} { return t.Contribution }),
+				"status": tripField(graphql.NewNonNull(graphql.String), func(t model.Trip) interface{} {
+					// trips without a status predate them and are treated as confirmed
+					if t.Status == "" {
+						return string(model.TripConfirmed)
+					}
+					return string(t.Status)
+				}),
+				"distance": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.Float),
+					Description: "Distance in the given unit, km or miles, or the car share's unit if none is given",
+					Args: graphql.FieldConfigArgument{
+						"unit": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						trip := p.Source.(model.Trip)
+						if name, ok := p.Args["unit"].(string); ok {
+							unit, err := model.ParseUnit(name)
+							if err != nil {
+								return nil, fmt.Errorf("unit must be km or miles")
+							}
+							return unit.FromMetres(trip.Metres), nil
+						}
+						carShare := requestFrom(p.Context).loaders.carShare(p.Context, trip.CarShareID)
+						return func() (interface{}, error) {
+							carShare, err := carShare()
+							if err != nil {
+								return nil, err
+							}
+							return carShare.PreferredUnit().FromMetres(trip.Metres), nil
+						}, nil
+					},
+				},
+				"carShare": &graphql.Field{
+					Type: graphql.NewNonNull(carShareType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						carShare := requestFrom(p.Context).loaders.carShare(p.Context, p.Source.(model.Trip).CarShareID)
+						return func() (interface{}, error) { return carShare() }, nil
+					},
+				},
+				"driver": &graphql.Field{
+					Type: userType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						trip := p.Source.(model.Trip)
+						if trip.DriverID == "" {
+							return nil, nil
+						}
+						return loadUser(p.Context, trip.DriverID), nil
+					},
+				},
+				"passengers": &graphql.Field{
+					Type: nonNullList(userType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadUsers(p.Context, p.Source.(model.Trip).PassengerIDs), nil
+					},
+				},
+				"scores": &graphql.Field{
+					Type:        nonNullList(scoreType),
+					Description: "Running scores of the car share's members as of this trip",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return scores(p.Source.(model.Trip).Scores), nil
+					},
+				},
+			}
+		}),
+	})
+
+	pageInfoType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PageInfo",
+		Fields: graphql.Fields{
+			"hasNextPage": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Boolean),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tripPage).hasNextPage, nil
+				},
+			},
+			"endCursor": &graphql.Field{
+				Type:        graphql.String,
+				Description: "Cursor to ask for the trips after this page with",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					page := p.Source.(tripPage)
+					if len(page.tripIDs) == 0 {
+						return nil, nil
+					}
+					return page.tripIDs[len(page.tripIDs)-1], nil
+				},
+			},
+		},
+	})
+
+	tripEdgeType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TripEdge",
+		Fields: graphql.Fields{
+			"cursor": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(string), nil
+				},
+			},
+			"node": &graphql.Field{
+				Type: graphql.NewNonNull(tripType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					trip := requestFrom(p.Context).loaders.trip(p.Context, p.Source.(string))
+					return func() (interface{}, error) { return trip() }, nil
+				},
+			},
+		},
+	})
+
+	tripConnectionType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "TripConnection",
+		Description: "A page of trips, most recently logged first",
+		Fields: graphql.Fields{
+			"edges": &graphql.Field{
+				Type: nonNullList(tripEdgeType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tripPage).tripIDs, nil
+				},
+			},
+			"pageInfo": &graphql.Field{
+				Type: graphql.NewNonNull(pageInfoType),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source, nil
+				},
+			},
+			"totalCount": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.Int),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Source.(tripPage).totalCount, nil
+				},
+			},
+		},
+	})
+
+	carShareType = graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CarShare",
+		Description: "A group of users who take turns driving each other",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":           carShareField(graphql.NewNonNull(graphql.ID), func(cs model.CarShare) interface{} { return cs.GetID() }),
+				"name":         carShareField(graphql.NewNonNull(graphql.String), func(cs model.CarShare) interface{} { return cs.Name }),
+				"unit":         carShareField(graphql.NewNonNull(graphql.String), func(cs model.CarShare) interface{} { return string(cs.PreferredUnit()) }),
+				"windowDays":   carShareField(graphql.NewNonNull(graphql.Int), func(cs model.CarShare) interface{} { return cs.WindowDays }),
+				"halfLifeDays": carShareField(graphql.NewNonNull(graphql.Int), func(cs model.CarShare) interface{} { return cs.HalfLifeDays }),
+				"scoring": carShareField(graphql.NewNonNull(graphql.String), func(cs model.CarShare) interface{} {
+					if cs.Scoring == "" {
+						return string(model.DefaultScoring)
+					}
+					return string(cs.Scoring)
+				}),
+				"admins": &graphql.Field{
+					Type: nonNullList(userType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadUsers(p.Context, p.Source.(model.CarShare).AdminIDs), nil
+					},
+				},
+				"members": &graphql.Field{
+					Type: nonNullList(userType),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return loadUsers(p.Context, p.Source.(model.CarShare).MemberIDs), nil
+					},
+				},
+				"trips": &graphql.Field{
+					Type: graphql.NewNonNull(tripConnectionType),
+					Args: graphql.FieldConfigArgument{
+						"first": &graphql.ArgumentConfig{
+							Type:         graphql.Int,
+							DefaultValue: DefaultPageSize,
+							Description:  fmt.Sprintf("Number of trips in the page, at most %d", MaxPageSize),
+						},
+						"after": &graphql.ArgumentConfig{
+							Type:        graphql.String,
+							Description: "Cursor of the trip the page starts after",
+						},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						after, _ := p.Args["after"].(string)
+						return paginate(p.Source.(model.CarShare).TripIDs, p.Args["first"].(int), after)
+					},
+				},
+				"scores": &graphql.Field{
+					Type:        nonNullList(scoreType),
+					Description: "All time scores of the members, the running scores of the latest trip",
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						req := requestFrom(p.Context)
+						carShare := p.Source.(model.CarShare)
+						trip, err := req.TripStorage.GetLatest(carShare.GetID(), req.ctx)
+						switch err {
+						case nil:
+							return scores(trip.Scores), nil
+						case storage.ErrNotFound:
+							return []score{}, nil
+						default:
+							return nil, fmt.Errorf("error retrieving latest trip of car share %s, %s", carShare.GetID(), err)
+						}
+					},
+				},
+			}
+		}),
+	})
+
+	idArgs := graphql.FieldConfigArgument{
+		"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+	}
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": &graphql.Field{
+				Type:        graphql.NewNonNull(userType),
+				Description: "The requesting user",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return requestFrom(p.Context).requestingUser, nil
+				},
+			},
+			"user": &graphql.Field{
+				Type: userType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return orNil(requestFrom(p.Context).user(p.Context, p.Args["id"].(string)))
+				},
+			},
+			"carShares": &graphql.Field{
+				Type:        nonNullList(carShareType),
+				Description: "Car shares the requesting user is a member of",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return requestFrom(p.Context).memberCarShares()
+				},
+			},
+			"carShare": &graphql.Field{
+				Type: carShareType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return orNil(requestFrom(p.Context).carShare(p.Context, p.Args["id"].(string)))
+				},
+			},
+			"trip": &graphql.Field{
+				Type: tripType,
+				Args: idArgs,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return orNil(requestFrom(p.Context).trip(p.Context, p.Args["id"].(string)))
+				},
+			},
+		},
+	})
+
+	var err error
+	schema, err = graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+	if err != nil {
+		panic(fmt.Sprintf("invalid graphql schema, %s", err))
+	}
+}
+
+// paginate the IDs of a car share's trips, which are in the order they were logged, into the
+// page of those logged before the one after
+func paginate(tripIDs []string, first int, after string) (tripPage, error) {
+	if first < 1 || first > MaxPageSize {
+		return tripPage{}, fmt.Errorf("first must be between 1 and %d", MaxPageSize)
+	}
+	end := len(tripIDs)
+	if after != "" {
+		end = -1
+		for i, id := range tripIDs {
+			if id == after {
+				end = i
+			}
+		}
+		if end == -1 {
+			return tripPage{}, fmt.Errorf("unknown cursor %s", after)
+		}
+	}
+	page := tripPage{totalCount: len(tripIDs), tripIDs: []string{}}
+	for i := end - 1; i >= 0 && len(page.tripIDs) < first; i-- {
+		page.tripIDs = append(page.tripIDs, tripIDs[i])
+	}
+	page.hasNextPage = end-len(page.tripIDs) > 0
+	return page, nil
+}
+
+// scores of each user, in a stable order. Guests have no user to resolve, so their scores
+// are left out.
+func scores(byUser map[string]model.Score) []score {
+	result := []score{}
+	for userID, s := range byUser {
+		if strings.HasPrefix(userID, model.GuestKeyPrefix) {
+			continue
+		}
+		result = append(result, score{userID: userID, Score: s})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].userID < result[j].userID
+	})
+	return result
+}
+
+// loadUser as part of the next batch of users. Fields resolve to functions like this once the
+// batches they depend on have been retrieved.
+func loadUser(ctx context.Context, id string) func() (interface{}, error) {
+	user := requestFrom(ctx).loaders.user(ctx, id)
+	return func() (interface{}, error) { return user() }
+}
+
+// loadUsers as part of the next batch of users
+func loadUsers(ctx context.Context, ids []string) []interface{} {
+	users := []interface{}{}
+	for _, id := range ids {
+		users = append(users, loadUser(ctx, id))
+	}
+	return users
+}
+
+// orNil resolves to null rather than an empty value when there is an error
+func orNil(value interface{}, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// nonNullList of values that are never null
+func nonNullList(t graphql.Output) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+func userField(t graphql.Output, value func(model.User) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return value(p.Source.(model.User)), nil
+		},
+	}
+}
+
+func carShareField(t graphql.Output, value func(model.CarShare) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return value(p.Source.(model.CarShare)), nil
+		},
+	}
+}
+
+func tripField(t graphql.Output, value func(model.Trip) interface{}) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return value(p.Source.(model.Trip)), nil
+		},
+	}
+}
+
+func scoreField(value func(score) int) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.Int),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return value(p.Source.(score)), nil
+		},
+	}
+}
@@ -0,0 +1,308 @@
+/*
+Package importer brings historical trips into a car share, for groups migrating from a
+spreadsheet.
+*/
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// Columns that must appear in the header row of an import, in any order
+const (
+	DateColumn       = "date"
+	DriverColumn     = "driver"
+	PassengersColumn = "passengers"
+	DistanceColumn   = "distance"
+)
+
+// dateLayouts accepted in the date column
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+var (
+	// ErrInvalidRows indicates that one or more rows (including the header) failed
+	// validation, nothing has been imported
+	ErrInvalidRows = errors.New("invalid rows, nothing imported")
+)
+
+// RowError describes why a single row of an import is invalid
+type RowError struct {
+	// Row number in the file, counting the header as row 1
+	Row int
+	Err error
+}
+
+func (e RowError) Error() string {
+	return fmt.Sprintf("row %d: %s", e.Row, e.Err)
+}
+
+// Result of an import
+type Result struct {
+	Imported     []string
+	CreatedUsers []string
+	Errors       []RowError
+}
+
+// TripImporter imports trips from CSV files with date, driver, passengers and distance
+// (in metres) columns. Drivers and passengers are given by display name or email, with
+// multiple passengers separated by semicolons.
+type TripImporter struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+
+	// CreateUsers for names that don't match a member, as users linked to the car share.
+	// Otherwise unknown names are reported as row errors.
+	CreateUsers bool
+}
+
+// row that has been parsed but not yet resolved against the members of a car share
+type row struct {
+	number     int
+	timeStamp  time.Time
+	metres     int
+	driver     string
+	passengers []string
+}
+
+// Import validates every row before importing anything. If any row is invalid
+// ErrInvalidRows is returned along with the per row errors in the result. Otherwise
+// trips are inserted in chronological order and the car share score ledger rebuilt.
+func (i TripImporter) Import(carShare model.CarShare, r io.Reader, ctx api2go.APIContexter) (Result, error) {
+
+	result := Result{}
+
+	rows, rowErrors, err := parse(r)
+	if err != nil {
+		return result, err
+	}
+	if len(rows) == 0 && len(rowErrors) == 0 {
+		rowErrors = append(rowErrors, RowError{Row: 2, Err: errors.New("no trips to import")})
+	}
+
+	members, err := i.members(carShare, ctx)
+	if err != nil {
+		return result, err
+	}
+
+	// unknown names are collected up-front so each is created once, however many rows use it
+	unknown := []string{}
+	resolve := func(name string) (string, error) {
+		userID, err := members.find(name)
+		if err == storage.ErrNotFound && i.CreateUsers {
+			userID = members.addPending(name)
+			unknown = append(unknown, name)
+			err = nil
+		}
+		if err == storage.ErrNotFound {
+			err = fmt.Errorf("\"%s\" is not a member", name)
+		}
+		return userID, err
+	}
+
+	for _, row := range rows {
+		errs := []string{}
+		driverID, err := resolve(row.driver)
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+		for _, passenger := range row.passengers {
+			passengerID, err := resolve(passenger)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			if passengerID == driverID {
+				errs = append(errs, fmt.Sprintf("passenger \"%s\" is also the driver", passenger))
+			}
+		}
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, RowError{Row: row.number, Err: errors.New(strings.Join(errs, ", "))})
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		sort.Slice(rowErrors, func(a, b int) bool { return rowErrors[a].Row < rowErrors[b].Row })
+		result.Errors = rowErrors
+		return result, ErrInvalidRows
+	}
+
+	for _, name := range unknown {
+		user := model.User{DisplayName: name, LinkedCarShareID: carShare.GetID()}
+		if strings.Contains(name, "@") {
+			user.Email = name
+		}
+		id, err := i.UserStorage.Insert(user, ctx)
+		if err != nil {
+			return result, fmt.Errorf("error creating user \"%s\", %s", name, err)
+		}
+		members.created(name, id)
+		carShare.MemberIDs = append(carShare.MemberIDs, id)
+		result.CreatedUsers = append(result.CreatedUsers, id)
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool { return rows[a].timeStamp.Before(rows[b].timeStamp) })
+
+	for _, row := range rows {
+		trip := model.Trip{
+			Metres:     row.metres,
+			TimeStamp:  row.timeStamp,
+			CarShareID: carShare.GetID(),
+			DriverID:   members.mustFind(row.driver),
+			Scores:     make(map[string]model.Score),
+		}
+		for _, passenger := range row.passengers {
+			trip.PassengerIDs = append(trip.PassengerIDs, members.mustFind(passenger))
+		}
+		sort.Strings(trip.PassengerIDs)
+		id, err := i.TripStorage.Insert(trip, ctx)
+		if err != nil {
+			return result, fmt.Errorf("error inserting trip from row %d, %s", row.number, err)
+		}
+		carShare.TripIDs = append(carShare.TripIDs, id)
+		result.Imported = append(result.Imported, id)
+	}
+
+	sort.Strings(carShare.MemberIDs)
+	err = i.CarShareStorage.Update(carShare, ctx)
+	if err != nil {
+		return result, fmt.Errorf("error updating car share %s, %s", carShare.GetID(), err)
+	}
+
+	err = ledger.Rebuild(carShare, i.TripStorage, ctx)
+	if err != nil {
+		return result, fmt.Errorf("error rebuilding scores for car share %s, %s", carShare.GetID(), err)
+	}
+
+	return result, nil
+}
+
+// parse the rows of the file. Problems with the content of the file are returned as
+// row errors, the error is reserved for failing to read it at all.
+func parse(r io.Reader) ([]row, []RowError, error) {
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, []RowError{{Row: 1, Err: errors.New("empty file")}}, nil
+	}
+	if parseErr, ok := err.(*csv.ParseError); ok {
+		return nil, []RowError{{Row: parseErr.Line, Err: parseErr.Err}}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	columns := make(map[string]int)
+	for index, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	missing := []string{}
+	for _, name := range []string{DateColumn, DriverColumn, PassengersColumn, DistanceColumn} {
+		if _, ok := columns[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, []RowError{{Row: 1, Err: fmt.Errorf("missing columns %s", strings.Join(missing, ", "))}}, nil
+	}
+
+	rows := []row{}
+	rowErrors := []RowError{}
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			// the rest of the file can't be trusted after a quoting error
+			rowErrors = append(rowErrors, RowError{Row: parseErr.Line, Err: parseErr.Err})
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		field := func(name string) string {
+			index := columns[name]
+			if index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		row, err := parseRow(number, field(DateColumn), field(DriverColumn), field(PassengersColumn), field(DistanceColumn))
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: number, Err: err})
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, rowErrors, nil
+}
+
+func parseRow(number int, date, driver, passengers, distance string) (row, error) {
+
+	result := row{number: number, driver: driver}
+	errs := []string{}
+
+	parsed := false
+	for _, layout := range dateLayouts {
+		timeStamp, err := time.Parse(layout, date)
+		if err == nil {
+			result.timeStamp = timeStamp.UTC()
+			parsed = true
+			break
+		}
+	}
+	if !parsed {
+		errs = append(errs, fmt.Sprintf("invalid date \"%s\"", date))
+	}
+
+	if driver == "" {
+		errs = append(errs, "missing driver")
+	}
+
+	seen := make(map[string]bool)
+	for _, passenger := range strings.Split(passengers, ";") {
+		passenger = strings.TrimSpace(passenger)
+		if passenger == "" {
+			continue
+		}
+		if seen[strings.ToLower(passenger)] {
+			errs = append(errs, fmt.Sprintf("passenger \"%s\" listed more than once", passenger))
+			continue
+		}
+		seen[strings.ToLower(passenger)] = true
+		result.passengers = append(result.passengers, passenger)
+	}
+
+	metres, err := strconv.Atoi(distance)
+	if err != nil || metres <= 0 {
+		errs = append(errs, fmt.Sprintf("invalid distance \"%s\", expected a whole number of metres", distance))
+	}
+	result.metres = metres
+
+	if len(errs) > 0 {
+		return result, errors.New(strings.Join(errs, ", "))
+	}
+	return result, nil
+}
@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"strings"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trip Importer", func() {
+
+	var (
+		tripImporter    TripImporter
+		context         *api2go.APIContext
+		carShareStorage *memory.CarShareStorage
+		tripStorage     *memory.TripStorage
+		userStorage     *memory.UserStorage
+		carShare        model.CarShare
+		aliceID         string
+		bobID           string
+		file            string
+		result          Result
+		err             error
+	)
+
+	BeforeEach(func() {
+		context = &api2go.APIContext{}
+		carShareStorage = memory.NewCarShareStorage()
+		tripStorage = memory.NewTripStorage()
+		userStorage = memory.NewUserStorage()
+		tripImporter = TripImporter{
+			CarShareStorage: carShareStorage,
+			TripStorage:     tripStorage,
+			UserStorage:     userStorage,
+		}
+
+		aliceID, err = userStorage.Insert(model.User{DisplayName: "Alice"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		bobID, err = userStorage.Insert(model.User{DisplayName: "Bob", Email: "bob@example.com"}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		carShare = model.CarShare{MemberIDs: []string{aliceID, bobID}}
+		id, err := carShareStorage.Insert(carShare, context)
+		Expect(err).ToNot(HaveOccurred())
+		carShare.SetID(id)
+
+		file = strings.Join([]string{
+			"Date,Driver,Passengers,Distance",
+			"2017-11-02,alice,bob@example.com,2000",
+			"2017-11-01,Bob,Alice,1000",
+		}, "\n")
+	})
+
+	JustBeforeEach(func() {
+		result, err = tripImporter.Import(carShare, strings.NewReader(file), context)
+	})
+
+	Context("with valid rows", func() {
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Imported).To(HaveLen(2))
+		})
+
+		It("should import trips in chronological order with their own timestamps", func() {
+			first, err := tripStorage.GetOne(result.Imported[0], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(first.TimeStamp).To(Equal(time.Date(2017, 11, 1, 0, 0, 0, 0, time.UTC)))
+			Expect(first.DriverID).To(Equal(bobID))
+			Expect(first.PassengerIDs).To(Equal([]string{aliceID}))
+		})
+
+		It("should rebuild the score ledger", func() {
+			last, err := tripStorage.GetOne(result.Imported[1], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(last.Scores[aliceID]).To(Equal(model.Score{MetresAsDriver: 2000, MetresAsPassenger: 1000, PointsAsDriver: 2000, PointsAsPassenger: 1000}))
+			Expect(last.Scores[bobID]).To(Equal(model.Score{MetresAsDriver: 1000, MetresAsPassenger: 2000, PointsAsDriver: 1000, PointsAsPassenger: 2000}))
+		})
+
+		It("should add the trips to the car share", func() {
+			updated, err := carShareStorage.GetOne(carShare.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.TripIDs).To(ConsistOf(result.Imported))
+		})
+
+	})
+
+	Context("with invalid rows", func() {
+
+		BeforeEach(func() {
+			file = strings.Join([]string{
+				"date,driver,passengers,distance",
+				"2017-11-01,Alice,Bob,1000",
+				"yesterday,Alice,Bob,-5",
+				"2017-11-03,Alice,Alice,1000",
+				"2017-11-04,Carol,Bob,1000",
+			}, "\n")
+		})
+
+		It("should throw an ErrInvalidRows error", func() {
+			Expect(err).To(Equal(ErrInvalidRows))
+		})
+
+		It("should report an error for every invalid row", func() {
+			Expect(result.Errors).To(HaveLen(3))
+			Expect(result.Errors[0].Row).To(Equal(3))
+			Expect(result.Errors[0].Error()).To(ContainSubstring("invalid date"))
+			Expect(result.Errors[0].Error()).To(ContainSubstring("invalid distance"))
+			Expect(result.Errors[1].Row).To(Equal(4))
+			Expect(result.Errors[1].Error()).To(ContainSubstring("also the driver"))
+			Expect(result.Errors[2].Row).To(Equal(5))
+			Expect(result.Errors[2].Error()).To(ContainSubstring("\"Carol\" is not a member"))
+		})
+
+		It("should not import anything", func() {
+			trips, err := tripStorage.GetAll(context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trips).To(BeEmpty())
+		})
+
+	})
+
+	Context("with a missing column", func() {
+
+		BeforeEach(func() {
+			file = "date,driver,distance\n2017-11-01,Alice,1000"
+		})
+
+		It("should report the header row", func() {
+			Expect(err).To(Equal(ErrInvalidRows))
+			Expect(result.Errors).To(HaveLen(1))
+			Expect(result.Errors[0].Row).To(Equal(1))
+			Expect(result.Errors[0].Error()).To(ContainSubstring("passengers"))
+		})
+
+	})
+
+	Context("creating users", func() {
+
+		BeforeEach(func() {
+			tripImporter.CreateUsers = true
+			file = strings.Join([]string{
+				"date,driver,passengers,distance",
+				"2017-11-01,Carol,Alice;dave@example.com,1000",
+				"2017-11-02,carol,Bob,1000",
+			}, "\n")
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should create each unknown user once, linked to the car share", func() {
+			Expect(result.CreatedUsers).To(HaveLen(2))
+			carol, err := userStorage.GetOne(result.CreatedUsers[0], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carol.DisplayName).To(Equal("Carol"))
+			Expect(carol.LinkedCarShareID).To(Equal(carShare.GetID()))
+			dave, err := userStorage.GetOne(result.CreatedUsers[1], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dave.Email).To(Equal("dave@example.com"))
+		})
+
+		It("should add created users to the car share members", func() {
+			updated, err := carShareStorage.GetOne(carShare.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.MemberIDs).To(ContainElement(result.CreatedUsers[0]))
+			Expect(updated.MemberIDs).To(ContainElement(result.CreatedUsers[1]))
+		})
+
+	})
+
+})
@@ -0,0 +1,75 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// pendingPrefix marks ids handed out for users that will be created once every row is valid
+const pendingPrefix = "pending:"
+
+// directory of car share members, by case insensitive display name and email
+type directory map[string][]string
+
+// members of a car share
+func (i TripImporter) members(carShare model.CarShare, ctx api2go.APIContexter) (directory, error) {
+	result := make(directory)
+	for _, memberID := range carShare.MemberIDs {
+		member, err := i.UserStorage.GetOne(memberID, ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving member %s, %s", memberID, err)
+		}
+		result.add(member.DisplayName, memberID)
+		if !strings.EqualFold(member.Email, member.DisplayName) {
+			result.add(member.Email, memberID)
+		}
+	}
+	return result, nil
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func (d directory) add(name, userID string) {
+	if key(name) == "" {
+		return
+	}
+	d[key(name)] = append(d[key(name)], userID)
+}
+
+// find the id of the member known by name. Names shared by more than one member are
+// rejected rather than guessed at.
+func (d directory) find(name string) (string, error) {
+	userIDs := d[key(name)]
+	switch len(userIDs) {
+	case 0:
+		return "", storage.ErrNotFound
+	case 1:
+		return userIDs[0], nil
+	default:
+		return "", fmt.Errorf("\"%s\" matches more than one member", name)
+	}
+}
+
+// addPending adds a user that is yet to be created, returning a placeholder id
+func (d directory) addPending(name string) string {
+	userID := pendingPrefix + key(name)
+	d.add(name, userID)
+	return userID
+}
+
+// created replaces the placeholder id of a pending user with their real id
+func (d directory) created(name, userID string) {
+	d[key(name)] = []string{userID}
+}
+
+// mustFind is for use once every name is known to resolve
+func (d directory) mustFind(name string) string {
+	userID, _ := d.find(name)
+	return userID
+}
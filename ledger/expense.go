@@ -0,0 +1,53 @@
+package ledger
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// Share an expense between its participants. Expenses for a trip are shared equally, as
+// everyone in the car travelled the same distance. Otherwise expenses split by distance
+// are shared in proportion to the distance each participant has travelled in the car
+// share so far, as a driver or passenger.
+func Share(expense *model.Expense, tripStorage storage.TripStorage, ctx api2go.APIContexter) error {
+
+	if expense.Split != model.SplitByDistance || expense.TripID != "" {
+		expense.Divide(nil)
+		return nil
+	}
+
+	latestTrip, err := tripStorage.GetLatest(expense.CarShareID, ctx)
+	if err != nil && err != storage.ErrNotFound {
+		return err
+	}
+
+	weights := make(map[string]int, len(latestTrip.Scores))
+	for userID, score := range latestTrip.Scores {
+		weights[userID] = score.MetresAsDriver + score.MetresAsPassenger
+	}
+	expense.Divide(weights)
+	return nil
+}
+
+// Settle the expenses of a car share, working out the balance of each member and the
+// transfers between them that would bring every balance to zero
+func Settle(carShareID string, expenseStorage storage.ExpenseStorage, ctx api2go.APIContexter) (model.Balance, error) {
+
+	balance := model.Balance{
+		CarShareID: carShareID,
+		Balances:   make(map[string]int),
+	}
+
+	expenses, err := expenseStorage.GetAll(carShareID, ctx)
+	if err != nil {
+		return balance, err
+	}
+
+	for _, expense := range expenses {
+		balance.Add(expense)
+	}
+	balance.Settle()
+
+	return balance, nil
+}
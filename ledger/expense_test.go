@@ -0,0 +1,117 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expenses", func() {
+
+	var (
+		tripStorage    *memory.TripStorage
+		expenseStorage *memory.ExpenseStorage
+		context        *api2go.APIContext
+		carShareID     = "58a7b3b4e4b0e1b5c0a1a001"
+	)
+
+	BeforeEach(func() {
+		tripStorage = memory.NewTripStorage()
+		expenseStorage = memory.NewExpenseStorage()
+		context = &api2go.APIContext{}
+		_, err := tripStorage.Insert(model.Trip{
+			Metres:     3000,
+			TimeStamp:  time.Date(2017, 11, 6, 0, 0, 0, 0, time.UTC),
+			CarShareID: carShareID,
+			Scores: map[string]model.Score{
+				"alice": {MetresAsDriver: 2000},
+				"bob":   {MetresAsPassenger: 1000},
+			},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("share", func() {
+
+		var expense model.Expense
+
+		BeforeEach(func() {
+			expense = model.Expense{
+				Amount:         1000,
+				CarShareID:     carShareID,
+				ParticipantIDs: []string{"alice", "bob", "carol"},
+			}
+		})
+
+		It("should share equally", func() {
+			expense.Split = model.SplitEqually
+			Expect(Share(&expense, tripStorage, context)).To(Succeed())
+			Expect(expense.Shares).To(Equal(map[string]int{"alice": 334, "bob": 333, "carol": 333}))
+		})
+
+		It("should share by the distance travelled so far", func() {
+			expense.Split = model.SplitByDistance
+			Expect(Share(&expense, tripStorage, context)).To(Succeed())
+			Expect(expense.Shares).To(Equal(map[string]int{"alice": 667, "bob": 333, "carol": 0}))
+		})
+
+		It("should share a trip's expenses equally", func() {
+			expense.Split = model.SplitByDistance
+			expense.TripID = "58a7b3b4e4b0e1b5c0a1a002"
+			Expect(Share(&expense, tripStorage, context)).To(Succeed())
+			Expect(expense.Shares).To(Equal(map[string]int{"alice": 334, "bob": 333, "carol": 333}))
+		})
+
+		It("should share equally when nobody has travelled", func() {
+			expense.Split = model.SplitByDistance
+			expense.CarShareID = "another car share"
+			Expect(Share(&expense, tripStorage, context)).To(Succeed())
+			Expect(expense.Shares).To(Equal(map[string]int{"alice": 334, "bob": 333, "carol": 333}))
+		})
+
+	})
+
+	Describe("settle", func() {
+
+		var (
+			balance model.Balance
+			err     error
+		)
+
+		BeforeEach(func() {
+			for _, expense := range []model.Expense{
+				{PayerID: "alice", Amount: 900, Shares: map[string]int{"alice": 300, "bob": 300, "carol": 300}},
+				{PayerID: "bob", Amount: 300, Shares: map[string]int{"alice": 100, "bob": 100, "carol": 100}},
+				{PayerID: "dave", Amount: 100, Shares: map[string]int{"dave": 100}},
+			} {
+				expense.CarShareID = carShareID
+				_, err = expenseStorage.Insert(expense, context)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			balance, err = Settle(carShareID, expenseStorage, context)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should balance what each member has paid against their shares", func() {
+			Expect(balance.Balances).To(Equal(map[string]int{"alice": 500, "bob": -100, "carol": -400, "dave": 0}))
+		})
+
+		It("should settle the balances with as few transfers as possible", func() {
+			Expect(balance.Transfers).To(Equal([]model.Transfer{
+				{FromID: "carol", ToID: "alice", Amount: 400},
+				{FromID: "bob", ToID: "alice", Amount: 100},
+			}))
+		})
+
+	})
+
+})
@@ -0,0 +1,42 @@
+/*
+Package ledger keeps the running scores stored against each trip consistent with the
+trip history of a car share, works out how its members stand over a rolling window, and
+settles the expenses they have paid.
+*/
+package ledger
+
+import (
+	"reflect"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// Rebuild recalculates the running scores of every trip in a car share, oldest first, using
+// its scoring strategy. Needed whenever trips are added, changed or removed anywhere other
+// than at the end of a car share's history, or the strategy changes. Only trips whose scores
+// change are updated.
+func Rebuild(carShare model.CarShare, tripStorage storage.TripStorage, ctx api2go.APIContexter) error {
+	strategy := carShare.Scoring.Strategy()
+	scores := make(map[string]model.Score)
+	return tripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		previous := trip.Scores
+		trip.Scores = nil
+		trip.CalculateScores(copyScores(scores), strategy)
+		scores = trip.Scores
+		if reflect.DeepEqual(previous, trip.Scores) {
+			return nil
+		}
+		return tripStorage.Update(trip, ctx)
+	}, ctx)
+}
+
+// copyScores so that calculating the scores for one trip doesn't modify the last
+func copyScores(scores map[string]model.Score) map[string]model.Score {
+	result := make(map[string]model.Score, len(scores))
+	for userID, score := range scores {
+		result[userID] = score
+	}
+	return result
+}
@@ -0,0 +1,170 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Ledger", func() {
+
+	var (
+		tripStorage *memory.TripStorage
+		context     *api2go.APIContext
+		carShareID  = "58a7b3b4e4b0e1b5c0a1a001"
+		carShare    model.CarShare
+		tripIDs     []string
+		err         error
+	)
+
+	BeforeEach(func() {
+		tripStorage = memory.NewTripStorage()
+		context = &api2go.APIContext{}
+		tripIDs = nil
+		carShare = model.CarShare{ID: bson.ObjectIdHex(carShareID)}
+
+		// scores deliberately wrong, as if the middle trip had been inserted after the last
+		for day, metres := range []int{100, 200, 300} {
+			id, err := tripStorage.Insert(model.Trip{
+				Metres:       metres,
+				TimeStamp:    time.Date(2017, 11, day+1, 0, 0, 0, 0, time.UTC),
+				CarShareID:   carShareID,
+				DriverID:     "driver",
+				PassengerIDs: []string{"passenger"},
+				Scores: map[string]model.Score{
+					"driver": {MetresAsDriver: metres},
+				},
+			}, context)
+			Expect(err).ToNot(HaveOccurred())
+			tripIDs = append(tripIDs, id)
+		}
+		_, err = tripStorage.Insert(model.Trip{
+			Metres:     1000,
+			CarShareID: "another car share",
+			DriverID:   "driver",
+			Scores:     map[string]model.Score{},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		err = Rebuild(carShare, tripStorage, context)
+	})
+
+	It("should not throw an error", func() {
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("should recalculate the running scores of every trip", func() {
+		for i, expected := range []int{100, 300, 600} {
+			trip, err := tripStorage.GetOne(tripIDs[i], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.Scores).To(Equal(map[string]model.Score{
+				"driver":    {MetresAsDriver: expected, PointsAsDriver: expected},
+				"passenger": {MetresAsPassenger: expected, PointsAsPassenger: expected},
+			}))
+		}
+	})
+
+	It("should not share score maps between trips", func() {
+		first, _ := tripStorage.GetOne(tripIDs[0], context)
+		last, _ := tripStorage.GetOne(tripIDs[2], context)
+		first.Scores["driver"] = model.Score{}
+		Expect(last.Scores["driver"].MetresAsDriver).To(Equal(600))
+	})
+
+	Context("with guests", func() {
+
+		BeforeEach(func() {
+			_, err := tripStorage.Insert(model.Trip{
+				Metres:       1000,
+				TimeStamp:    time.Date(2017, 11, 4, 0, 0, 0, 0, time.UTC),
+				CarShareID:   carShareID,
+				DriverID:     "driver",
+				PassengerIDs: []string{"passenger"},
+				Guests:       []model.Guest{{Name: "Visitor", Counted: true}, {Name: "Hitchhiker"}},
+			}, context)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should only score the guests that are counted", func() {
+			latest, err := tripStorage.GetLatest(carShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.Scores).To(HaveLen(3))
+			Expect(latest.Scores["guest:visitor"]).To(Equal(model.Score{MetresAsPassenger: 1000, PointsAsPassenger: 1000}))
+		})
+
+	})
+
+	Context("with a car share that counts trips", func() {
+
+		BeforeEach(func() {
+			carShare.Scoring = model.TripScoring
+		})
+
+		It("should award points by that strategy, still keeping track of distance", func() {
+			last, err := tripStorage.GetOne(tripIDs[2], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(last.Scores).To(Equal(map[string]model.Score{
+				"driver":    {MetresAsDriver: 600, PointsAsDriver: 3},
+				"passenger": {MetresAsPassenger: 600, PointsAsPassenger: 3},
+			}))
+		})
+
+	})
+
+	Context("with a car share that weights trips by passengers", func() {
+
+		BeforeEach(func() {
+			_, err := tripStorage.Insert(model.Trip{
+				Metres:       1000,
+				TimeStamp:    time.Date(2017, 11, 4, 0, 0, 0, 0, time.UTC),
+				CarShareID:   carShareID,
+				DriverID:     "driver",
+				PassengerIDs: []string{"passenger", "another passenger"},
+			}, context)
+			Expect(err).ToNot(HaveOccurred())
+			carShare.Scoring = model.PassengerDistanceScoring
+		})
+
+		It("should credit the driver once for each passenger carried", func() {
+			latest, err := tripStorage.GetLatest(carShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.Scores["driver"].PointsAsDriver).To(Equal(2600))
+			Expect(latest.Scores["passenger"].PointsAsPassenger).To(Equal(1600))
+			Expect(latest.Scores["another passenger"].PointsAsPassenger).To(Equal(1000))
+		})
+
+	})
+
+	Context("with a car share that discounts short trips", func() {
+
+		BeforeEach(func() {
+			_, err := tripStorage.Insert(model.Trip{
+				Metres:       model.ShortTripMetres,
+				TimeStamp:    time.Date(2017, 11, 4, 0, 0, 0, 0, time.UTC),
+				CarShareID:   carShareID,
+				DriverID:     "driver",
+				PassengerIDs: []string{"passenger"},
+			}, context)
+			Expect(err).ToNot(HaveOccurred())
+			carShare.Scoring = model.ShortTripScoring
+		})
+
+		It("should count trips shorter than the threshold at half their distance", func() {
+			latest, err := tripStorage.GetLatest(carShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.Scores["driver"].MetresAsDriver).To(Equal(5600))
+			Expect(latest.Scores["driver"].PointsAsDriver).To(Equal(5300))
+		})
+
+	})
+
+})
@@ -0,0 +1,82 @@
+package ledger
+
+import (
+	"math"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// Standings of the members of a car share at the given time. All time scores are the running
+// scores of its latest trip. Windowed scores are worked out afresh from the trip history, each
+// trip counting towards them by its weight under the car share's rolling window and half life.
+func Standings(carShare model.CarShare, tripStorage storage.TripStorage, now time.Time, ctx api2go.APIContexter) (model.Standings, error) {
+
+	standings := model.Standings{
+		CarShareID:   carShare.GetID(),
+		AllTime:      make(map[string]model.Score),
+		Windowed:     make(map[string]model.Score),
+		WindowDays:   carShare.WindowDays,
+		HalfLifeDays: carShare.HalfLifeDays,
+	}
+
+	strategy := carShare.Scoring.Strategy()
+	windowed := make(map[string]*weightedScore)
+	err := tripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		standings.AllTime = copyScores(trip.Scores)
+
+		weight := carShare.Weight(trip.TimeStamp, now)
+		if weight == 0 || !trip.Status.Counts() {
+			return nil
+		}
+
+		// the scores of a trip on its own are what it adds to the running scores
+		trip.Scores = nil
+		trip.CalculateScores(make(map[string]model.Score), strategy)
+		for userID, score := range trip.Scores {
+			if windowed[userID] == nil {
+				windowed[userID] = &weightedScore{}
+			}
+			windowed[userID].add(score, weight)
+		}
+		return nil
+	}, ctx)
+	if err != nil {
+		return standings, err
+	}
+
+	for userID, score := range windowed {
+		standings.Windowed[userID] = score.round()
+	}
+
+	return standings, nil
+}
+
+// weightedScore accumulates a score from weighted trips, only rounding once they are all added
+type weightedScore struct {
+	metresAsDriver, metresAsPassenger float64
+	pointsAsDriver, pointsAsPassenger float64
+	costAsDriver, costAsPassenger     float64
+}
+
+func (w *weightedScore) add(score model.Score, weight float64) {
+	w.metresAsDriver += float64(score.MetresAsDriver) * weight
+	w.metresAsPassenger += float64(score.MetresAsPassenger) * weight
+	w.pointsAsDriver += float64(score.PointsAsDriver) * weight
+	w.pointsAsPassenger += float64(score.PointsAsPassenger) * weight
+	w.costAsDriver += float64(score.CostAsDriver) * weight
+	w.costAsPassenger += float64(score.CostAsPassenger) * weight
+}
+
+func (w weightedScore) round() model.Score {
+	return model.Score{
+		MetresAsDriver:    int(math.Floor(w.metresAsDriver + 0.5)),
+		MetresAsPassenger: int(math.Floor(w.metresAsPassenger + 0.5)),
+		PointsAsDriver:    int(math.Floor(w.pointsAsDriver + 0.5)),
+		PointsAsPassenger: int(math.Floor(w.pointsAsPassenger + 0.5)),
+		CostAsDriver:      int(math.Floor(w.costAsDriver + 0.5)),
+		CostAsPassenger:   int(math.Floor(w.costAsPassenger + 0.5)),
+	}
+}
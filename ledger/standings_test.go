@@ -0,0 +1,106 @@
+package ledger
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Standings", func() {
+
+	var (
+		tripStorage *memory.TripStorage
+		context     *api2go.APIContext
+		carShare    model.CarShare
+		now         = time.Date(2018, 1, 29, 0, 0, 0, 0, time.UTC)
+		standings   model.Standings
+		err         error
+	)
+
+	BeforeEach(func() {
+		tripStorage = memory.NewTripStorage()
+		context = &api2go.APIContext{}
+		carShare = model.CarShare{ID: bson.ObjectIdHex("58a7b3b4e4b0e1b5c0a1a001")}
+		for _, trip := range []model.Trip{
+			{
+				Metres:       1000,
+				TimeStamp:    now.AddDate(0, 0, -60),
+				DriverID:     "alice",
+				PassengerIDs: []string{"bob"},
+			},
+			{
+				Metres:       2000,
+				TimeStamp:    now.AddDate(0, 0, -30),
+				DriverID:     "bob",
+				PassengerIDs: []string{"alice"},
+			},
+			{
+				Metres:       4000,
+				TimeStamp:    now.AddDate(0, 0, -1),
+				Status:       model.TripDraft,
+				DriverID:     "alice",
+				PassengerIDs: []string{"bob"},
+			},
+		} {
+			trip.CarShareID = carShare.GetID()
+			_, err := tripStorage.Insert(trip, context)
+			Expect(err).ToNot(HaveOccurred())
+		}
+		Expect(Rebuild(carShare, tripStorage, context)).To(Succeed())
+	})
+
+	JustBeforeEach(func() {
+		standings, err = Standings(carShare, tripStorage, now, context)
+	})
+
+	It("should count every trip in the windowed scores without a window or half life", func() {
+		Expect(err).ToNot(HaveOccurred())
+		Expect(standings.GetID()).To(Equal(carShare.GetID()))
+		Expect(standings.Windowed).To(Equal(standings.AllTime))
+		Expect(standings.AllTime["alice"]).To(Equal(model.Score{
+			MetresAsDriver: 1000, MetresAsPassenger: 2000, PointsAsDriver: 1000, PointsAsPassenger: 2000,
+		}))
+	})
+
+	Context("with a rolling window", func() {
+
+		BeforeEach(func() {
+			carShare.WindowDays = 40
+		})
+
+		It("should only count the trips within the window", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(standings.WindowDays).To(Equal(40))
+			Expect(standings.Windowed).To(Equal(map[string]model.Score{
+				"alice": {MetresAsPassenger: 2000, PointsAsPassenger: 2000},
+				"bob":   {MetresAsDriver: 2000, PointsAsDriver: 2000},
+			}))
+			Expect(standings.AllTime["alice"].MetresAsDriver).To(Equal(1000))
+		})
+
+	})
+
+	Context("with a half life", func() {
+
+		BeforeEach(func() {
+			carShare.HalfLifeDays = 30
+		})
+
+		It("should decay trips by their age", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(standings.Windowed).To(Equal(map[string]model.Score{
+				"alice": {MetresAsDriver: 250, MetresAsPassenger: 1000, PointsAsDriver: 250, PointsAsPassenger: 1000},
+				"bob":   {MetresAsDriver: 1000, MetresAsPassenger: 250, PointsAsDriver: 1000, PointsAsPassenger: 250},
+			}))
+		})
+
+	})
+
+})
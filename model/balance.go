@@ -0,0 +1,131 @@
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// Transfer of money from one member of a car share to another to settle their balances
+type Transfer struct {
+	FromID string `json:"from"`
+	ToID   string `json:"to"`
+	Amount int    `json:"amount"`
+}
+
+// Balance of the expenses of a car share. Each member is owed their balance if it is
+// positive, or owes it if it is negative. A balance is identified by its car share.
+type Balance struct {
+	CarShareID string         `json:"-"`
+	Balances   map[string]int `json:"balances"`
+	Transfers  []Transfer     `json:"transfers"`
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (b Balance) GetID() string {
+	return b.CarShareID
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (b *Balance) SetID(id string) error {
+	b.CarShareID = id
+	return nil
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (b Balance) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (b Balance) GetReferencedIDs() []jsonapi.ReferenceID {
+	return []jsonapi.ReferenceID{
+		{
+			ID:   b.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		},
+	}
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (b *Balance) SetToOneReferenceID(name, ID string) error {
+	if name == "carShare" {
+		b.CarShareID = ID
+		return nil
+	}
+	return errors.New("There is no to-one relationship with the name " + name)
+}
+
+// Add an expense to the balance, crediting its payer and debiting each participant their share
+func (b *Balance) Add(expense Expense) {
+	if b.Balances == nil {
+		b.Balances = make(map[string]int)
+	}
+	b.Balances[expense.PayerID] += expense.Amount
+	for participantID, share := range expense.Shares {
+		b.Balances[participantID] -= share
+	}
+}
+
+// Settle works out the transfers that bring every balance to zero. Each transfer pays off
+// as much as possible of the largest debt to the largest credit, so there is at most one
+// fewer transfer than members with a balance.
+func (b *Balance) Settle() {
+
+	type position struct {
+		userID string
+		amount int
+	}
+
+	var creditors, debtors []position
+	for userID, amount := range b.Balances {
+		switch {
+		case amount > 0:
+			creditors = append(creditors, position{userID, amount})
+		case amount < 0:
+			debtors = append(debtors, position{userID, -amount})
+		}
+	}
+
+	// largest first, by ID when equal so the transfers are always the same
+	largest := func(positions []position) func(i, j int) bool {
+		return func(i, j int) bool {
+			if positions[i].amount != positions[j].amount {
+				return positions[i].amount > positions[j].amount
+			}
+			return positions[i].userID < positions[j].userID
+		}
+	}
+
+	b.Transfers = []Transfer{}
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.Slice(creditors, largest(creditors))
+		sort.Slice(debtors, largest(debtors))
+
+		amount := creditors[0].amount
+		if debtors[0].amount < amount {
+			amount = debtors[0].amount
+		}
+		b.Transfers = append(b.Transfers, Transfer{
+			FromID: debtors[0].userID,
+			ToID:   creditors[0].userID,
+			Amount: amount,
+		})
+
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+}
@@ -4,22 +4,35 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"sort"
+	"time"
 
 	"github.com/manyminds/api2go/jsonapi"
 	"gopkg.in/mgo.v2/bson"
 )
 
-// CarShare an individual group of users who make up a car share
+// CarShare an individual group of users who make up a car share. Besides all time scores,
+// members can be compared over a rolling window of the last WindowDays days and with trips
+// decaying to half their worth every HalfLifeDays days, either being off when zero.
+// Members are emailed a weekly digest, and a reminder on the days set by Reminder when
+// no trip has been logged.
 type CarShare struct {
-	ID        bson.ObjectId `json:"-"    bson:"_id,omitempty"`
-	Name      string        `json:"name" bson:"name"`
-	Members   []*User       `json:"-"    bson:"-"`
-	MemberIDs []string      `json:"-"    bson:"members"`
-	Admins    []*User       `json:"-"    bson:"-"`
-	AdminIDs  []string      `json:"-"    bson:"admins"`
-	Trips     []Trip        `json:"-"    bson:"-"`
-	TripIDs   []string      `json:"-"    bson:"trips"`
+	ID           bson.ObjectId `json:"-"              bson:"_id,omitempty"`
+	Name         string        `json:"name"           bson:"name"`
+	Unit         Unit          `json:"unit"           bson:"unit,omitempty"`
+	Scoring      Scoring       `json:"scoring"        bson:"scoring,omitempty"`
+	WindowDays   int           `json:"window-days"    bson:"window-days,omitempty"`
+	HalfLifeDays int           `json:"half-life-days" bson:"half-life-days,omitempty"`
+	Members      []*User       `json:"-"              bson:"-"`
+	MemberIDs    []string      `json:"-"              bson:"members"`
+	Admins       []*User       `json:"-"              bson:"-"`
+	AdminIDs     []string      `json:"-"              bson:"admins"`
+	Trips        []Trip        `json:"-"              bson:"-"`
+	TripIDs      []string      `json:"-"              bson:"trips"`
+	Reminder     *Reminder     `json:"reminder"       bson:"reminder,omitempty"`
+	NextDigest   time.Time     `json:"-"              bson:"next-digest,omitempty"`
+	NextReminder time.Time     `json:"-"              bson:"next-reminder,omitempty"`
 }
 
 // GetID to satisfy jsonapi.MarshalIdentifier interface
@@ -56,6 +69,45 @@ func (cs CarShare) GetReferences() []jsonapi.Reference {
 			Type: "users",
 			Name: "admins",
 		},
+		{
+			// routes, schedules, vehicles, expenses, webhooks, the balance and standings belong to
+			// the car share rather than being listed by it, so are only ever available as links
+			Type:        "routes",
+			Name:        "routes",
+			IsNotLoaded: true,
+		},
+		{
+			Type:        "schedules",
+			Name:        "schedules",
+			IsNotLoaded: true,
+		},
+		{
+			Type:        "vehicles",
+			Name:        "vehicles",
+			IsNotLoaded: true,
+		},
+		{
+			Type:        "expenses",
+			Name:        "expenses",
+			IsNotLoaded: true,
+		},
+		{
+			Type:        "webhooks",
+			Name:        "webhooks",
+			IsNotLoaded: true,
+		},
+		{
+			Type:         "balances",
+			Name:         "balance",
+			IsNotLoaded:  true,
+			Relationship: jsonapi.ToOneRelationship,
+		},
+		{
+			Type:         "standings",
+			Name:         "standings",
+			IsNotLoaded:  true,
+			Relationship: jsonapi.ToOneRelationship,
+		},
 	}
 }
 
@@ -190,6 +242,38 @@ func (cs *CarShare) DeleteToManyIDs(name string, IDs []string) error {
 	return nil
 }
 
+// PreferredUnit for presenting distances to the car share
+func (cs *CarShare) PreferredUnit() Unit {
+	if cs.Unit == "" {
+		return DefaultUnit
+	}
+	return cs.Unit
+}
+
+// ErrInvalidWindow indicates a negative rolling window or half life
+var ErrInvalidWindow = errors.New("window and half life can't be negative")
+
+// ValidateWindow checks the rolling window and half life of the car share
+func (cs *CarShare) ValidateWindow() error {
+	if cs.WindowDays < 0 || cs.HalfLifeDays < 0 {
+		return ErrInvalidWindow
+	}
+	return nil
+}
+
+// Weight of a trip made at the given time in the windowed scores of the car share. Trips
+// before the rolling window count for nothing, and the rest decay by their age in half lives.
+func (cs *CarShare) Weight(at time.Time, now time.Time) float64 {
+	age := now.Sub(at)
+	if cs.WindowDays > 0 && age > time.Duration(cs.WindowDays)*24*time.Hour {
+		return 0
+	}
+	if cs.HalfLifeDays <= 0 || age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, age.Hours()/float64(24*cs.HalfLifeDays))
+}
+
 // IsAdmin returns true if userID is in list of admins
 func (cs *CarShare) IsAdmin(userID string) bool {
 	for _, id := range cs.AdminIDs {
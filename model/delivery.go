@@ -0,0 +1,129 @@
+package model
+
+import (
+	"errors"
+	"time"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// DeliveryStatus where a delivery is in being sent to its webhook
+type DeliveryStatus string
+
+// Delivery statuses
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// MaxDeliveryAttempts before a delivery is given up on
+const MaxDeliveryAttempts = 8
+
+// FirstRetry is how long after a failed first attempt a delivery is retried, doubling with
+// each failure after that
+const FirstRetry = time.Minute
+
+// Delivery of an event to a webhook. Deliveries are queued in storage until they are
+// sent, so none are lost if the server restarts.
+type Delivery struct {
+	ID           bson.ObjectId  `json:"-"                       bson:"_id,omitempty"`
+	Event        string         `json:"event"                   bson:"event"`
+	Payload      string         `json:"payload"                 bson:"payload"`
+	Status       DeliveryStatus `json:"status"                  bson:"status"`
+	Attempts     int            `json:"attempts"                bson:"attempts"`
+	Created      time.Time      `json:"created"                 bson:"created"`
+	NextAttempt  time.Time      `json:"next-attempt,omitempty"  bson:"next-attempt,omitempty"`
+	Delivered    time.Time      `json:"delivered,omitempty"     bson:"delivered,omitempty"`
+	ResponseCode int            `json:"response-code,omitempty" bson:"response-code,omitempty"`
+	LastError    string         `json:"last-error,omitempty"    bson:"last-error,omitempty"`
+	WebhookID    string         `json:"-"                       bson:"webhook"`
+	CarShareID   string         `json:"-"                       bson:"car-share"`
+}
+
+// Succeeded records a successful attempt at the delivery
+func (d *Delivery) Succeeded(at time.Time, responseCode int) {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.Delivered = at
+	d.NextAttempt = time.Time{}
+	d.ResponseCode = responseCode
+	d.LastError = ""
+}
+
+// Failed records a failed attempt at the delivery, scheduling a retry with exponential backoff
+// until it has been attempted MaxDeliveryAttempts times
+func (d *Delivery) Failed(at time.Time, responseCode int, reason string) {
+	d.Attempts++
+	d.ResponseCode = responseCode
+	d.LastError = reason
+	if d.Attempts >= MaxDeliveryAttempts {
+		d.Status = DeliveryFailed
+		d.NextAttempt = time.Time{}
+		return
+	}
+	d.NextAttempt = at.Add(FirstRetry << uint(d.Attempts-1))
+}
+
+// Abandon the delivery without retrying, e.g. because its webhook has been deleted
+func (d *Delivery) Abandon(reason string) {
+	d.Status = DeliveryFailed
+	d.NextAttempt = time.Time{}
+	d.LastError = reason
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (d Delivery) GetID() string {
+	return d.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (d *Delivery) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		d.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid delivery id")
+}
+
+// GetName to satisfy jsonapi.EntityNamer interface
+func (d Delivery) GetName() string {
+	return "deliveries"
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (d Delivery) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "webhooks",
+			Name: "webhook",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (d Delivery) GetReferencedIDs() []jsonapi.ReferenceID {
+	return []jsonapi.ReferenceID{
+		{
+			ID:   d.WebhookID,
+			Name: "webhook",
+			Type: "webhooks",
+		},
+	}
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (d *Delivery) SetToOneReferenceID(name, ID string) error {
+	if name == "webhook" {
+		d.WebhookID = ID
+		return nil
+	}
+	return errors.New("There is no to-one relationship with the name " + name)
+}
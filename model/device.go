@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Platforms devices can be registered for push notifications on
+const (
+	Android = "android"
+	IOS     = "ios"
+	Web     = "web"
+)
+
+// Platforms devices can be registered on, in the order they are documented
+var Platforms = []string{Android, IOS, Web}
+
+// Device a user has registered to be sent push notifications of their car shares' events. The
+// token is the one the device was given by the push service.
+type Device struct {
+	ID       bson.ObjectId `json:"-"        bson:"_id,omitempty"`
+	Token    string        `json:"token"    bson:"token"`
+	Platform string        `json:"platform" bson:"platform"`
+	Created  time.Time     `json:"created"  bson:"created"`
+	User     *User         `json:"-"        bson:"-"`
+	UserID   string        `json:"-"        bson:"user"`
+}
+
+// Validate the device, returning an error explaining the first problem found
+func (d Device) Validate() error {
+	if d.Token == "" {
+		return errors.New("device must have a token")
+	}
+	if !contains(Platforms, d.Platform) {
+		return fmt.Errorf("unknown platform \"%s\"", d.Platform)
+	}
+	return nil
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (d Device) GetID() string {
+	return d.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (d *Device) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		d.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid device id")
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (d Device) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "users",
+			Name: "user",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (d Device) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{}
+
+	if d.UserID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   d.UserID,
+			Name: "user",
+			Type: "users",
+		})
+	}
+
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (d *Device) SetToOneReferenceID(name, ID string) error {
+	if name == "user" {
+		d.UserID = ID
+		return nil
+	}
+	return errors.New("There is no to-one relationship with the name " + name)
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unit that distances are presented in. Distances are always stored in metres.
+type Unit string
+
+// Supported distance units
+const (
+	Kilometres Unit = "km"
+	Miles      Unit = "miles"
+)
+
+// DefaultUnit for car shares that haven't chosen one
+const DefaultUnit = Kilometres
+
+// MetresPerMile by international definition, which makes conversion exact
+const MetresPerMile = 1609.344
+
+var (
+	// ErrUnknownUnit indicates a distance unit other than km or miles
+	ErrUnknownUnit = errors.New("unknown distance unit")
+
+	// ErrMissingDistance indicates a unit given without a distance to go with it
+	ErrMissingDistance = errors.New("missing distance")
+)
+
+// ParseUnit from its name, accepting common abbreviations and spellings. An empty name
+// gives the default unit.
+func ParseUnit(name string) (Unit, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "":
+		return DefaultUnit, nil
+	case "km", "kilometre", "kilometres", "kilometer", "kilometers":
+		return Kilometres, nil
+	case "mi", "mile", "miles":
+		return Miles, nil
+	default:
+		return "", ErrUnknownUnit
+	}
+}
+
+// metresPerUnit for each supported unit
+func (u Unit) metresPerUnit() float64 {
+	if u == Miles {
+		return MetresPerMile
+	}
+	return 1000
+}
+
+// ToMetres converts a distance in this unit to whole metres, rounding to the nearest metre
+func (u Unit) ToMetres(distance float64) (int, error) {
+	if distance < 0 || math.IsNaN(distance) || math.IsInf(distance, 0) {
+		return 0, fmt.Errorf("invalid distance %v %s", distance, u)
+	}
+	return int(math.Floor(distance*u.metresPerUnit() + 0.5)), nil
+}
+
+// FromMetres converts a distance in metres to this unit
+func (u Unit) FromMetres(metres int) float64 {
+	return float64(metres) / u.metresPerUnit()
+}
+
+// Format a distance in metres for display in this unit, e.g. "12.4 miles"
+func (u Unit) Format(metres int) string {
+	return strconv.FormatFloat(u.FromMetres(metres), 'f', 1, 64) + " " + string(u)
+}
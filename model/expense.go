@@ -0,0 +1,281 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// ExpenseCategory what an expense was paid for
+type ExpenseCategory string
+
+// Expense categories
+const (
+	ExpenseFuel    ExpenseCategory = "fuel"
+	ExpenseParking ExpenseCategory = "parking"
+	ExpenseToll    ExpenseCategory = "toll"
+	ExpenseOther   ExpenseCategory = "other"
+)
+
+// SplitMethod how an expense is shared between its participants
+type SplitMethod string
+
+// Split methods
+const (
+	SplitEqually    SplitMethod = "equal"
+	SplitByDistance SplitMethod = "distance"
+)
+
+// Expense paid by a member of a car share on behalf of some or all of its members. The
+// amount is in the smallest unit of the currency (e.g. pence) so that it divides exactly.
+type Expense struct {
+	ID             bson.ObjectId   `json:"-"           bson:"_id,omitempty"`
+	Description    string          `json:"description" bson:"description"`
+	Category       ExpenseCategory `json:"category"    bson:"category"`
+	Amount         int             `json:"amount"      bson:"amount"`
+	Split          SplitMethod     `json:"split"       bson:"split"`
+	TimeStamp      time.Time       `json:"timestamp"   bson:"timestamp"`
+	Shares         map[string]int  `json:"shares"      bson:"shares"`
+	CarShare       *CarShare       `json:"-"           bson:"-"`
+	CarShareID     string          `json:"-"           bson:"car-share"`
+	Payer          *User           `json:"-"           bson:"-"`
+	PayerID        string          `json:"-"           bson:"payer"`
+	Trip           *Trip           `json:"-"           bson:"-"`
+	TripID         string          `json:"-"           bson:"trip,omitempty"`
+	Participants   []*User         `json:"-"           bson:"-"`
+	ParticipantIDs []string        `json:"-"           bson:"participants"`
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (e Expense) GetID() string {
+	return e.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (e *Expense) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		e.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid expense id")
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (e Expense) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+		{
+			Type: "users",
+			Name: "payer",
+		},
+		{
+			Type: "trips",
+			Name: "trip",
+		},
+		{
+			Type: "users",
+			Name: "participants",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (e Expense) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{}
+
+	if e.CarShareID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   e.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		})
+	}
+
+	if e.PayerID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   e.PayerID,
+			Name: "payer",
+			Type: "users",
+		})
+	}
+
+	if e.TripID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   e.TripID,
+			Name: "trip",
+			Type: "trips",
+		})
+	}
+
+	for _, participantID := range e.ParticipantIDs {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   participantID,
+			Type: "users",
+			Name: "participants",
+		})
+	}
+
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (e *Expense) SetToOneReferenceID(name, ID string) error {
+	switch name {
+	case "carShare":
+		e.CarShareID = ID
+		return nil
+	case "payer":
+		e.PayerID = ID
+		return nil
+	case "trip":
+		e.TripID = ID
+		return nil
+	default:
+		return errors.New("There is no to-one relationship with the name " + name)
+	}
+}
+
+// GetReferencedStructs to satisfy jsonapi.MarshalIncludedRelations interface
+func (e Expense) GetReferencedStructs() []jsonapi.MarshalIdentifier {
+	result := []jsonapi.MarshalIdentifier{}
+
+	if e.CarShare != nil {
+		result = append(result, *e.CarShare)
+	}
+
+	if e.Payer != nil {
+		result = append(result, *e.Payer)
+	}
+
+	if e.Trip != nil {
+		result = append(result, *e.Trip)
+	}
+
+	for _, participant := range e.Participants {
+		result = append(result, participant)
+	}
+
+	return result
+}
+
+// SetToManyReferenceIDs to satisfy jsonapi.UnmarshalToManyRelations
+func (e *Expense) SetToManyReferenceIDs(name string, IDs []string) error {
+	if name == "participants" {
+		e.ParticipantIDs = append([]string{}, IDs...)
+		sort.Strings(e.ParticipantIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// AddToManyIDs to satisfy jsonapi.AddToManyIDs
+func (e *Expense) AddToManyIDs(name string, IDs []string) error {
+	if name == "participants" {
+		e.ParticipantIDs = append(e.ParticipantIDs, IDs...)
+		sort.Strings(e.ParticipantIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// DeleteToManyIDs to satisfy jsonapi.DeleteToManyIDs
+func (e *Expense) DeleteToManyIDs(name string, IDs []string) error {
+	if name == "participants" {
+		for _, ID := range IDs {
+			e.ParticipantIDs = remove(e.ParticipantIDs, ID)
+		}
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// Validate the amount, category and split method of the expense, defaulting the category
+// to other and the split to equal if they haven't been given
+func (e *Expense) Validate() error {
+
+	if e.Amount <= 0 {
+		return errors.New("expense must have an amount greater than zero")
+	}
+
+	switch e.Category {
+	case "":
+		e.Category = ExpenseOther
+	case ExpenseFuel, ExpenseParking, ExpenseToll, ExpenseOther:
+		break
+	default:
+		return fmt.Errorf("unknown expense category %s, must be fuel, parking, toll or other", e.Category)
+	}
+
+	switch e.Split {
+	case "":
+		e.Split = SplitEqually
+	case SplitEqually, SplitByDistance:
+		break
+	default:
+		return fmt.Errorf("unknown split %s, must be equal or distance", e.Split)
+	}
+
+	return nil
+}
+
+// Divide the amount into shares for each participant in proportion to their weights.
+// Participants share equally if none of them carry any weight. Any remainder is given a
+// unit at a time to those whose shares were rounded down the most, so the shares always
+// add up to the amount.
+func (e *Expense) Divide(weights map[string]int) {
+
+	e.Shares = make(map[string]int, len(e.ParticipantIDs))
+	if len(e.ParticipantIDs) == 0 {
+		return
+	}
+
+	participants := append([]string{}, e.ParticipantIDs...)
+	sort.Strings(participants)
+
+	total := 0
+	for _, participantID := range participants {
+		total += weights[participantID]
+	}
+	weight := func(participantID string) int {
+		if total == 0 {
+			return 1
+		}
+		return weights[participantID]
+	}
+	if total == 0 {
+		total = len(participants)
+	}
+
+	allocated := 0
+	remainders := make(map[string]int, len(participants))
+	for _, participantID := range participants {
+		share := e.Amount * weight(participantID)
+		e.Shares[participantID] = share / total
+		remainders[participantID] = share % total
+		allocated += share / total
+	}
+
+	sort.SliceStable(participants, func(i, j int) bool {
+		return remainders[participants[i]] > remainders[participants[j]]
+	})
+	for i := 0; allocated < e.Amount; i++ {
+		e.Shares[participants[i%len(participants)]]++
+		allocated++
+	}
+}
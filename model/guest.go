@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Guest passenger on a trip who isn't a member of the car share, such as a colleague visiting
+// for the day. Guests are known only by name and only accumulate a score if they are counted.
+type Guest struct {
+	Name    string `json:"name"    bson:"name"`
+	Counted bool   `json:"counted" bson:"counted,omitempty"`
+}
+
+// GuestKeyPrefix distinguishes the scores of guests from those of members
+const GuestKeyPrefix = "guest:"
+
+// Key identifying the guest in scores, the same on every trip they are named on
+func (g Guest) Key() string {
+	return GuestKeyPrefix + strings.ToLower(strings.TrimSpace(g.Name))
+}
+
+// Is the guest known by the given name, ignoring case and surrounding space
+func (g Guest) Is(name string) bool {
+	return g.Key() == Guest{Name: name}.Key()
+}
+
+// ValidateGuests checks every guest on the trip has a name and is only named once
+func (t Trip) ValidateGuests() error {
+	seen := make(map[string]bool, len(t.Guests))
+	for _, guest := range t.Guests {
+		if strings.TrimSpace(guest.Name) == "" {
+			return errors.New("guest must have a name")
+		}
+		if seen[guest.Key()] {
+			return fmt.Errorf("guest %s is named more than once", guest.Name)
+		}
+		seen[guest.Key()] = true
+	}
+	return nil
+}
+
+// riders is the number of passengers and counted guests on the trip, everyone other than
+// the driver who shares its cost and scores
+func (t Trip) riders() int {
+	riders := len(t.PassengerIDs)
+	for _, guest := range t.Guests {
+		if guest.Counted {
+			riders++
+		}
+	}
+	return riders
+}
+
+// HasGuest returns true if a guest with the given name was on the trip
+func (t Trip) HasGuest(name string) bool {
+	for _, guest := range t.Guests {
+		if guest.Is(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// PromoteGuest replaces the named guest with the user they have become, as a passenger who
+// has already confirmed the trip. Returns false if the guest wasn't on the trip.
+func (t *Trip) PromoteGuest(name string, userID string) bool {
+	for i, guest := range t.Guests {
+		if guest.Is(name) {
+			t.Guests = append(t.Guests[:i], t.Guests[i+1:]...)
+			if len(t.Guests) == 0 {
+				t.Guests = nil
+			}
+			if !contains(t.PassengerIDs, userID) {
+				t.PassengerIDs = append(t.PassengerIDs, userID)
+				sort.Strings(t.PassengerIDs)
+			}
+			if !contains(t.ConfirmedBy, userID) {
+				t.ConfirmedBy = append(t.ConfirmedBy, userID)
+			}
+			return true
+		}
+	}
+	return false
+}
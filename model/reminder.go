@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Reminder sent to the members of a car share on the days they expect to commute, if no
+// trip has been logged by the given time of day
+type Reminder struct {
+	Weekdays []string `json:"weekdays" bson:"weekdays"`
+	Time     string   `json:"time"     bson:"time"`
+	TimeZone string   `json:"timezone" bson:"timezone"`
+}
+
+// schedule the reminder runs to, which has the same rules as a trip schedule
+func (r Reminder) schedule() Schedule {
+	return Schedule{Weekdays: r.Weekdays, Time: r.Time, TimeZone: r.TimeZone}
+}
+
+// Validate the weekdays, time of day and time zone of the reminder
+func (r Reminder) Validate() error {
+	return r.schedule().Validate()
+}
+
+// NextOccurrence of the reminder after the given time, in UTC
+func (r Reminder) NextOccurrence(after time.Time) (time.Time, error) {
+	return r.schedule().NextOccurrence(after)
+}
+
+// Day the reminder at the given time covers, from midnight to midnight in its time zone
+func (r Reminder) Day(at time.Time) (time.Time, time.Time, error) {
+	location, err := time.LoadLocation(r.TimeZone)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	local := at.In(location)
+	start := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, location)
+	return start.UTC(), start.AddDate(0, 0, 1).UTC(), nil
+}
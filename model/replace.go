@@ -0,0 +1,112 @@
+package model
+
+// ReplaceUser on the trip, as its driver, passenger, in its confirmations and disputes and in
+// its scores. Returns true if the user was on the trip.
+func (t *Trip) ReplaceUser(from string, to string) bool {
+	changed := false
+	if t.DriverID == from {
+		t.DriverID = to
+		changed = true
+	}
+	changed = replaceID(&t.PassengerIDs, from, to) || changed
+	if contains(t.PassengerIDs, t.DriverID) {
+		t.PassengerIDs = remove(t.PassengerIDs, t.DriverID)
+	}
+	changed = replaceID(&t.ConfirmedBy, from, to) || changed
+	changed = replaceID(&t.DisputedBy, from, to) || changed
+	if score, ok := t.Scores[from]; ok {
+		merged := t.Scores[to]
+		merged.MetresAsDriver += score.MetresAsDriver
+		merged.MetresAsPassenger += score.MetresAsPassenger
+		merged.PointsAsDriver += score.PointsAsDriver
+		merged.PointsAsPassenger += score.PointsAsPassenger
+		merged.CostAsDriver += score.CostAsDriver
+		merged.CostAsPassenger += score.CostAsPassenger
+		delete(t.Scores, from)
+		t.Scores[to] = merged
+		changed = true
+	}
+	return changed
+}
+
+// ReplaceUser in the members and admins of the car share. Returns true if the user was
+// either.
+func (cs *CarShare) ReplaceUser(from string, to string) bool {
+	changed := replaceID(&cs.MemberIDs, from, to)
+	changed = replaceID(&cs.AdminIDs, from, to) || changed
+	return changed
+}
+
+// ReplaceUser as the driver or a passenger of the route. Returns true if the user was either.
+func (rt *Route) ReplaceUser(from string, to string) bool {
+	changed := false
+	if rt.DriverID == from {
+		rt.DriverID = to
+		changed = true
+	}
+	changed = replaceID(&rt.PassengerIDs, from, to) || changed
+	if contains(rt.PassengerIDs, rt.DriverID) {
+		rt.PassengerIDs = remove(rt.PassengerIDs, rt.DriverID)
+	}
+	return changed
+}
+
+// ReplaceUser as the driver or a passenger of the schedule. Returns true if the user was
+// either.
+func (s *Schedule) ReplaceUser(from string, to string) bool {
+	changed := false
+	if s.DriverID == from {
+		s.DriverID = to
+		changed = true
+	}
+	changed = replaceID(&s.PassengerIDs, from, to) || changed
+	if contains(s.PassengerIDs, s.DriverID) {
+		s.PassengerIDs = remove(s.PassengerIDs, s.DriverID)
+	}
+	return changed
+}
+
+// ReplaceUser as the owner or a driver of the vehicle. Returns true if the user was either.
+func (v *Vehicle) ReplaceUser(from string, to string) bool {
+	changed := false
+	if v.OwnerID == from {
+		v.OwnerID = to
+		changed = true
+	}
+	return replaceID(&v.DriverIDs, from, to) || changed
+}
+
+// ReplaceUser as the payer or a participant of the expense, combining their shares if both
+// users took part. Returns true if the user was either.
+func (e *Expense) ReplaceUser(from string, to string) bool {
+	changed := false
+	if e.PayerID == from {
+		e.PayerID = to
+		changed = true
+	}
+	changed = replaceID(&e.ParticipantIDs, from, to) || changed
+	if share, ok := e.Shares[from]; ok {
+		delete(e.Shares, from)
+		e.Shares[to] += share
+		changed = true
+	}
+	return changed
+}
+
+// replaceID in a list of IDs, without listing the replacement twice. Returns true if the ID
+// was in the list.
+func replaceID(IDs *[]string, from string, to string) bool {
+	if !contains(*IDs, from) {
+		return false
+	}
+	if contains(*IDs, to) {
+		*IDs = remove(*IDs, from)
+		return true
+	}
+	for i, ID := range *IDs {
+		if ID == from {
+			(*IDs)[i] = to
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Route a journey a car share makes regularly, used to fill in the details of new trips
+type Route struct {
+	ID           bson.ObjectId `json:"-"      bson:"_id,omitempty"`
+	Name         string        `json:"name"   bson:"name"`
+	Metres       int           `json:"metres" bson:"metres"`
+	CarShare     *CarShare     `json:"-"      bson:"-"`
+	CarShareID   string        `json:"-"      bson:"car-share"`
+	Driver       *User         `json:"-"      bson:"-"`
+	DriverID     string        `json:"-"      bson:"driver"`
+	Passengers   []*User       `json:"-"      bson:"-"`
+	PassengerIDs []string      `json:"-"      bson:"passengers"`
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (rt Route) GetID() string {
+	return rt.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (rt *Route) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		rt.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid route id")
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (rt Route) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+		{
+			Type: "users",
+			Name: "driver",
+		},
+		{
+			Type: "users",
+			Name: "passengers",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (rt Route) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{}
+
+	if rt.CarShareID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   rt.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		})
+	}
+
+	if rt.DriverID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   rt.DriverID,
+			Name: "driver",
+			Type: "users",
+		})
+	}
+
+	for _, passengerID := range rt.PassengerIDs {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   passengerID,
+			Type: "users",
+			Name: "passengers",
+		})
+	}
+
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (rt *Route) SetToOneReferenceID(name, ID string) error {
+	switch name {
+	case "carShare":
+		rt.CarShareID = ID
+		return nil
+	case "driver":
+		rt.DriverID = ID
+		return nil
+	default:
+		return errors.New("There is no to-one relationship with the name " + name)
+	}
+}
+
+// GetReferencedStructs to satisfy jsonapi.MarshalIncludedRelations interface
+func (rt Route) GetReferencedStructs() []jsonapi.MarshalIdentifier {
+	result := []jsonapi.MarshalIdentifier{}
+
+	if rt.CarShare != nil {
+		result = append(result, *rt.CarShare)
+	}
+
+	if rt.Driver != nil {
+		result = append(result, *rt.Driver)
+	}
+
+	for _, passenger := range rt.Passengers {
+		result = append(result, passenger)
+	}
+
+	return result
+}
+
+// SetToManyReferenceIDs to satisfy jsonapi.UnmarshalToManyRelations
+func (rt *Route) SetToManyReferenceIDs(name string, IDs []string) error {
+	if name == "passengers" {
+		rt.PassengerIDs = append([]string{}, IDs...)
+		sort.Strings(rt.PassengerIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// AddToManyIDs to satisfy jsonapi.AddToManyIDs
+func (rt *Route) AddToManyIDs(name string, IDs []string) error {
+	if name == "passengers" {
+		rt.PassengerIDs = append(rt.PassengerIDs, IDs...)
+		sort.Strings(rt.PassengerIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// DeleteToManyIDs to satisfy jsonapi.DeleteToManyIDs
+func (rt *Route) DeleteToManyIDs(name string, IDs []string) error {
+	if name == "passengers" {
+		for _, ID := range IDs {
+			for pos, passengerID := range rt.PassengerIDs {
+				if ID == passengerID {
+					rt.PassengerIDs = append(rt.PassengerIDs[:pos], rt.PassengerIDs[pos+1:]...)
+					break
+				}
+			}
+		}
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
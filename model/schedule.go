@@ -0,0 +1,259 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Schedule a trip a car share plans to make regularly. The scheduler turns each
+// occurrence into a draft trip for members to confirm or cancel.
+type Schedule struct {
+	ID           bson.ObjectId `json:"-"        bson:"_id,omitempty"`
+	Name         string        `json:"name"     bson:"name"`
+	Weekdays     []string      `json:"weekdays" bson:"weekdays"`
+	Time         string        `json:"time"     bson:"time"`
+	TimeZone     string        `json:"timezone" bson:"timezone"`
+	Metres       int           `json:"metres"   bson:"metres"`
+	Next         time.Time     `json:"next"     bson:"next"`
+	CarShare     *CarShare     `json:"-"        bson:"-"`
+	CarShareID   string        `json:"-"        bson:"car-share"`
+	RouteID      string        `json:"-"        bson:"route,omitempty"`
+	Driver       *User         `json:"-"        bson:"-"`
+	DriverID     string        `json:"-"        bson:"driver"`
+	Passengers   []*User       `json:"-"        bson:"-"`
+	PassengerIDs []string      `json:"-"        bson:"passengers"`
+}
+
+// weekdays by lower case name
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (s Schedule) GetID() string {
+	return s.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (s *Schedule) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		s.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid schedule id")
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (s Schedule) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+		{
+			Type: "routes",
+			Name: "route",
+		},
+		{
+			Type: "users",
+			Name: "driver",
+		},
+		{
+			Type: "users",
+			Name: "passengers",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (s Schedule) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{}
+
+	if s.CarShareID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   s.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		})
+	}
+
+	if s.RouteID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   s.RouteID,
+			Name: "route",
+			Type: "routes",
+		})
+	}
+
+	if s.DriverID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   s.DriverID,
+			Name: "driver",
+			Type: "users",
+		})
+	}
+
+	for _, passengerID := range s.PassengerIDs {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   passengerID,
+			Type: "users",
+			Name: "passengers",
+		})
+	}
+
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (s *Schedule) SetToOneReferenceID(name, ID string) error {
+	switch name {
+	case "carShare":
+		s.CarShareID = ID
+		return nil
+	case "route":
+		s.RouteID = ID
+		return nil
+	case "driver":
+		s.DriverID = ID
+		return nil
+	default:
+		return errors.New("There is no to-one relationship with the name " + name)
+	}
+}
+
+// GetReferencedStructs to satisfy jsonapi.MarshalIncludedRelations interface
+func (s Schedule) GetReferencedStructs() []jsonapi.MarshalIdentifier {
+	result := []jsonapi.MarshalIdentifier{}
+
+	if s.CarShare != nil {
+		result = append(result, *s.CarShare)
+	}
+
+	if s.Driver != nil {
+		result = append(result, *s.Driver)
+	}
+
+	for _, passenger := range s.Passengers {
+		result = append(result, passenger)
+	}
+
+	return result
+}
+
+// SetToManyReferenceIDs to satisfy jsonapi.UnmarshalToManyRelations
+func (s *Schedule) SetToManyReferenceIDs(name string, IDs []string) error {
+	if name == "passengers" {
+		s.PassengerIDs = append([]string{}, IDs...)
+		sort.Strings(s.PassengerIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// AddToManyIDs to satisfy jsonapi.AddToManyIDs
+func (s *Schedule) AddToManyIDs(name string, IDs []string) error {
+	if name == "passengers" {
+		s.PassengerIDs = append(s.PassengerIDs, IDs...)
+		sort.Strings(s.PassengerIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// DeleteToManyIDs to satisfy jsonapi.DeleteToManyIDs
+func (s *Schedule) DeleteToManyIDs(name string, IDs []string) error {
+	if name == "passengers" {
+		for _, ID := range IDs {
+			for pos, passengerID := range s.PassengerIDs {
+				if ID == passengerID {
+					s.PassengerIDs = append(s.PassengerIDs[:pos], s.PassengerIDs[pos+1:]...)
+					break
+				}
+			}
+		}
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// Validate the weekdays, time of day and time zone of the schedule
+func (s Schedule) Validate() error {
+	if len(s.Weekdays) == 0 {
+		return errors.New("schedule must run on at least one weekday")
+	}
+	for _, day := range s.Weekdays {
+		if _, ok := weekdays[strings.ToLower(day)]; !ok {
+			return fmt.Errorf("unknown weekday \"%s\"", day)
+		}
+	}
+	if _, err := time.Parse("15:04", s.Time); err != nil {
+		return fmt.Errorf("invalid time \"%s\", expected HH:MM", s.Time)
+	}
+	if _, err := time.LoadLocation(s.TimeZone); err != nil {
+		return fmt.Errorf("unknown time zone \"%s\"", s.TimeZone)
+	}
+	return nil
+}
+
+// NextOccurrence of the schedule after the given time, in UTC
+func (s Schedule) NextOccurrence(after time.Time) (time.Time, error) {
+
+	if err := s.Validate(); err != nil {
+		return time.Time{}, err
+	}
+
+	location, _ := time.LoadLocation(s.TimeZone)
+	timeOfDay, _ := time.Parse("15:04", s.Time)
+	runsOn := make(map[time.Weekday]bool)
+	for _, day := range s.Weekdays {
+		runsOn[weekdays[strings.ToLower(day)]] = true
+	}
+
+	local := after.In(location)
+	for offset := 0; offset <= 7; offset++ {
+		day := local.AddDate(0, 0, offset)
+		occurrence := time.Date(day.Year(), day.Month(), day.Day(), timeOfDay.Hour(), timeOfDay.Minute(), 0, 0, location)
+		if occurrence.After(after) && runsOn[occurrence.Weekday()] {
+			return occurrence.UTC(), nil
+		}
+	}
+
+	// unreachable for a valid schedule
+	return time.Time{}, errors.New("schedule has no next occurrence")
+}
+
+// Draft trip for an occurrence of the schedule
+func (s Schedule) Draft(occurrence time.Time) Trip {
+	return Trip{
+		Metres:       s.Metres,
+		TimeStamp:    occurrence,
+		Status:       TripDraft,
+		CarShareID:   s.CarShareID,
+		DriverID:     s.DriverID,
+		PassengerIDs: append([]string{}, s.PassengerIDs...),
+		RouteID:      s.RouteID,
+		ScheduleID:   s.GetID(),
+		Scores:       make(map[string]Score),
+	}
+}
@@ -1,7 +1,21 @@
 package model
 
-// A score keeps track of how many miles a user has travelled as a driver and as a passenger
+// A score keeps track of how many miles a user has travelled as a driver and as a passenger,
+// the points those trips were worth under the car share's scoring strategy, and the
+// contributions towards the cost of those trips they are owed as a driver and owe as a passenger
 type Score struct {
-	MetresAsDriver    int `json:"metres-as-driver"    bson:"metres-as-driver"`
-	MetresAsPassenger int `json:"metres-as-passenger" bson:"metres-as-passenger"`
+	MetresAsDriver       int    `json:"metres-as-driver"                 bson:"metres-as-driver"`
+	MetresAsPassenger    int    `json:"metres-as-passenger"              bson:"metres-as-passenger"`
+	PointsAsDriver       int    `json:"points-as-driver"                 bson:"points-as-driver"`
+	PointsAsPassenger    int    `json:"points-as-passenger"              bson:"points-as-passenger"`
+	CostAsDriver         int    `json:"cost-as-driver"                   bson:"cost-as-driver,omitempty"`
+	CostAsPassenger      int    `json:"cost-as-passenger"                bson:"cost-as-passenger,omitempty"`
+	FormattedAsDriver    string `json:"formatted-as-driver,omitempty"    bson:"-"`
+	FormattedAsPassenger string `json:"formatted-as-passenger,omitempty" bson:"-"`
+}
+
+// FormatDistances presents the score in the given unit
+func (s *Score) FormatDistances(unit Unit) {
+	s.FormattedAsDriver = unit.Format(s.MetresAsDriver)
+	s.FormattedAsPassenger = unit.Format(s.MetresAsPassenger)
 }
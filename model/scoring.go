@@ -0,0 +1,87 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// ScoringStrategy decides how many points a trip is worth to its driver and to each of its
+// passengers. Points accumulate in the scores alongside the distances travelled.
+type ScoringStrategy interface {
+	Points(trip Trip) (asDriver int, asPassenger int)
+}
+
+// Scoring names one of the built in scoring strategies a car share can choose
+type Scoring string
+
+// Built in scoring strategies
+const (
+	// DistanceScoring is worth the distance of the trip to everyone in the car
+	DistanceScoring Scoring = "distance"
+	// PassengerDistanceScoring is worth the distance of the trip for each passenger or
+	// counted guest carried to the driver, so a full car counts for more than an empty one
+	PassengerDistanceScoring Scoring = "passenger-distance"
+	// TripScoring is worth one point to everyone in the car, however far they went
+	TripScoring Scoring = "trips"
+	// ShortTripScoring is worth the distance of the trip, discounted by half for trips
+	// shorter than ShortTripMetres
+	ShortTripScoring Scoring = "short-trip-discount"
+)
+
+// DefaultScoring for car shares that haven't chosen a strategy
+const DefaultScoring = DistanceScoring
+
+// ShortTripMetres below which trips are discounted by ShortTripScoring
+const ShortTripMetres = 5000
+
+var (
+	// ErrUnknownScoring indicates a scoring strategy that isn't built in
+	ErrUnknownScoring = errors.New("unknown scoring strategy")
+)
+
+// ParseScoring from its name. An empty name gives the default strategy.
+func ParseScoring(name string) (Scoring, error) {
+	scoring := Scoring(strings.ToLower(strings.TrimSpace(name)))
+	if scoring == "" {
+		return DefaultScoring, nil
+	}
+	if _, ok := strategies[scoring]; !ok {
+		return "", ErrUnknownScoring
+	}
+	return scoring, nil
+}
+
+// Strategy named by the scoring, or the default strategy if it isn't built in
+func (s Scoring) Strategy() ScoringStrategy {
+	strategy, ok := strategies[s]
+	if !ok {
+		return strategies[DefaultScoring]
+	}
+	return strategy
+}
+
+// pointsFunc lets a plain function be used as a ScoringStrategy
+type pointsFunc func(trip Trip) (int, int)
+
+// Points to satisfy the ScoringStrategy interface
+func (f pointsFunc) Points(trip Trip) (int, int) {
+	return f(trip)
+}
+
+var strategies = map[Scoring]ScoringStrategy{
+	DistanceScoring: pointsFunc(func(trip Trip) (int, int) {
+		return trip.Metres, trip.Metres
+	}),
+	PassengerDistanceScoring: pointsFunc(func(trip Trip) (int, int) {
+		return trip.Metres * trip.riders(), trip.Metres
+	}),
+	TripScoring: pointsFunc(func(trip Trip) (int, int) {
+		return 1, 1
+	}),
+	ShortTripScoring: pointsFunc(func(trip Trip) (int, int) {
+		if trip.Metres < ShortTripMetres {
+			return trip.Metres / 2, trip.Metres / 2
+		}
+		return trip.Metres, trip.Metres
+	}),
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"errors"
+
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// Standings of the members of a car share. All time scores are those of its latest trip,
+// windowed scores only count the trips within its rolling window, decayed by their age.
+// Standings are identified by their car share.
+type Standings struct {
+	CarShareID   string           `json:"-"`
+	AllTime      map[string]Score `json:"all-time"`
+	Windowed     map[string]Score `json:"windowed"`
+	WindowDays   int              `json:"window-days"`
+	HalfLifeDays int              `json:"half-life-days"`
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (s Standings) GetID() string {
+	return s.CarShareID
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (s *Standings) SetID(id string) error {
+	s.CarShareID = id
+	return nil
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (s Standings) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (s Standings) GetReferencedIDs() []jsonapi.ReferenceID {
+	return []jsonapi.ReferenceID{
+		{
+			ID:   s.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		},
+	}
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (s *Standings) SetToOneReferenceID(name, ID string) error {
+	if name == "carShare" {
+		s.CarShareID = ID
+		return nil
+	}
+	return errors.New("There is no to-one relationship with the name " + name)
+}
+
+// FormatDistances presents every score in the given unit
+func (s *Standings) FormatDistances(unit Unit) {
+	for _, scores := range []map[string]Score{s.AllTime, s.Windowed} {
+		for userID, score := range scores {
+			score.FormatDistances(unit)
+			scores[userID] = score
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// CoPassengerLimit is the number of most frequent co-passengers kept in a user's stats
+const CoPassengerLimit = 5
+
+// Stats of a user across every car share they belong to, only counting trips that count
+// towards scores. Stats are identified by their user.
+type Stats struct {
+	UserID            string        `json:"-"`
+	MetresAsDriver    int           `json:"metres-as-driver"`
+	MetresAsPassenger int           `json:"metres-as-passenger"`
+	TripsAsDriver     int           `json:"trips-as-driver"`
+	TripsAsPassenger  int           `json:"trips-as-passenger"`
+	CoPassengers      []CoPassenger `json:"co-passengers"`
+	Months            []MonthStats  `json:"months"`
+	CarShareIDs       []string      `json:"-"`
+}
+
+// CoPassenger someone a user shared trips with, whether they drove or were a passenger
+type CoPassenger struct {
+	UserID string `json:"user"  bson:"_id"`
+	Trips  int    `json:"trips" bson:"trips"`
+}
+
+// MonthStats of a user for a single calendar month, formatted as YYYY-MM
+type MonthStats struct {
+	Month             string `json:"month"               bson:"_id"`
+	MetresAsDriver    int    `json:"metres-as-driver"    bson:"metres-as-driver"`
+	MetresAsPassenger int    `json:"metres-as-passenger" bson:"metres-as-passenger"`
+	TripsAsDriver     int    `json:"trips-as-driver"     bson:"trips-as-driver"`
+	TripsAsPassenger  int    `json:"trips-as-passenger"  bson:"trips-as-passenger"`
+}
+
+// Total the monthly stats into the overall stats
+func (s *Stats) Total() {
+	s.MetresAsDriver, s.MetresAsPassenger, s.TripsAsDriver, s.TripsAsPassenger = 0, 0, 0, 0
+	for _, month := range s.Months {
+		s.MetresAsDriver += month.MetresAsDriver
+		s.MetresAsPassenger += month.MetresAsPassenger
+		s.TripsAsDriver += month.TripsAsDriver
+		s.TripsAsPassenger += month.TripsAsPassenger
+	}
+}
+
+// SortCoPassengers most frequent first, breaking ties by user id, keeping at most
+// CoPassengerLimit of them
+func (s *Stats) SortCoPassengers() {
+	sort.Slice(s.CoPassengers, func(i, j int) bool {
+		if s.CoPassengers[i].Trips != s.CoPassengers[j].Trips {
+			return s.CoPassengers[i].Trips > s.CoPassengers[j].Trips
+		}
+		return s.CoPassengers[i].UserID < s.CoPassengers[j].UserID
+	})
+	if len(s.CoPassengers) > CoPassengerLimit {
+		s.CoPassengers = s.CoPassengers[:CoPassengerLimit]
+	}
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (s Stats) GetID() string {
+	return s.UserID
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (s *Stats) SetID(id string) error {
+	s.UserID = id
+	return nil
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (s Stats) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "users",
+			Name: "user",
+		},
+		{
+			Type: "carShares",
+			Name: "carShares",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (s Stats) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{
+		{
+			ID:   s.UserID,
+			Name: "user",
+			Type: "users",
+		},
+	}
+	for _, carShareID := range s.CarShareIDs {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   carShareID,
+			Name: "carShares",
+			Type: "carShares",
+		})
+	}
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (s *Stats) SetToOneReferenceID(name, ID string) error {
+	if name == "user" {
+		s.UserID = ID
+		return nil
+	}
+	return errors.New("There is no to-one relationship with the name " + name)
+}
@@ -10,18 +10,46 @@ import (
 	"github.com/manyminds/api2go/jsonapi"
 )
 
+// TripStatus whether a trip counts towards the scores of its car share
+type TripStatus string
+
+// Trip statuses. Trips without a status predate them and are treated as confirmed.
+const (
+	TripDraft     TripStatus = "draft"
+	TripConfirmed TripStatus = "confirmed"
+	TripDisputed  TripStatus = "disputed"
+)
+
+// Counts towards scores
+func (s TripStatus) Counts() bool {
+	return s == TripConfirmed || s == ""
+}
+
 // Trip - a single instance of a car share
 type Trip struct {
-	ID           bson.ObjectId    `json:"-"         bson:"_id,omitempty"`
-	Metres       int              `json:"metres"    bson:"metres"`
-	TimeStamp    time.Time        `json:"timestamp" bson:"timestamp"`
-	CarShare     *CarShare        `json:"-"         bson:"-"`
-	CarShareID   string           `json:"-"         bson:"car-share"`
-	Driver       *User            `json:"-"         bson:"-"`
-	DriverID     string           `json:"-"         bson:"driver"`
-	Passengers   []*User          `json:"-"         bson:"-"`
-	PassengerIDs []string         `json:"-"         bson:"passengers"`
-	Scores       map[string]Score `json:"scores"    bson:"scores"`
+	ID           bson.ObjectId    `json:"-"                            bson:"_id,omitempty"`
+	Metres       int              `json:"metres"                       bson:"metres"`
+	Distance     float64          `json:"distance,omitempty"           bson:"-"`
+	Unit         Unit             `json:"unit,omitempty"               bson:"-"`
+	Formatted    string           `json:"formatted-distance,omitempty" bson:"-"`
+	TimeStamp    time.Time        `json:"timestamp"                    bson:"timestamp"`
+	Cost         int              `json:"cost,omitempty"               bson:"cost,omitempty"`
+	Contribution int              `json:"contribution,omitempty"       bson:"contribution,omitempty"`
+	Status       TripStatus       `json:"status"                       bson:"status,omitempty"`
+	ConfirmedBy  []string         `json:"confirmed-by"                 bson:"confirmed-by,omitempty"`
+	DisputedBy   []string         `json:"disputed-by"                  bson:"disputed-by,omitempty"`
+	CarShare     *CarShare        `json:"-"                            bson:"-"`
+	CarShareID   string           `json:"-"                            bson:"car-share"`
+	Driver       *User            `json:"-"                            bson:"-"`
+	DriverID     string           `json:"-"                            bson:"driver"`
+	Passengers   []*User          `json:"-"                            bson:"-"`
+	PassengerIDs []string         `json:"-"                            bson:"passengers"`
+	Guests       []Guest          `json:"guests"                       bson:"guests,omitempty"`
+	RouteID      string           `json:"-"                            bson:"route,omitempty"`
+	ScheduleID   string           `json:"-"                            bson:"schedule,omitempty"`
+	Vehicle      *Vehicle         `json:"-"                            bson:"-"`
+	VehicleID    string           `json:"-"                            bson:"vehicle,omitempty"`
+	Scores       map[string]Score `json:"scores"                       bson:"scores"`
 }
 
 // GetID to satisfy jsonapi.MarshalIdentifier interface
@@ -61,6 +89,18 @@ func (t Trip) GetReferences() []jsonapi.Reference {
 			Type: "users",
 			Name: "passengers",
 		},
+		{
+			Type: "routes",
+			Name: "route",
+		},
+		{
+			Type: "schedules",
+			Name: "schedule",
+		},
+		{
+			Type: "vehicles",
+			Name: "vehicle",
+		},
 	}
 }
 
@@ -92,6 +132,30 @@ func (t Trip) GetReferencedIDs() []jsonapi.ReferenceID {
 		})
 	}
 
+	if t.RouteID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   t.RouteID,
+			Name: "route",
+			Type: "routes",
+		})
+	}
+
+	if t.ScheduleID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   t.ScheduleID,
+			Name: "schedule",
+			Type: "schedules",
+		})
+	}
+
+	if t.VehicleID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   t.VehicleID,
+			Name: "vehicle",
+			Type: "vehicles",
+		})
+	}
+
 	return result
 }
 
@@ -104,6 +168,15 @@ func (t *Trip) SetToOneReferenceID(name, ID string) error {
 	case "driver":
 		t.DriverID = ID
 		return nil
+	case "route":
+		t.RouteID = ID
+		return nil
+	case "schedule":
+		t.ScheduleID = ID
+		return nil
+	case "vehicle":
+		t.VehicleID = ID
+		return nil
 	default:
 		return errors.New("There is no to-one relationship with the name " + name)
 	}
@@ -125,6 +198,10 @@ func (t Trip) GetReferencedStructs() []jsonapi.MarshalIdentifier {
 		result = append(result, passenger)
 	}
 
+	if t.Vehicle != nil {
+		result = append(result, *t.Vehicle)
+	}
+
 	return result
 }
 
@@ -171,14 +248,143 @@ func (t *Trip) DeleteToManyIDs(name string, IDs []string) error {
 	return errors.New("There is no to-many relationship with the name " + name)
 }
 
+// ConvertDistance sets Metres from a distance given in another unit. Trips without a
+// unit are assumed to already be in metres, and a unit needs a distance to go with it.
+func (t *Trip) ConvertDistance() error {
+	if t.Unit == "" {
+		return nil
+	}
+	unit, err := ParseUnit(string(t.Unit))
+	if err != nil {
+		return err
+	}
+	if t.Distance <= 0 {
+		return ErrMissingDistance
+	}
+	metres, err := unit.ToMetres(t.Distance)
+	if err != nil {
+		return err
+	}
+	t.Metres = metres
+	return nil
+}
+
+// FormatDistances presents the trip distance and scores in the given unit
+func (t *Trip) FormatDistances(unit Unit) {
+	t.Unit = unit
+	t.Distance = unit.FromMetres(t.Metres)
+	t.Formatted = unit.Format(t.Metres)
+	for userID, score := range t.Scores {
+		score.FormatDistances(unit)
+		t.Scores[userID] = score
+	}
+}
+
+// FollowRoute fills in the details of the trip that haven't been given from a route
+func (t *Trip) FollowRoute(route Route) {
+	t.RouteID = route.GetID()
+	if t.Metres == 0 {
+		t.Metres = route.Metres
+	}
+	if t.DriverID == "" {
+		t.DriverID = route.DriverID
+	}
+	if len(t.PassengerIDs) == 0 {
+		t.PassengerIDs = append([]string{}, route.PassengerIDs...)
+	}
+}
+
+// Price the trip in the vehicle it was made in. The cost is shared equally between
+// everyone in the car, with each passenger and counted guest contributing their share to
+// the driver.
+func (t *Trip) Price(vehicle Vehicle, prices FuelPrices) {
+	t.Cost = vehicle.Cost(t.Metres, prices)
+	t.Contribution = 0
+	if riders := t.riders(); riders > 0 {
+		occupants := riders + 1
+		t.Contribution = (t.Cost + occupants/2) / occupants
+	}
+}
+
+// IsParticipant returns true if the user is the driver or a passenger of the trip
+func (t Trip) IsParticipant(userID string) bool {
+	if t.DriverID == userID {
+		return true
+	}
+	return contains(t.PassengerIDs, userID)
+}
+
+// AwaitingConfirmation returns the passengers who have yet to confirm they were in the car
+func (t Trip) AwaitingConfirmation() []string {
+	awaiting := []string{}
+	for _, passengerID := range t.PassengerIDs {
+		if !contains(t.ConfirmedBy, passengerID) {
+			awaiting = append(awaiting, passengerID)
+		}
+	}
+	return awaiting
+}
+
+// Confirm records that a participant agrees with the trip, withdrawing any dispute they
+// raised. Once every passenger has confirmed and nobody disputes it the trip is confirmed.
+func (t *Trip) Confirm(userID string) {
+	if t.IsParticipant(userID) && !contains(t.ConfirmedBy, userID) {
+		t.ConfirmedBy = append(t.ConfirmedBy, userID)
+		sort.Strings(t.ConfirmedBy)
+	}
+	t.DisputedBy = remove(t.DisputedBy, userID)
+	t.resolve()
+}
+
+// Dispute records that a participant disagrees with the trip, excluding it from the
+// scores until the dispute is resolved
+func (t *Trip) Dispute(userID string) {
+	if !contains(t.DisputedBy, userID) {
+		t.DisputedBy = append(t.DisputedBy, userID)
+		sort.Strings(t.DisputedBy)
+	}
+	t.ConfirmedBy = remove(t.ConfirmedBy, userID)
+	t.Status = TripDisputed
+}
+
+// Override confirms the trip regardless of outstanding confirmations or disputes
+func (t *Trip) Override() {
+	t.DisputedBy = nil
+	t.Status = TripConfirmed
+}
+
+// resolve the status of the trip from its confirmations and disputes
+func (t *Trip) resolve() {
+	switch {
+	case len(t.DisputedBy) > 0:
+		t.Status = TripDisputed
+	case len(t.AwaitingConfirmation()) > 0:
+		t.Status = TripDraft
+	default:
+		t.Status = TripConfirmed
+	}
+}
+
 // CalculateScores for the trip (basically the ratio between distance travelled as driver
-// and as passenger)
-func (t *Trip) CalculateScores(scoresFromLastTrip map[string]Score) error {
+// and as passenger), with points awarded by the car share's scoring strategy. A nil
+// strategy uses the default.
+func (t *Trip) CalculateScores(scoresFromLastTrip map[string]Score, strategy ScoringStrategy) error {
+
+	if strategy == nil {
+		strategy = DefaultScoring.Strategy()
+	}
 
 	if scoresFromLastTrip != nil {
 		t.Scores = scoresFromLastTrip
 	}
 
+	// scores carry through draft and disputed trips unchanged
+	if !t.Status.Counts() {
+		return nil
+	}
+
+	pointsAsDriver, pointsAsPassenger := strategy.Points(*t)
+
 	if t.DriverID != "" {
 		driverScore, ok := t.Scores[t.DriverID]
 		if ok {
@@ -186,6 +392,8 @@ func (t *Trip) CalculateScores(scoresFromLastTrip map[string]Score) error {
 		} else {
 			driverScore = Score{MetresAsDriver: t.Metres, MetresAsPassenger: 0}
 		}
+		driverScore.PointsAsDriver += pointsAsDriver
+		driverScore.CostAsDriver += t.Contribution * t.riders()
 		t.Scores[t.DriverID] = driverScore
 	}
 
@@ -196,8 +404,45 @@ func (t *Trip) CalculateScores(scoresFromLastTrip map[string]Score) error {
 		} else {
 			passengerScore = Score{MetresAsDriver: 0, MetresAsPassenger: t.Metres}
 		}
+		passengerScore.PointsAsPassenger += pointsAsPassenger
+		passengerScore.CostAsPassenger += t.Contribution
 		t.Scores[passengerID] = passengerScore
 	}
 
+	for _, guest := range t.Guests {
+		if !guest.Counted {
+			continue
+		}
+		guestScore := t.Scores[guest.Key()]
+		guestScore.MetresAsPassenger += t.Metres
+		guestScore.PointsAsPassenger += pointsAsPassenger
+		guestScore.CostAsPassenger += t.Contribution
+		t.Scores[guest.Key()] = guestScore
+	}
+
 	return nil
 }
+
+// contains returns true if the ID is in the list
+func contains(IDs []string, ID string) bool {
+	for _, candidate := range IDs {
+		if candidate == ID {
+			return true
+		}
+	}
+	return false
+}
+
+// remove returns the list without the ID
+func remove(IDs []string, ID string) []string {
+	result := IDs[:0]
+	for _, candidate := range IDs {
+		if candidate != ID {
+			result = append(result, candidate)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
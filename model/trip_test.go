@@ -0,0 +1,83 @@
+package model
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trip", func() {
+
+	var trip Trip
+
+	BeforeEach(func() {
+		trip = Trip{Metres: 1234}
+	})
+
+	Describe("convert distance", func() {
+
+		It("should leave trips without a unit in metres", func() {
+			trip.Distance = 2.5
+			Expect(trip.ConvertDistance()).To(Succeed())
+			Expect(trip.Metres).To(Equal(1234))
+		})
+
+		It("should convert a distance in miles to metres", func() {
+			trip.Distance = 2.5
+			trip.Unit = Miles
+			Expect(trip.ConvertDistance()).To(Succeed())
+			Expect(trip.Metres).To(Equal(4023))
+		})
+
+		It("should reject a unit without a distance", func() {
+			trip.Unit = Kilometres
+			Expect(trip.ConvertDistance()).To(MatchError(ErrMissingDistance))
+			Expect(trip.Metres).To(Equal(1234))
+		})
+
+		It("should reject an unknown unit", func() {
+			trip.Distance = 2.5
+			trip.Unit = Unit("leagues")
+			Expect(trip.ConvertDistance()).To(MatchError(ErrUnknownUnit))
+			Expect(trip.Metres).To(Equal(1234))
+		})
+
+	})
+
+	Describe("with guests", func() {
+
+		var guest, visitor Guest
+
+		BeforeEach(func() {
+			guest = Guest{Name: "Colleague", Counted: true}
+			visitor = Guest{Name: "Visitor"}
+			trip = Trip{
+				Metres:       10000,
+				DriverID:     "driver",
+				PassengerIDs: []string{"passenger"},
+				Guests:       []Guest{guest, visitor},
+			}
+			trip.Price(Vehicle{WearRate: 30}, FuelPrices{})
+		})
+
+		It("should share the cost with counted guests", func() {
+			Expect(trip.Cost).To(Equal(300))
+			Expect(trip.Contribution).To(Equal(100))
+		})
+
+		It("should collect the contributions of counted guests for the driver", func() {
+			Expect(trip.CalculateScores(map[string]Score{}, DistanceScoring.Strategy())).To(Succeed())
+			Expect(trip.Scores["driver"].CostAsDriver).To(Equal(200))
+			Expect(trip.Scores["passenger"].CostAsPassenger).To(Equal(100))
+			Expect(trip.Scores[guest.Key()].CostAsPassenger).To(Equal(100))
+			Expect(trip.Scores).ToNot(HaveKey(visitor.Key()))
+		})
+
+		It("should score counted guests as passengers carried", func() {
+			Expect(trip.CalculateScores(map[string]Score{}, PassengerDistanceScoring.Strategy())).To(Succeed())
+			Expect(trip.Scores["driver"].PointsAsDriver).To(Equal(20000))
+			Expect(trip.Scores[guest.Key()].PointsAsPassenger).To(Equal(10000))
+		})
+
+	})
+
+})
@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"fmt"
 
 	"gopkg.in/mgo.v2/bson"
 )
@@ -17,9 +18,135 @@ type User struct {
 	PhotoURL    string `json:"photo-url"     bson:"photo-url"`
 	IsAnon      bool   `json:"is-anon"       bson:"is-anon"`
 
-	// Used for non firebase users created specifically for a car share
+	// profile fields the user has edited, which are no longer refreshed from firebase
+	Overrides []string `json:"-" bson:"overrides,omitempty"`
+
+	// Used for non firebase users created specifically for a car share, until they are
+	// claimed by the firebase user they turn out to be
 	LinkedCarShareID string   `json:"-" bson:"linked-carshare"`
 	LinkedCarShare   CarShare `json:"-" bson:"-"`
+	ClaimedBy        string   `json:"-" bson:"claimed-by,omitempty"`
+
+	// Anonymous users who take the place of users that deleted their account
+	Erased bool `json:"erased" bson:"erased,omitempty"`
+
+	// kinds of email and events pushed to their devices the user doesn't want to receive
+	EmailOptOut []string `json:"-" bson:"email-opt-out,omitempty"`
+	PushOptOut  []string `json:"-" bson:"push-opt-out,omitempty"`
+}
+
+// IsPlaceholder returns true if the user was created for a car share rather than signing up
+func (u User) IsPlaceholder() bool {
+	return u.FirebaseUID == "" && u.LinkedCarShareID != "" && !u.Erased
+}
+
+// Profile of a user as given by firebase when they sign in
+type Profile struct {
+	DisplayName string
+	Email       string
+	PhotoURL    string
+	IsAnon      bool
+}
+
+// Profile fields that users can edit for themselves
+const (
+	DisplayNameField = "display-name"
+	PhotoURLField    = "photo-url"
+)
+
+// SyncProfile refreshes the user from their firebase profile, other than the fields they
+// have edited themselves. Anonymous users who link a permanent account keep the same user.
+// Returns true if anything changed.
+func (u *User) SyncProfile(profile Profile) bool {
+	changed := false
+	sync := func(field string, current *string, latest string) {
+		if latest != "" && latest != *current && !contains(u.Overrides, field) {
+			*current = latest
+			changed = true
+		}
+	}
+	sync(DisplayNameField, &u.DisplayName, profile.DisplayName)
+	sync(PhotoURLField, &u.PhotoURL, profile.PhotoURL)
+	if profile.Email != "" && profile.Email != u.Email {
+		u.Email = profile.Email
+		changed = true
+	}
+	if profile.IsAnon != u.IsAnon {
+		u.IsAnon = profile.IsAnon
+		changed = true
+	}
+	return changed
+}
+
+// Kinds of email sent to users, which they can opt out of
+const (
+	DigestEmail   = "digest"
+	ReminderEmail = "reminder"
+)
+
+// EmailKinds users can opt out of
+var EmailKinds = []string{DigestEmail, ReminderEmail}
+
+// ValidateEmailOptOut checks the user has only opted out of known kinds of email
+func (u User) ValidateEmailOptOut() error {
+	for _, kind := range u.EmailOptOut {
+		if !contains(EmailKinds, kind) {
+			return fmt.Errorf("unknown kind of email \"%s\"", kind)
+		}
+	}
+	return nil
+}
+
+// ValidatePushOptOut checks the user has only opted out of being pushed known events
+func (u User) ValidatePushOptOut() error {
+	for _, event := range u.PushOptOut {
+		if !contains(Events, event) {
+			return fmt.Errorf("unknown event \"%s\"", event)
+		}
+	}
+	return nil
+}
+
+// WantsPush returns true if the user hasn't opted out of having the event pushed to their devices
+func (u User) WantsPush(event string) bool {
+	return !u.Erased && !contains(u.PushOptOut, event)
+}
+
+// WantsEmail returns true if the user has an address and hasn't opted out of the kind of email
+func (u User) WantsEmail(kind string) bool {
+	return u.Email != "" && !u.Erased && !contains(u.EmailOptOut, kind)
+}
+
+// Override records that the user has edited a profile field for themselves
+func (u *User) Override(field string) {
+	if !contains(u.Overrides, field) {
+		u.Overrides = append(u.Overrides, field)
+	}
+}
+
+// PrivateUser is a user as they see themselves, including the details hidden from everyone else
+type PrivateUser struct {
+	User
+	FirebaseUID string   `json:"firebase-uid"`
+	Email       string   `json:"email"`
+	EmailOptOut []string `json:"email-opt-out"`
+	PushOptOut  []string `json:"push-opt-out"`
+}
+
+// Private view of the user, only to be shown to the user themselves
+func (u User) Private() PrivateUser {
+	return PrivateUser{
+		User:        u,
+		FirebaseUID: u.FirebaseUID,
+		Email:       u.Email,
+		EmailOptOut: append([]string{}, u.EmailOptOut...),
+		PushOptOut:  append([]string{}, u.PushOptOut...),
+	}
+}
+
+// GetName to satisfy jsonapi.EntityNamer interface, so a private user is still a user
+func (p PrivateUser) GetName() string {
+	return "users"
 }
 
 // GetID to satisfy jsonapi.MarshalIdentifier interface
@@ -0,0 +1,256 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// FuelType a vehicle runs on
+type FuelType string
+
+// Fuel types
+const (
+	Petrol   FuelType = "petrol"
+	Diesel   FuelType = "diesel"
+	LPG      FuelType = "lpg"
+	Electric FuelType = "electric"
+)
+
+// ErrUnknownFuelType returned when a fuel type isn't one of those supported
+var ErrUnknownFuelType = errors.New("unknown fuel type, must be petrol, diesel, lpg or electric")
+
+// ParseFuelType from its name
+func ParseFuelType(name string) (FuelType, error) {
+	switch fuelType := FuelType(name); fuelType {
+	case Petrol, Diesel, LPG, Electric:
+		return fuelType, nil
+	default:
+		return "", ErrUnknownFuelType
+	}
+}
+
+// FuelPrices of each fuel type in the smallest unit of currency (e.g. pence) per litre,
+// or per kWh for electric
+type FuelPrices map[FuelType]float64
+
+// ParseFuelPrices from prices given by fuel type name
+func ParseFuelPrices(prices map[string]string) (FuelPrices, error) {
+	result := make(FuelPrices, len(prices))
+	for name, price := range prices {
+		fuelType, err := ParseFuelType(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", name, err)
+		}
+		result[fuelType], err = strconv.ParseFloat(price, 64)
+		if err != nil || result[fuelType] < 0 {
+			return nil, fmt.Errorf("invalid price %s for %s", price, name)
+		}
+	}
+	return result, nil
+}
+
+// Vehicle used by a car share. Consumption is in litres, or kWh for electric vehicles,
+// per 100 km and the wear rate in the smallest unit of currency per km. Seats includes the
+// driver's. Besides its owner, a vehicle can be driven by the drivers they allow.
+type Vehicle struct {
+	ID          bson.ObjectId `json:"-"           bson:"_id,omitempty"`
+	Name        string        `json:"name"        bson:"name"`
+	FuelType    FuelType      `json:"fuel-type"   bson:"fuel-type"`
+	Consumption float64       `json:"consumption" bson:"consumption"`
+	WearRate    float64       `json:"wear-rate"   bson:"wear-rate"`
+	Seats       int           `json:"seats"       bson:"seats"`
+	CarShare    *CarShare     `json:"-"           bson:"-"`
+	CarShareID  string        `json:"-"           bson:"car-share"`
+	Owner       *User         `json:"-"           bson:"-"`
+	OwnerID     string        `json:"-"           bson:"owner"`
+	Drivers     []*User       `json:"-"           bson:"-"`
+	DriverIDs   []string      `json:"-"           bson:"drivers"`
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (v Vehicle) GetID() string {
+	return v.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (v *Vehicle) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		v.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid vehicle id")
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (v Vehicle) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+		{
+			Type: "users",
+			Name: "owner",
+		},
+		{
+			Type: "users",
+			Name: "drivers",
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (v Vehicle) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{}
+
+	if v.CarShareID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   v.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		})
+	}
+
+	if v.OwnerID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   v.OwnerID,
+			Name: "owner",
+			Type: "users",
+		})
+	}
+
+	for _, driverID := range v.DriverIDs {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   driverID,
+			Type: "users",
+			Name: "drivers",
+		})
+	}
+
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (v *Vehicle) SetToOneReferenceID(name, ID string) error {
+	switch name {
+	case "carShare":
+		v.CarShareID = ID
+		return nil
+	case "owner":
+		v.OwnerID = ID
+		return nil
+	default:
+		return errors.New("There is no to-one relationship with the name " + name)
+	}
+}
+
+// GetReferencedStructs to satisfy jsonapi.MarshalIncludedRelations interface
+func (v Vehicle) GetReferencedStructs() []jsonapi.MarshalIdentifier {
+	result := []jsonapi.MarshalIdentifier{}
+
+	if v.CarShare != nil {
+		result = append(result, *v.CarShare)
+	}
+
+	if v.Owner != nil {
+		result = append(result, *v.Owner)
+	}
+
+	for _, driver := range v.Drivers {
+		result = append(result, driver)
+	}
+
+	return result
+}
+
+// SetToManyReferenceIDs to satisfy jsonapi.UnmarshalToManyRelations
+func (v *Vehicle) SetToManyReferenceIDs(name string, IDs []string) error {
+	if name == "drivers" {
+		v.DriverIDs = append([]string{}, IDs...)
+		sort.Strings(v.DriverIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// AddToManyIDs to satisfy jsonapi.AddToManyIDs
+func (v *Vehicle) AddToManyIDs(name string, IDs []string) error {
+	if name == "drivers" {
+		v.DriverIDs = append(v.DriverIDs, IDs...)
+		sort.Strings(v.DriverIDs)
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// DeleteToManyIDs to satisfy jsonapi.DeleteToManyIDs
+func (v *Vehicle) DeleteToManyIDs(name string, IDs []string) error {
+	if name == "drivers" {
+		for _, ID := range IDs {
+			v.DriverIDs = remove(v.DriverIDs, ID)
+		}
+		return nil
+	}
+
+	return errors.New("There is no to-many relationship with the name " + name)
+}
+
+// Validate the name, fuel type, consumption and wear rate of the vehicle
+func (v Vehicle) Validate() error {
+
+	if v.Name == "" {
+		return errors.New("vehicle must have a name")
+	}
+
+	if _, err := ParseFuelType(string(v.FuelType)); err != nil {
+		return err
+	}
+
+	if v.Consumption <= 0 {
+		return errors.New("vehicle must have a consumption per 100 km greater than zero")
+	}
+
+	if v.WearRate < 0 {
+		return errors.New("vehicle wear rate can't be negative")
+	}
+
+	if v.Seats < 1 {
+		return errors.New("vehicle must have at least one seat")
+	}
+
+	return nil
+}
+
+// CanDrive returns true if the user owns the vehicle or has been allowed to drive it
+func (v Vehicle) CanDrive(userID string) bool {
+	return v.OwnerID == userID || contains(v.DriverIDs, userID)
+}
+
+// Carries returns true if the vehicle has a seat for the driver and each passenger.
+// Vehicles recorded before their seats were counted are assumed to have enough.
+func (v Vehicle) Carries(passengers int) bool {
+	return v.Seats == 0 || passengers+1 <= v.Seats
+}
+
+// Cost of driving the vehicle the given distance, in the smallest unit of currency, from
+// the fuel it uses and its wear rate
+func (v Vehicle) Cost(metres int, prices FuelPrices) int {
+	km := float64(metres) / 1000
+	fuel := v.Consumption / 100 * km * prices[v.FuelType]
+	wear := v.WearRate * km
+	return int(math.Floor(fuel + wear + 0.5))
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/manyminds/api2go/jsonapi"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Events of a car share that webhooks and devices can be notified of
+const (
+	TripCreated   = "trip.created"
+	TripUpdated   = "trip.updated"
+	TripDeleted   = "trip.deleted"
+	MemberAdded   = "member.added"
+	MemberRemoved = "member.removed"
+)
+
+// Events webhooks and devices can be notified of, in the order they are documented
+var Events = []string{TripCreated, TripUpdated, TripDeleted, MemberAdded, MemberRemoved}
+
+// Webhook an HTTPS endpoint that admins of a car share have asked to be told about its events.
+// Every delivery is signed with the webhook's secret, which is never shown again once set.
+type Webhook struct {
+	ID         bson.ObjectId `json:"-"                bson:"_id,omitempty"`
+	URL        string        `json:"url"              bson:"url"`
+	Secret     string        `json:"secret,omitempty" bson:"secret"`
+	Events     []string      `json:"events"           bson:"events"`
+	CarShare   *CarShare     `json:"-"                bson:"-"`
+	CarShareID string        `json:"-"                bson:"car-share"`
+}
+
+// Validate the webhook, returning an error explaining the first problem found
+func (w Webhook) Validate() error {
+	endpoint, err := url.Parse(w.URL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return fmt.Errorf("webhook url must be an https url")
+	}
+	if w.Secret == "" {
+		return errors.New("webhook must have a secret")
+	}
+	if len(w.Events) == 0 {
+		return errors.New("webhook must have at least one event")
+	}
+	for _, event := range w.Events {
+		if !contains(Events, event) {
+			return fmt.Errorf("unknown event \"%s\"", event)
+		}
+	}
+	return nil
+}
+
+// Wants returns true if the webhook is to be notified of the event
+func (w Webhook) Wants(event string) bool {
+	return contains(w.Events, event)
+}
+
+// Sign a delivery body with the webhook's secret, as the hex HMAC-SHA256 prefixed by "sha256="
+func (w Webhook) Sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GetID to satisfy jsonapi.MarshalIdentifier interface
+func (w Webhook) GetID() string {
+	return w.ID.Hex()
+}
+
+// SetID to satisfy jsonapi.UnmarshalIdentifier interface
+func (w *Webhook) SetID(id string) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if bson.IsObjectIdHex(id) {
+		w.ID = bson.ObjectIdHex(id)
+		return nil
+	}
+
+	return errors.New("<id>" + id + "</id> is not a valid webhook id")
+}
+
+// GetReferences to satisfy jsonapi.MarshalReferences interface
+func (w Webhook) GetReferences() []jsonapi.Reference {
+	return []jsonapi.Reference{
+		{
+			Type: "carShares",
+			Name: "carShare",
+		},
+		{
+			// deliveries belong to the webhook rather than being listed by it
+			Type:        "deliveries",
+			Name:        "deliveries",
+			IsNotLoaded: true,
+		},
+	}
+}
+
+// GetReferencedIDs to satisfy jsonapi.MarshalLinkedRelations interface
+func (w Webhook) GetReferencedIDs() []jsonapi.ReferenceID {
+	result := []jsonapi.ReferenceID{}
+
+	if w.CarShareID != "" {
+		result = append(result, jsonapi.ReferenceID{
+			ID:   w.CarShareID,
+			Name: "carShare",
+			Type: "carShares",
+		})
+	}
+
+	return result
+}
+
+// SetToOneReferenceID to satisfy jsonapi.UnmarshalToOneRelations interface
+func (w *Webhook) SetToOneReferenceID(name, ID string) error {
+	if name == "carShare" {
+		w.CarShareID = ID
+		return nil
+	}
+	return errors.New("There is no to-one relationship with the name " + name)
+}
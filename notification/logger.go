@@ -0,0 +1,7 @@
+package notification
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("notification")
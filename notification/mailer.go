@@ -0,0 +1,85 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Message is a plain text email to a single recipient
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(msg Message) error
+}
+
+// ErrInvalidRecipient indicates a recipient address that would break the message headers
+var ErrInvalidRecipient = errors.New("recipient address can't contain line breaks")
+
+// SMTPMailer sends emails through an SMTP server, Auth being nil for servers that don't
+// require authentication
+type SMTPMailer struct {
+	Addr string
+	From string
+	Auth smtp.Auth
+}
+
+// Send to satisfy the Mailer interface
+func (m SMTPMailer) Send(msg Message) error {
+	data, err := format(m.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+	return smtp.SendMail(m.Addr, m.Auth, m.From, []string{msg.To}, data)
+}
+
+// format the message with its headers, ready to send
+func format(from string, msg Message, date time.Time) ([]byte, error) {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(from, "\r\n") {
+		return nil, ErrInvalidRecipient
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	body := strings.Replace(msg.Body, "\r\n", "\n", -1)
+	buf.WriteString(strings.Replace(body, "\n", "\r\n", -1))
+	return buf.Bytes(), nil
+}
+
+// MemoryMailer keeps the emails it is asked to send rather than sending them, for tests and
+// running without an SMTP server
+type MemoryMailer struct {
+	mutex sync.Mutex
+	sent  []Message
+}
+
+// Send to satisfy the Mailer interface
+func (m *MemoryMailer) Send(msg Message) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns the emails sent so far, oldest first
+func (m *MemoryMailer) Sent() []Message {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return append([]Message{}, m.sent...)
+}
@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mailer", func() {
+
+	var date = time.Date(2017, time.March, 6, 8, 0, 0, 0, time.UTC)
+
+	Describe("formatting a message", func() {
+
+		It("should give the message plain text headers and CRLF line endings", func() {
+			data, err := format("carshare@example.com", Message{
+				To:      "alice@example.com",
+				Subject: "Your week",
+				Body:    "Hi Alice,\n\nNothing happened.\n",
+			}, date)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal("From: carshare@example.com\r\n" +
+				"To: alice@example.com\r\n" +
+				"Subject: Your week\r\n" +
+				"Date: Mon, 06 Mar 2017 08:00:00 +0000\r\n" +
+				"MIME-Version: 1.0\r\n" +
+				"Content-Type: text/plain; charset=utf-8\r\n" +
+				"Content-Transfer-Encoding: 8bit\r\n" +
+				"\r\n" +
+				"Hi Alice,\r\n\r\nNothing happened.\r\n"))
+		})
+
+		It("should encode subjects that aren't plain ascii", func() {
+			data, err := format("carshare@example.com", Message{To: "alice@example.com", Subject: "Café run\r\nBcc: eve@example.com"}, date)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("Subject: =?utf-8?q?"))
+			Expect(string(data)).ToNot(ContainSubstring("\r\nBcc:"))
+		})
+
+		It("should refuse recipients that would add headers", func() {
+			_, err := format("carshare@example.com", Message{To: "alice@example.com\r\nBcc: eve@example.com"}, date)
+			Expect(err).To(Equal(ErrInvalidRecipient))
+		})
+
+	})
+
+	Describe("memory mailer", func() {
+
+		It("should keep the messages it is sent", func() {
+			mailer := &MemoryMailer{}
+			Expect(mailer.Send(Message{To: "alice@example.com"})).To(Succeed())
+			Expect(mailer.Send(Message{To: "bob@example.com"})).To(Succeed())
+			Expect(mailer.Sent()).To(Equal([]Message{{To: "alice@example.com"}, {To: "bob@example.com"}}))
+		})
+
+	})
+
+})
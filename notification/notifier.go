@@ -0,0 +1,311 @@
+/*
+Package notification emails the members of car shares a weekly digest of their trips and
+standings, and reminds them to log a trip on the days they expect to commute.
+*/
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+)
+
+// staleReminder is how late a reminder can be sent, a reminder missed for longer than this
+// while the notifier wasn't running is skipped
+const staleReminder = 12 * time.Hour
+
+// Notifier sends digests and reminders as they fall due. Digest is when the weekly digest of
+// every car share goes out.
+type Notifier struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	Mailer          Mailer
+	Clock           clock.Clock
+	Digest          model.Reminder
+}
+
+// Run sends due notifications immediately and then every interval, until stop is closed
+func (n Notifier) Run(interval time.Duration, ctx api2go.APIContexter, stop <-chan struct{}) {
+	ticker := n.Clock.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		sent, err := n.Tick(ctx)
+		if err != nil {
+			log.Errorf("Error sending notifications, %s", err)
+		}
+		if sent > 0 {
+			log.Infof("sent %d emails", sent)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick sends the digests and reminders that have fallen due, returning how many emails were
+// sent. A car share is moved on to its next digest and reminder before they are sent, so a
+// failure loses a notification rather than repeating it to the members who did get it.
+// A problem with one car share doesn't stop the others, the first error is returned.
+func (n Notifier) Tick(ctx api2go.APIContexter) (int, error) {
+
+	now := n.Clock.Now().UTC()
+
+	carShares, err := n.CarShareStorage.GetNotificationsDue(now, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving car shares with notifications due, %s", err)
+	}
+
+	sent := 0
+	var firstErr error
+	for _, carShare := range carShares {
+		count, err := n.notify(carShare, now, ctx)
+		sent += count
+		if err != nil {
+			log.Errorf("Error notifying members of car share %s, %s", carShare.GetID(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return sent, firstErr
+}
+
+// notify the members of a car share of whatever is due, and work out when the next digest and
+// reminder are. Car shares new to the notifier only have theirs worked out.
+func (n Notifier) notify(carShare model.CarShare, now time.Time, ctx api2go.APIContexter) (int, error) {
+
+	var (
+		digestDue, reminderDue time.Time
+		err                    error
+	)
+
+	if carShare.NextDigest.IsZero() || !carShare.NextDigest.After(now) {
+		if !carShare.NextDigest.IsZero() {
+			digestDue = carShare.NextDigest
+		}
+		carShare.NextDigest, err = n.Digest.NextOccurrence(now)
+		if err != nil {
+			return 0, fmt.Errorf("error finding next digest, %s", err)
+		}
+	}
+
+	switch {
+	case carShare.Reminder == nil:
+		carShare.NextReminder = time.Time{}
+	case carShare.NextReminder.IsZero() || !carShare.NextReminder.After(now):
+		if !carShare.NextReminder.IsZero() && now.Sub(carShare.NextReminder) < staleReminder {
+			reminderDue = carShare.NextReminder
+		}
+		carShare.NextReminder, err = carShare.Reminder.NextOccurrence(now)
+		if err != nil {
+			return 0, fmt.Errorf("error finding next reminder, %s", err)
+		}
+	}
+
+	err = n.CarShareStorage.Update(carShare, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error updating car share, %s", err)
+	}
+
+	sent := 0
+	if !digestDue.IsZero() {
+		sent, err = n.sendDigest(carShare, digestDue, now, ctx)
+		if err != nil {
+			return sent, fmt.Errorf("error sending digest, %s", err)
+		}
+	}
+	if !reminderDue.IsZero() {
+		count, err := n.sendReminder(carShare, reminderDue, ctx)
+		sent += count
+		if err != nil {
+			return sent, fmt.Errorf("error sending reminder, %s", err)
+		}
+	}
+
+	return sent, nil
+}
+
+// sendDigest of the week up to the given time to the members who want it. Nothing is sent for
+// a week without trips.
+func (n Notifier) sendDigest(carShare model.CarShare, until, now time.Time, ctx api2go.APIContexter) (int, error) {
+
+	members, err := n.members(carShare, ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	location := n.location(carShare)
+	unit := carShare.PreferredUnit()
+	since := until.AddDate(0, 0, -7)
+	trips := []digestTrip{}
+	err = n.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		if !trip.TimeStamp.After(since) || trip.TimeStamp.After(until) || !trip.Status.Counts() {
+			return nil
+		}
+		passengers := []string{}
+		for _, passengerID := range trip.PassengerIDs {
+			passengers = append(passengers, name(members, passengerID))
+		}
+		trips = append(trips, digestTrip{
+			When:       trip.TimeStamp.In(location).Format("Mon 2 Jan 15:04"),
+			Driver:     name(members, trip.DriverID),
+			Passengers: strings.Join(passengers, ", "),
+			Distance:   unit.Format(trip.Metres),
+		})
+		return nil
+	}, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving trips, %s", err)
+	}
+	if len(trips) == 0 {
+		return 0, nil
+	}
+
+	standings, err := ledger.Standings(carShare, n.TripStorage, now, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error working out standings, %s", err)
+	}
+	table := []standing{}
+	for userID, score := range standings.AllTime {
+		table = append(table, standing{
+			Name:      name(members, userID),
+			Balance:   score.PointsAsDriver - score.PointsAsPassenger,
+			Driven:    unit.Format(score.MetresAsDriver),
+			Passenger: unit.Format(score.MetresAsPassenger),
+		})
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Balance != table[j].Balance {
+			return table[i].Balance > table[j].Balance
+		}
+		return table[i].Name < table[j].Name
+	})
+
+	return n.send(carShare, members, model.DigestEmail, func(member model.User) (Message, error) {
+		body, err := render(digestTemplate, digest{
+			Name:      member.DisplayName,
+			CarShare:  carShare.Name,
+			Until:     until.In(location).Format("Monday 2 January"),
+			Trips:     trips,
+			Standings: table,
+		})
+		return Message{To: member.Email, Subject: fmt.Sprintf("Your week in %s", carShare.Name), Body: body}, err
+	})
+}
+
+// sendReminder to the members who want it, unless a trip has been logged on the day it is due
+func (n Notifier) sendReminder(carShare model.CarShare, due time.Time, ctx api2go.APIContexter) (int, error) {
+
+	start, end, err := carShare.Reminder.Day(due)
+	if err != nil {
+		return 0, err
+	}
+	logged := false
+	err = n.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		// drafts are made by the scheduler, so still need a member to confirm them
+		if !trip.TimeStamp.Before(start) && trip.TimeStamp.Before(end) && trip.Status != model.TripDraft {
+			logged = true
+		}
+		return nil
+	}, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving trips, %s", err)
+	}
+	if logged {
+		return 0, nil
+	}
+
+	members, err := n.members(carShare, ctx)
+	if err != nil {
+		return 0, err
+	}
+	location := n.location(carShare)
+	return n.send(carShare, members, model.ReminderEmail, func(member model.User) (Message, error) {
+		body, err := render(reminderTemplate, reminder{
+			Name:     member.DisplayName,
+			CarShare: carShare.Name,
+			Day:      due.In(location).Format("Monday 2 January"),
+		})
+		return Message{To: member.Email, Subject: fmt.Sprintf("Log today's trip in %s", carShare.Name), Body: body}, err
+	})
+}
+
+// send an email of the given kind to each member who wants it, returning how many were sent.
+// Every member is tried, the first error is returned.
+func (n Notifier) send(carShare model.CarShare, members map[string]model.User, kind string, compose func(model.User) (Message, error)) (int, error) {
+	sent := 0
+	var firstErr error
+	for _, memberID := range carShare.MemberIDs {
+		member, ok := members[memberID]
+		if !ok || !member.WantsEmail(kind) {
+			continue
+		}
+		msg, err := compose(member)
+		if err == nil {
+			err = n.Mailer.Send(msg)
+		}
+		if err != nil {
+			log.Errorf("Error sending %s to user %s, %s", kind, memberID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		sent++
+	}
+	return sent, firstErr
+}
+
+// members of the car share by ID
+func (n Notifier) members(carShare model.CarShare, ctx api2go.APIContexter) (map[string]model.User, error) {
+	members := make(map[string]model.User)
+	for _, memberID := range carShare.MemberIDs {
+		member, err := n.UserStorage.GetOne(memberID, ctx)
+		switch err {
+		case nil:
+			members[memberID] = member
+		case storage.ErrNotFound:
+			log.Warningf("car share %s has missing member %s", carShare.GetID(), memberID)
+		default:
+			return nil, fmt.Errorf("error retrieving member %s, %s", memberID, err)
+		}
+	}
+	return members, nil
+}
+
+// location times are presented in, that of the car share's reminder if it has one
+func (n Notifier) location(carShare model.CarShare) *time.Location {
+	timeZone := n.Digest.TimeZone
+	if carShare.Reminder != nil {
+		timeZone = carShare.Reminder.TimeZone
+	}
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// name of a user, for users who have since left the car share as well
+func name(members map[string]model.User, userID string) string {
+	member, ok := members[userID]
+	switch {
+	case !ok:
+		return "a former member"
+	case member.DisplayName == "":
+		return "someone"
+	default:
+		return member.DisplayName
+	}
+}
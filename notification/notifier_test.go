@@ -0,0 +1,258 @@
+package notification
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notifier", func() {
+
+	var (
+		notifier        Notifier
+		mailer          *MemoryMailer
+		mockClock       *clock.Mock
+		context         *api2go.APIContext
+		carShareStorage *memory.CarShareStorage
+		tripStorage     *memory.TripStorage
+		userStorage     *memory.UserStorage
+		carShareID      string
+		aliceID         string
+		bobID           string
+		sent            int
+		err             error
+	)
+
+	// sunday, the day before the first digest and reminder
+	start := time.Date(2017, time.March, 5, 12, 0, 0, 0, time.UTC)
+
+	carShare := func() model.CarShare {
+		carShare, err := carShareStorage.GetOne(carShareID, context)
+		Expect(err).ToNot(HaveOccurred())
+		return carShare
+	}
+
+	logTrip := func(at time.Time, status model.TripStatus) {
+		_, err := tripStorage.Insert(model.Trip{
+			CarShareID:   carShareID,
+			TimeStamp:    at,
+			Metres:       16093,
+			Status:       status,
+			DriverID:     aliceID,
+			PassengerIDs: []string{bobID},
+			Scores: map[string]model.Score{
+				aliceID: {MetresAsDriver: 16093, PointsAsDriver: 16093},
+				bobID:   {MetresAsPassenger: 16093, PointsAsPassenger: 16093},
+			},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		mockClock = clock.NewMock()
+		mockClock.Set(start)
+		context = &api2go.APIContext{}
+		mailer = &MemoryMailer{}
+		carShareStorage = memory.NewCarShareStorage()
+		tripStorage = memory.NewTripStorage()
+		userStorage = memory.NewUserStorage()
+		notifier = Notifier{
+			CarShareStorage: carShareStorage,
+			TripStorage:     tripStorage,
+			UserStorage:     userStorage,
+			Mailer:          mailer,
+			Clock:           mockClock,
+			Digest:          model.Reminder{Weekdays: []string{"monday"}, Time: "08:00", TimeZone: "UTC"},
+		}
+
+		aliceID, err = userStorage.Insert(model.User{DisplayName: "Alice", Email: "alice@example.com"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		bobID, err = userStorage.Insert(model.User{DisplayName: "Bob", Email: "bob@example.com", EmailOptOut: []string{model.DigestEmail}}, context)
+		Expect(err).ToNot(HaveOccurred())
+		carolID, err := userStorage.Insert(model.User{DisplayName: "Carol"}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		carShareID, err = carShareStorage.Insert(model.CarShare{
+			Name:      "Commute",
+			Unit:      model.Miles,
+			MemberIDs: []string{aliceID, bobID, carolID},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("a car share new to the notifier", func() {
+
+		BeforeEach(func() {
+			sent, err = notifier.Tick(context)
+		})
+
+		It("should only work out when its next digest is", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(Equal(0))
+			Expect(carShare().NextDigest).To(Equal(time.Date(2017, time.March, 6, 8, 0, 0, 0, time.UTC)))
+			Expect(carShare().NextReminder.IsZero()).To(BeTrue())
+		})
+
+	})
+
+	Describe("digest", func() {
+
+		BeforeEach(func() {
+			_, err = notifier.Tick(context)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("of a week with trips", func() {
+
+			BeforeEach(func() {
+				logTrip(time.Date(2017, time.March, 3, 8, 30, 0, 0, time.UTC), model.TripConfirmed)
+				logTrip(time.Date(2017, time.March, 4, 8, 30, 0, 0, time.UTC), model.TripDraft)
+				mockClock.Set(time.Date(2017, time.March, 6, 8, 0, 0, 0, time.UTC))
+				sent, err = notifier.Tick(context)
+			})
+
+			It("should be sent to the members who want it", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(1))
+				Expect(mailer.Sent()).To(HaveLen(1))
+				Expect(mailer.Sent()[0].To).To(Equal("alice@example.com"))
+				Expect(mailer.Sent()[0].Subject).To(Equal("Your week in Commute"))
+			})
+
+			It("should list the trips that count and the standings", func() {
+				body := mailer.Sent()[0].Body
+				Expect(body).To(HavePrefix("Hi Alice,"))
+				Expect(body).To(ContainSubstring("- Fri 3 Mar 08:30: Alice drove Bob, 10.0 miles\n"))
+				Expect(body).ToNot(ContainSubstring("Sat 4 Mar"))
+				Expect(body).To(ContainSubstring("- Alice: 16093 points, 10.0 miles driven and 0.0 miles as a passenger\n" +
+					"- Bob: -16093 points, 0.0 miles driven and 10.0 miles as a passenger\n"))
+			})
+
+			It("should move on to the next week", func() {
+				Expect(carShare().NextDigest).To(Equal(time.Date(2017, time.March, 13, 8, 0, 0, 0, time.UTC)))
+				sent, err = notifier.Tick(context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(0))
+			})
+
+		})
+
+		Context("of a week without trips", func() {
+
+			BeforeEach(func() {
+				logTrip(time.Date(2017, time.February, 20, 8, 30, 0, 0, time.UTC), model.TripConfirmed)
+				mockClock.Set(time.Date(2017, time.March, 6, 8, 0, 0, 0, time.UTC))
+				sent, err = notifier.Tick(context)
+			})
+
+			It("should not be sent", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(0))
+				Expect(mailer.Sent()).To(BeEmpty())
+				Expect(carShare().NextDigest).To(Equal(time.Date(2017, time.March, 13, 8, 0, 0, 0, time.UTC)))
+			})
+
+		})
+
+	})
+
+	Describe("reminder", func() {
+
+		// 18:00 in London is 18:00 UTC until the clocks change at the end of March
+		due := time.Date(2017, time.March, 6, 18, 0, 0, 0, time.UTC)
+
+		BeforeEach(func() {
+			cs := carShare()
+			cs.Reminder = &model.Reminder{Weekdays: []string{"monday", "tuesday"}, Time: "18:00", TimeZone: "Europe/London"}
+			Expect(carShareStorage.Update(cs, context)).To(Succeed())
+			_, err = notifier.Tick(context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare().NextReminder).To(Equal(due))
+
+			// the digest is out of the way
+			mockClock.Set(time.Date(2017, time.March, 6, 9, 0, 0, 0, time.UTC))
+			_, err = notifier.Tick(context)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("on a day without a trip", func() {
+
+			BeforeEach(func() {
+				logTrip(time.Date(2017, time.March, 6, 8, 30, 0, 0, time.UTC), model.TripDraft)
+				mockClock.Set(due)
+				sent, err = notifier.Tick(context)
+			})
+
+			It("should be sent to the members who want it", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(2))
+				Expect(mailer.Sent()[0].To).To(Equal("alice@example.com"))
+				Expect(mailer.Sent()[1].To).To(Equal("bob@example.com"))
+				Expect(mailer.Sent()[1].Subject).To(Equal("Log today's trip in Commute"))
+				Expect(mailer.Sent()[1].Body).To(ContainSubstring("No trip has been logged in Commute for Monday 6 March yet"))
+			})
+
+			It("should move on to the next day it's expected", func() {
+				Expect(carShare().NextReminder).To(Equal(time.Date(2017, time.March, 7, 18, 0, 0, 0, time.UTC)))
+			})
+
+		})
+
+		Context("on a day with a trip", func() {
+
+			BeforeEach(func() {
+				logTrip(time.Date(2017, time.March, 6, 8, 30, 0, 0, time.UTC), model.TripConfirmed)
+				mockClock.Set(due)
+				sent, err = notifier.Tick(context)
+			})
+
+			It("should not be sent", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(0))
+				Expect(mailer.Sent()).To(BeEmpty())
+			})
+
+		})
+
+		Context("missed while the notifier wasn't running", func() {
+
+			BeforeEach(func() {
+				mockClock.Set(due.Add(13 * time.Hour))
+				sent, err = notifier.Tick(context)
+			})
+
+			It("should be skipped", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(0))
+				Expect(carShare().NextReminder).To(Equal(time.Date(2017, time.March, 7, 18, 0, 0, 0, time.UTC)))
+			})
+
+		})
+
+		Context("that has been removed", func() {
+
+			BeforeEach(func() {
+				cs := carShare()
+				cs.Reminder = nil
+				Expect(carShareStorage.Update(cs, context)).To(Succeed())
+				mockClock.Set(due)
+				sent, err = notifier.Tick(context)
+			})
+
+			It("should not be sent", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(0))
+			})
+
+		})
+
+	})
+
+})
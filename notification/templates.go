@@ -0,0 +1,68 @@
+package notification
+
+import (
+	"bytes"
+	"text/template"
+)
+
+var digestTemplate = template.Must(template.New("digest").Parse(`Hi{{if .Name}} {{.Name}}{{end}},
+
+Here's what happened in {{.CarShare}} in the week to {{.Until}}.
+
+Trips
+{{range .Trips}}- {{.When}}: {{.Driver}} drove{{if .Passengers}} {{.Passengers}}{{end}}, {{.Distance}}
+{{end}}
+Standings
+{{range .Standings}}- {{.Name}}: {{.Balance}} points, {{.Driven}} driven and {{.Passenger}} as a passenger
+{{end}}
+You're receiving this because you're a member of {{.CarShare}}. To stop receiving weekly
+digests, add "digest" to the email-opt-out of your user.
+`))
+
+var reminderTemplate = template.Must(template.New("reminder").Parse(`Hi{{if .Name}} {{.Name}}{{end}},
+
+No trip has been logged in {{.CarShare}} for {{.Day}} yet. If you travelled together
+today, remember to log who drove.
+
+You're receiving this because you're a member of {{.CarShare}}. To stop receiving reminders,
+add "reminder" to the email-opt-out of your user.
+`))
+
+// digestTrip is a trip as it appears in a digest
+type digestTrip struct {
+	When       string
+	Driver     string
+	Passengers string
+	Distance   string
+}
+
+// standing of a member as it appears in a digest
+type standing struct {
+	Name      string
+	Balance   int
+	Driven    string
+	Passenger string
+}
+
+// digest of a car share's week, for one member
+type digest struct {
+	Name      string
+	CarShare  string
+	Until     string
+	Trips     []digestTrip
+	Standings []standing
+}
+
+// reminder for one member to log the day's trip
+type reminder struct {
+	Name     string
+	CarShare string
+	Day      string
+}
+
+// render the template with the given data
+func render(t *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	err := t.Execute(&buf, data)
+	return buf.String(), err
+}
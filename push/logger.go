@@ -0,0 +1,7 @@
+package push
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("push")
@@ -0,0 +1,13 @@
+package push
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPush(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Push Suite")
+}
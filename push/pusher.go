@@ -0,0 +1,119 @@
+/*
+Package push notifies the devices members of a car share have registered of its trip and
+membership events, through a pluggable push service.
+*/
+package push
+
+import (
+	"fmt"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// Pusher sends a notification of an event to the devices of every member of the car share who
+// wants it
+type Pusher struct {
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	DeviceStorage   storage.DeviceStorage
+	Sender          Sender
+}
+
+// Push an event about the resource, a trip or user, to the devices of the car share's members
+// other than the user who caused it, returning how many notifications were sent. Devices the
+// push service no longer recognises are forgotten. Every device is tried, the first error is
+// returned.
+func (p Pusher) Push(carShareID, event string, resource jsonapi.MarshalIdentifier, actorID string, ctx api2go.APIContexter) (int, error) {
+
+	msg, ok := messages[event]
+	if !ok {
+		return 0, fmt.Errorf("no message for event %s", event)
+	}
+
+	carShare, err := p.CarShareStorage.GetOne(carShareID, ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving car share %s, %s", carShareID, err)
+	}
+
+	n, err := msg.render(p.details(carShare, resource, ctx))
+	if err != nil {
+		return 0, fmt.Errorf("error rendering %s notification, %s", event, err)
+	}
+	n.Data = map[string]string{
+		"event":    event,
+		"carShare": carShareID,
+		"id":       resource.GetID(),
+	}
+
+	sent := 0
+	var firstErr error
+	for _, memberID := range carShare.MemberIDs {
+		if memberID == actorID {
+			continue
+		}
+		member, err := p.UserStorage.GetOne(memberID, ctx)
+		if err != nil || !member.WantsPush(event) {
+			continue
+		}
+		devices, err := p.DeviceStorage.GetAll(memberID, ctx)
+		if err != nil {
+			log.Errorf("Error retrieving devices of user %s, %s", memberID, err)
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		for _, device := range devices {
+			err = p.Sender.Send(device.Token, n)
+			switch err {
+			case nil:
+				sent++
+			case ErrUnregistered:
+				log.Infof("forgetting device %s of user %s, its token is no longer registered", device.GetID(), memberID)
+				err = p.DeviceStorage.DeleteToken(device.Token, ctx)
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+			default:
+				log.Errorf("Error pushing %s to device %s, %s", event, device.GetID(), err)
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+	}
+
+	return sent, firstErr
+}
+
+// details of the event the notification is made from
+func (p Pusher) details(carShare model.CarShare, resource jsonapi.MarshalIdentifier, ctx api2go.APIContexter) details {
+	d := details{CarShare: carShare.Name}
+	switch r := resource.(type) {
+	case model.Trip:
+		d.Driver = p.name(r.DriverID, ctx)
+		d.Distance = carShare.PreferredUnit().Format(r.Metres)
+		d.Date = r.TimeStamp.Format("Mon 2 Jan")
+		if r.Status != model.TripConfirmed {
+			d.Status = string(r.Status)
+		}
+	case model.User:
+		d.Member = r.DisplayName
+		if d.Member == "" {
+			d.Member = "Someone"
+		}
+	}
+	return d
+}
+
+// name of a user for notifications
+func (p Pusher) name(userID string, ctx api2go.APIContexter) string {
+	user, err := p.UserStorage.GetOne(userID, ctx)
+	if err != nil || user.DisplayName == "" {
+		return "Someone"
+	}
+	return user.DisplayName
+}
@@ -0,0 +1,142 @@
+package push
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pusher", func() {
+
+	var (
+		pusher          Pusher
+		sender          *MemorySender
+		context         *api2go.APIContext
+		carShareStorage *memory.CarShareStorage
+		userStorage     *memory.UserStorage
+		deviceStorage   *memory.DeviceStorage
+		carShareID      string
+		aliceID         string
+		bobID           string
+		carolID         string
+		trip            model.Trip
+		sent            int
+		err             error
+	)
+
+	register := func(userID, token string) {
+		_, err := deviceStorage.Insert(model.Device{Token: token, Platform: model.Android, UserID: userID}, context)
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		context = &api2go.APIContext{}
+		sender = &MemorySender{}
+		carShareStorage = memory.NewCarShareStorage()
+		userStorage = memory.NewUserStorage()
+		deviceStorage = memory.NewDeviceStorage()
+		pusher = Pusher{
+			CarShareStorage: carShareStorage,
+			UserStorage:     userStorage,
+			DeviceStorage:   deviceStorage,
+			Sender:          sender,
+		}
+
+		aliceID, err = userStorage.Insert(model.User{DisplayName: "Alice"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		bobID, err = userStorage.Insert(model.User{DisplayName: "Bob"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		carolID, err = userStorage.Insert(model.User{DisplayName: "Carol", PushOptOut: []string{model.TripCreated}}, context)
+		Expect(err).ToNot(HaveOccurred())
+		carShareID, err = carShareStorage.Insert(model.CarShare{
+			Name:      "Commute",
+			Unit:      model.Kilometres,
+			MemberIDs: []string{aliceID, bobID, carolID},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		register(aliceID, "alice-phone")
+		register(bobID, "bob-phone")
+		register(bobID, "bob-tablet")
+		register(carolID, "carol-phone")
+
+		trip = model.Trip{
+			Metres:     12300,
+			TimeStamp:  time.Date(2017, time.March, 6, 8, 30, 0, 0, time.UTC),
+			Status:     model.TripConfirmed,
+			CarShareID: carShareID,
+			DriverID:   aliceID,
+		}
+		trip.SetID("58c6a1e2a5fe0e2a4c1d6e54")
+	})
+
+	Describe("a trip event", func() {
+
+		BeforeEach(func() {
+			sent, err = pusher.Push(carShareID, model.TripCreated, trip, aliceID, context)
+		})
+
+		It("should be pushed to every device of the members who want it, other than the driver's", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(Equal(2))
+			tokens := []string{}
+			for _, s := range sender.Sent() {
+				tokens = append(tokens, s.Token)
+			}
+			Expect(tokens).To(ConsistOf("bob-phone", "bob-tablet"))
+		})
+
+		It("should describe the trip", func() {
+			n := sender.Sent()[0].Notification
+			Expect(n.Title).To(Equal("New trip in Commute"))
+			Expect(n.Body).To(Equal("Alice drove 12.3 km on Mon 6 Mar"))
+			Expect(n.Data).To(Equal(map[string]string{
+				"event":    model.TripCreated,
+				"carShare": carShareID,
+				"id":       trip.GetID(),
+			}))
+		})
+
+	})
+
+	Describe("a membership event", func() {
+
+		BeforeEach(func() {
+			dave := model.User{DisplayName: "Dave"}
+			dave.SetID("58c6a1e2a5fe0e2a4c1d6e55")
+			sent, err = pusher.Push(carShareID, model.MemberAdded, dave, bobID, context)
+		})
+
+		It("should be pushed to the members who didn't add them", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(Equal(2))
+			Expect(sender.Sent()[0].Notification.Body).To(Equal("Dave joined Commute"))
+		})
+
+	})
+
+	Describe("a device that is no longer registered", func() {
+
+		BeforeEach(func() {
+			sender.Unregistered = []string{"bob-tablet"}
+			sent, err = pusher.Push(carShareID, model.TripUpdated, trip, aliceID, context)
+		})
+
+		It("should be forgotten", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sent).To(Equal(2))
+			devices, err := deviceStorage.GetAll(bobID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(devices).To(HaveLen(1))
+			Expect(devices[0].Token).To(Equal("bob-phone"))
+		})
+
+	})
+
+})
@@ -0,0 +1,142 @@
+package push
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Notification shown on a device. Data is passed to the app along with it.
+type Notification struct {
+	Title string            `json:"title"`
+	Body  string            `json:"body"`
+	Data  map[string]string `json:"-"`
+}
+
+// Sender sends notifications to devices by their push token
+type Sender interface {
+	Send(token string, n Notification) error
+}
+
+// ErrUnregistered indicates a token the push service no longer recognises, so the device it
+// was registered for should be forgotten
+var ErrUnregistered = errors.New("device token is no longer registered")
+
+// DefaultFCMEndpoint is the Firebase Cloud Messaging HTTP API
+const DefaultFCMEndpoint = "https://fcm.googleapis.com/fcm/send"
+
+// FCMSender sends notifications through the Firebase Cloud Messaging HTTP API, or any service
+// compatible with it
+type FCMSender struct {
+	Endpoint  string
+	ServerKey string
+	Client    *http.Client
+}
+
+// fcmMessage is the body posted to FCM
+type fcmMessage struct {
+	To           string            `json:"to"`
+	Notification Notification      `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+// fcmResponse is the body FCM replies with, with a result for each token sent to
+type fcmResponse struct {
+	Success int `json:"success"`
+	Failure int `json:"failure"`
+	Results []struct {
+		MessageID string `json:"message_id"`
+		Error     string `json:"error"`
+	} `json:"results"`
+}
+
+// Send to satisfy the Sender interface
+func (s FCMSender) Send(token string, n Notification) error {
+
+	body, err := json.Marshal(fcmMessage{To: token, Notification: n, Data: n.Data})
+	if err != nil {
+		return err
+	}
+
+	endpoint := s.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultFCMEndpoint
+	}
+	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "key="+s.ServerKey)
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("push service responded %s", resp.Status)
+	}
+
+	var result fcmResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return fmt.Errorf("error decoding push service response, %s", err)
+	}
+	if result.Failure == 0 {
+		return nil
+	}
+	for _, r := range result.Results {
+		switch r.Error {
+		case "":
+			continue
+		case "NotRegistered", "InvalidRegistration":
+			return ErrUnregistered
+		default:
+			return fmt.Errorf("push service rejected notification, %s", r.Error)
+		}
+	}
+	return errors.New("push service rejected notification")
+}
+
+// Sent is a notification a MemorySender was asked to send
+type Sent struct {
+	Token        string
+	Notification Notification
+}
+
+// MemorySender keeps the notifications it is asked to send rather than sending them, for tests
+// and running without a push service. Tokens in Unregistered are refused with ErrUnregistered.
+type MemorySender struct {
+	Unregistered []string
+	mutex        sync.Mutex
+	sent         []Sent
+}
+
+// Send to satisfy the Sender interface
+func (s *MemorySender) Send(token string, n Notification) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, unregistered := range s.Unregistered {
+		if token == unregistered {
+			return ErrUnregistered
+		}
+	}
+	s.sent = append(s.sent, Sent{Token: token, Notification: n})
+	return nil
+}
+
+// Sent returns the notifications sent so far, oldest first
+func (s *MemorySender) Sent() []Sent {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Sent{}, s.sent...)
+}
@@ -0,0 +1,103 @@
+package push
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FCM sender", func() {
+
+	var (
+		server   *httptest.Server
+		sender   FCMSender
+		request  *http.Request
+		body     map[string]interface{}
+		response string
+		status   int
+	)
+
+	BeforeEach(func() {
+		response = `{"success": 1, "failure": 0, "results": [{"message_id": "1"}]}`
+		status = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			request = r
+			data, _ := ioutil.ReadAll(r.Body)
+			body = nil
+			json.Unmarshal(data, &body)
+			w.WriteHeader(status)
+			w.Write([]byte(response))
+		}))
+		sender = FCMSender{Endpoint: server.URL, ServerKey: "secret", Client: server.Client()}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should post the notification to the token with the server key", func() {
+		err := sender.Send("token", Notification{Title: "New trip", Body: "Alice drove", Data: map[string]string{"event": "trip.created"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(request.Header.Get("Authorization")).To(Equal("key=secret"))
+		Expect(request.Header.Get("Content-Type")).To(Equal("application/json"))
+		Expect(body["to"]).To(Equal("token"))
+		Expect(body["notification"]).To(Equal(map[string]interface{}{"title": "New trip", "body": "Alice drove"}))
+		Expect(body["data"]).To(Equal(map[string]interface{}{"event": "trip.created"}))
+	})
+
+	Context("with a token that is no longer registered", func() {
+
+		BeforeEach(func() {
+			response = `{"success": 0, "failure": 1, "results": [{"error": "NotRegistered"}]}`
+		})
+
+		It("should return ErrUnregistered", func() {
+			Expect(sender.Send("token", Notification{})).To(Equal(ErrUnregistered))
+		})
+
+	})
+
+	Context("when the notification is rejected", func() {
+
+		BeforeEach(func() {
+			response = `{"success": 0, "failure": 1, "results": [{"error": "MessageTooBig"}]}`
+		})
+
+		It("should return the reason", func() {
+			err := sender.Send("token", Notification{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("MessageTooBig"))
+		})
+
+	})
+
+	Context("when the push service fails", func() {
+
+		BeforeEach(func() {
+			status = http.StatusUnauthorized
+		})
+
+		It("should return an error", func() {
+			err := sender.Send("token", Notification{})
+			Expect(err).To(HaveOccurred())
+			Expect(err).ToNot(Equal(ErrUnregistered))
+		})
+
+	})
+
+})
+
+var _ = Describe("Memory sender", func() {
+
+	It("should keep the notifications it is sent, refusing unregistered tokens", func() {
+		sender := &MemorySender{Unregistered: []string{"old"}}
+		Expect(sender.Send("new", Notification{Title: "hello"})).To(Succeed())
+		Expect(sender.Send("old", Notification{Title: "hello"})).To(Equal(ErrUnregistered))
+		Expect(sender.Sent()).To(Equal([]Sent{{Token: "new", Notification: Notification{Title: "hello"}}}))
+	})
+
+})
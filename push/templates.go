@@ -0,0 +1,61 @@
+package push
+
+import (
+	"bytes"
+	"text/template"
+
+	"github.com/LewisWatson/carshare-back/model"
+)
+
+// message templates for the title and body of the notification pushed for an event
+type message struct {
+	title *template.Template
+	body  *template.Template
+}
+
+func newMessage(event, title, body string) message {
+	return message{
+		title: template.Must(template.New(event + ".title").Parse(title)),
+		body:  template.Must(template.New(event + ".body").Parse(body)),
+	}
+}
+
+// messages for each event devices can be notified of
+var messages = map[string]message{
+	model.TripCreated: newMessage(model.TripCreated,
+		"New trip in {{.CarShare}}",
+		"{{.Driver}} drove {{.Distance}} on {{.Date}}"),
+	model.TripUpdated: newMessage(model.TripUpdated,
+		"Trip updated in {{.CarShare}}",
+		"{{.Driver}}'s trip on {{.Date}} is now {{.Distance}}{{if .Status}}, {{.Status}}{{end}}"),
+	model.TripDeleted: newMessage(model.TripDeleted,
+		"Trip deleted in {{.CarShare}}",
+		"{{.Driver}}'s trip on {{.Date}} was deleted"),
+	model.MemberAdded: newMessage(model.MemberAdded,
+		"New member in {{.CarShare}}",
+		"{{.Member}} joined {{.CarShare}}"),
+	model.MemberRemoved: newMessage(model.MemberRemoved,
+		"Member left {{.CarShare}}",
+		"{{.Member}} left {{.CarShare}}"),
+}
+
+// details of an event that messages are made from
+type details struct {
+	CarShare string
+	Driver   string
+	Distance string
+	Date     string
+	Status   string
+	Member   string
+}
+
+// render the notification for an event
+func (m message) render(d details) (Notification, error) {
+	var title, body bytes.Buffer
+	err := m.title.Execute(&title, d)
+	if err != nil {
+		return Notification{}, err
+	}
+	err = m.body.Execute(&body, d)
+	return Notification{Title: title.String(), Body: body.String()}, err
+}
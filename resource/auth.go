@@ -8,6 +8,7 @@ import (
 	"github.com/LewisWatson/carshare-back/storage"
 	"github.com/LewisWatson/firebase-jwt-auth"
 	"github.com/manyminds/api2go"
+	"gopkg.in/jose.v1/jwt"
 )
 
 // verify the request auth token
@@ -22,22 +23,52 @@ func verify(r api2go.Request, tokenVerifier fireauth.TokenVerifier) (firebaseUID
 	return userID, nil
 }
 
-// getRequestUser verifies the request authorisation token and finds the user it links to
+// getRequestUser verifies the request authorisation token and finds the user it links to,
+// refreshing their profile from the token claims
 func getRequestUser(r api2go.Request, tokenVerifier fireauth.TokenVerifier, userStorage storage.UserStorage) (requestUser model.User, err error) {
 	firebaseUID, err := verify(r, tokenVerifier)
 	if err != nil {
 		return model.User{}, err
 	}
+	profile := profileFromClaims(r)
 	requestUser, err = userStorage.GetByFirebaseUID(firebaseUID, r.Context)
-	if err == storage.ErrNotFound {
-		requestUser, err = createAppUserForFirebaseUser(firebaseUID, r, userStorage)
+	switch err {
+	case nil:
+		if requestUser.SyncProfile(profile) {
+			err = userStorage.Update(requestUser, r.Context)
+			if err != nil {
+				err = fmt.Errorf("error updating profile of user %s, %s", requestUser.GetID(), err)
+			}
+		}
+	case storage.ErrNotFound:
+		requestUser, err = createAppUserForFirebaseUser(firebaseUID, profile, r, userStorage)
 	}
 	return requestUser, err
 }
 
+// profileFromClaims reads the firebase profile from the claims of a verified token
+func profileFromClaims(r api2go.Request) (profile model.Profile) {
+	value, ok := r.Context.Get("claims")
+	if !ok {
+		return profile
+	}
+	claims, ok := value.(jwt.Claims)
+	if !ok {
+		return profile
+	}
+	profile.DisplayName, _ = claims.Get("name").(string)
+	profile.Email, _ = claims.Get("email").(string)
+	profile.PhotoURL, _ = claims.Get("picture").(string)
+	if firebase, ok := claims.Get("firebase").(map[string]interface{}); ok {
+		profile.IsAnon = firebase["sign_in_provider"] == "anonymous"
+	}
+	return profile
+}
+
 // createAppUserForFirebaseUser inserts a new user into user storage with the provided firebaseUID
-func createAppUserForFirebaseUser(firebaseUID string, r api2go.Request, userStorage storage.UserStorage) (user model.User, err error) {
+func createAppUserForFirebaseUser(firebaseUID string, profile model.Profile, r api2go.Request, userStorage storage.UserStorage) (user model.User, err error) {
 	user = model.User{FirebaseUID: firebaseUID}
+	user.SyncProfile(profile)
 	var id string
 	id, err = userStorage.Insert(user, r.Context)
 	if err == nil && id == "" {
@@ -84,6 +84,101 @@ var _ = Describe("auth", func() {
 
 		})
 
+		Context("token with profile claims", func() {
+
+			var claims jwt.Claims
+
+			BeforeEach(func() {
+				claims = make(jwt.Claims)
+				claims.Set("sub", "fbUserfirebaseuid")
+				claims.Set("name", "Renamed in firebase")
+				claims.Set("email", "user@example.com")
+				claims.Set("picture", "https://example.com/user.png")
+				claims.Set("firebase", map[string]interface{}{"sign_in_provider": "google.com"})
+			})
+
+			JustBeforeEach(func() {
+				requestUser, err = getRequestUser(request, mockTokenVerifier{Claims: claims}, userStorage)
+			})
+
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should refresh the profile of the user", func() {
+				Expect(requestUser.GetID()).To(Equal(fbUser.GetID()))
+				Expect(requestUser.DisplayName).To(Equal("Renamed in firebase"))
+				Expect(requestUser.Email).To(Equal("user@example.com"))
+				Expect(requestUser.PhotoURL).To(Equal("https://example.com/user.png"))
+				Expect(requestUser.IsAnon).To(BeFalse())
+			})
+
+			It("should store the refreshed profile", func() {
+				stored, err := userStorage.GetOne(fbUser.GetID(), request.Context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.DisplayName).To(Equal("Renamed in firebase"))
+				Expect(stored.Email).To(Equal("user@example.com"))
+			})
+
+			Context("user has edited their display name", func() {
+
+				BeforeEach(func() {
+					edited := fbUser
+					edited.Override(model.DisplayNameField)
+					err := userStorage.Update(edited, request.Context)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+				It("should keep the edited display name", func() {
+					Expect(requestUser.DisplayName).To(Equal(fbUser.DisplayName))
+					Expect(requestUser.Email).To(Equal("user@example.com"))
+				})
+
+			})
+
+			Context("anonymous user", func() {
+
+				BeforeEach(func() {
+					claims.Set("firebase", map[string]interface{}{"sign_in_provider": "anonymous"})
+				})
+
+				It("should mark the user anonymous", func() {
+					Expect(requestUser.IsAnon).To(BeTrue())
+				})
+
+				Context("upgraded to a permanent account", func() {
+
+					BeforeEach(func() {
+						_, err := getRequestUser(request, mockTokenVerifier{Claims: claims}, userStorage)
+						Expect(err).ToNot(HaveOccurred())
+						claims.Set("firebase", map[string]interface{}{"sign_in_provider": "password"})
+					})
+
+					It("should no longer mark the same user anonymous", func() {
+						Expect(requestUser.GetID()).To(Equal(fbUser.GetID()))
+						Expect(requestUser.IsAnon).To(BeFalse())
+					})
+
+				})
+
+			})
+
+			Context("new user", func() {
+
+				BeforeEach(func() {
+					claims.Set("sub", "newUserFirebaseUID")
+				})
+
+				It("should create the user with their profile", func() {
+					Expect(requestUser.FirebaseUID).To(Equal("newUserFirebaseUID"))
+					Expect(requestUser.DisplayName).To(Equal("Renamed in firebase"))
+					Expect(requestUser.PhotoURL).To(Equal("https://example.com/user.png"))
+				})
+
+			})
+
+		})
+
 	})
 
 })
@@ -0,0 +1,104 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// BalanceResource for api2go routes. Balances are worked out from the expenses of a car
+// share whenever they are requested, so are read only and identified by their car share.
+type BalanceResource struct {
+	ExpenseStorage  storage.ExpenseStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	balanceFindDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "balance_find_duration_seconds",
+		Help: "Time taken to find balances",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(balanceFindDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. A car share only has the one balance,
+// found through /carShares/:id/balance
+func (b BalanceResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code := http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all balances not supported"),
+			"balances must be found through their car share",
+			code,
+		)
+	}
+	return b.FindOne(r.QueryParams["carSharesID"][0], r)
+}
+
+// FindOne to satisfy api2go.CRUD interface. The ID of a balance is that of its car share.
+func (b BalanceResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		balanceFindDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, b.TokenVerifier, b.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	carShare, httpErr, code := findCarShare(b.CarShareStorage, ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to access balance for car share %s they are not a member of", requestingUser.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to access",
+			code,
+		)
+	}
+
+	balance, err := ledger.Settle(carShare.GetID(), b.ExpenseStorage, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while settling expenses for car share %s", carShare.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	// members who haven't paid or shared in any expenses are all square
+	for _, memberID := range carShare.MemberIDs {
+		if _, ok := balance.Balances[memberID]; !ok {
+			balance.Balances[memberID] = 0
+		}
+	}
+
+	code = http.StatusOK
+	return &Response{Res: balance, Code: code}, nil
+}
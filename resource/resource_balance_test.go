@@ -0,0 +1,107 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Balance Resource", func() {
+
+	var (
+		balanceResource *BalanceResource
+		request         api2go.Request
+		context         *api2go.APIContext
+		mockVerifier    mockTokenVerifier
+		user1ID         = bson.NewObjectId()
+		user2ID         = bson.NewObjectId()
+		user3ID         = bson.NewObjectId()
+		carShareID      = bson.NewObjectId()
+		result          api2go.Responder
+		err             error
+	)
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		mockVerifier.Claims.Set("sub", "user1FirebaseUID")
+		balanceResource = &BalanceResource{
+			ExpenseStorage:  &mongodb.ExpenseStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: user1ID, FirebaseUID: "user1FirebaseUID"},
+			&model.User{ID: user2ID, FirebaseUID: "user2FirebaseUID"},
+			&model.User{ID: user3ID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{user1ID.Hex(), user2ID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.ExpensesColl).Insert(
+			&model.Expense{
+				Amount:     600,
+				CarShareID: carShareID.Hex(),
+				PayerID:    user1ID.Hex(),
+				Shares:     map[string]int{user1ID.Hex(): 300, user2ID.Hex(): 300},
+			},
+		)
+	})
+
+	Describe("get one", func() {
+
+		It("should settle the expenses of the car share", func() {
+			result, err = balanceResource.FindOne(carShareID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			balance := result.(*Response).Res.(model.Balance)
+			Expect(balance.GetID()).To(Equal(carShareID.Hex()))
+			Expect(balance.Balances).To(Equal(map[string]int{user1ID.Hex(): 300, user2ID.Hex(): -300}))
+			Expect(balance.Transfers).To(Equal([]model.Transfer{{FromID: user2ID.Hex(), ToID: user1ID.Hex(), Amount: 300}}))
+		})
+
+		It("should return a forbidden error to a non member", func() {
+			mockVerifier.Claims.Set("sub", "outsiderFirebaseUID")
+			result, err = balanceResource.FindOne(carShareID.Hex(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", http.StatusForbidden)))
+		})
+
+	})
+
+	Describe("get all", func() {
+
+		It("should find the balance through its car share", func() {
+			request.QueryParams = map[string][]string{"carSharesID": {carShareID.Hex()}}
+			result, err = balanceResource.FindAll(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Balance).GetID()).To(Equal(carShareID.Hex()))
+		})
+
+		It("should return a bad request error without a car share", func() {
+			result, err = balanceResource.FindAll(request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", http.StatusBadRequest)))
+		})
+
+	})
+
+})
@@ -4,10 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
 	"time"
 
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/ledger"
 	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/push"
 	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/webhook"
 	"github.com/LewisWatson/firebase-jwt-auth"
 	"github.com/manyminds/api2go"
 	"github.com/prometheus/client_golang/prometheus"
@@ -19,7 +24,13 @@ type CarShareResource struct {
 	CarShareStorage storage.CarShareStorage
 	TripStorage     storage.TripStorage
 	UserStorage     storage.UserStorage
+	RouteStorage    storage.RouteStorage
+	ExpenseStorage  storage.ExpenseStorage
+	VehicleStorage  storage.VehicleStorage
 	TokenVerifier   fireauth.TokenVerifier
+	Webhooks        *webhook.Notifier
+	Push            *push.Pusher
+	Events          *events.Bus
 }
 
 var (
@@ -88,7 +99,12 @@ func (cs CarShareResource) FindAll(r api2go.Request) (api2go.Responder, error) {
 
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
 	for _, carShare := range result {
-		err = cs.populate(&carShare, r.Context)
+		unit, err := displayUnit(r, carShare)
+		if err != nil {
+			code = http.StatusBadRequest
+			return &Response{}, api2go.NewHTTPError(err, "unit must be km or miles", code)
+		}
+		err = cs.populate(&carShare, unit, r.Context)
 		if err != nil {
 			errMsg := fmt.Sprintf("Error when populating car share %s", carShare.GetID())
 			return &Response{Res: result}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -131,8 +147,14 @@ func (cs CarShareResource) FindOne(ID string, r api2go.Request) (api2go.Responde
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
 	}
 
+	unit, err := displayUnit(r, carShare)
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, "unit must be km or miles", code)
+	}
+
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
-	popErr := cs.populate(&carShare, r.Context)
+	popErr := cs.populate(&carShare, unit, r.Context)
 	if popErr != nil {
 		errMsg := fmt.Sprintf("Error when populating car share %s", carShare.GetID())
 		err = api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -163,6 +185,42 @@ func (cs CarShareResource) Create(obj interface{}, r api2go.Request) (api2go.Res
 			fmt.Errorf("Invalid instance given to car share create: %v", obj), http.StatusText(code), code)
 	}
 
+	carShare.Unit, err = model.ParseUnit(string(carShare.Unit))
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid unit given to car share create: %v", obj), "unit must be km or miles", code)
+	}
+
+	carShare.Scoring, err = model.ParseScoring(string(carShare.Scoring))
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid scoring given to car share create: %v", obj), "scoring must be distance, passenger-distance, trips or short-trip-discount", code)
+	}
+
+	err = carShare.ValidateWindow()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid window given to car share create: %v", obj), err.Error(), code)
+	}
+
+	if carShare.Reminder != nil {
+		err = carShare.Reminder.Validate()
+		if err != nil {
+			code = http.StatusBadRequest
+			return &Response{}, api2go.NewHTTPError(
+				fmt.Errorf("Invalid reminder given to car share create: %v", obj), err.Error(), code)
+		}
+	}
+
+	unit, err := displayUnit(r, carShare)
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, "unit must be km or miles", code)
+	}
+
 	if !carShare.IsMember(requestingUser.GetID()) {
 		carShare.MemberIDs = append(carShare.MemberIDs, requestingUser.GetID())
 	}
@@ -184,7 +242,7 @@ func (cs CarShareResource) Create(obj interface{}, r api2go.Request) (api2go.Res
 	carShare.SetID(id)
 
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
-	popErr := cs.populate(&carShare, r.Context)
+	popErr := cs.populate(&carShare, unit, r.Context)
 	if popErr != nil {
 		errMsg := fmt.Sprintf("Error when populating car share %s", carShare.GetID())
 		err = api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -250,6 +308,29 @@ func (cs CarShareResource) Delete(id string, r api2go.Request) (api2go.Responder
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
 	}
 
+	ok = cs.deleteAssocRoutes(carShare, r.Context)
+	if !ok {
+		errMsg := fmt.Sprintf("Car share deleted, but error occurred while deleting associated routes")
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	ok = cs.deleteAssocExpenses(carShare, r.Context)
+	if !ok {
+		errMsg := fmt.Sprintf("Car share deleted, but error occurred while deleting associated expenses")
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	ok = cs.deleteAssocVehicles(carShare, r.Context)
+	if !ok {
+		errMsg := fmt.Sprintf("Car share deleted, but error occurred while deleting associated vehicles")
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	publish(cs.Events, carShare.GetID(), events.Deleted, carShare)
+
 	code = http.StatusOK
 	return &Response{Code: code}, nil
 }
@@ -266,6 +347,57 @@ func (cs CarShareResource) deleteAssocTrips(carShare model.CarShare, ctx api2go.
 	return ok
 }
 
+func (cs CarShareResource) deleteAssocRoutes(carShare model.CarShare, ctx api2go.APIContexter) bool {
+	routes, err := cs.RouteStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		prometheusLog.Infof("Error retrieving associated routes, %v", err)
+		return false
+	}
+	ok := true
+	for _, route := range routes {
+		err := cs.RouteStorage.Delete(route.GetID(), ctx)
+		if err != nil && err != storage.ErrNotFound {
+			ok = false
+			prometheusLog.Infof("Error deleting associated route %s, %v", route.GetID(), err)
+		}
+	}
+	return ok
+}
+
+func (cs CarShareResource) deleteAssocExpenses(carShare model.CarShare, ctx api2go.APIContexter) bool {
+	expenses, err := cs.ExpenseStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		prometheusLog.Infof("Error retrieving associated expenses, %v", err)
+		return false
+	}
+	ok := true
+	for _, expense := range expenses {
+		err := cs.ExpenseStorage.Delete(expense.GetID(), ctx)
+		if err != nil && err != storage.ErrNotFound {
+			ok = false
+			prometheusLog.Infof("Error deleting associated expense %s, %v", expense.GetID(), err)
+		}
+	}
+	return ok
+}
+
+func (cs CarShareResource) deleteAssocVehicles(carShare model.CarShare, ctx api2go.APIContexter) bool {
+	vehicles, err := cs.VehicleStorage.GetAll(carShare.GetID(), ctx)
+	if err != nil {
+		prometheusLog.Infof("Error retrieving associated vehicles, %v", err)
+		return false
+	}
+	ok := true
+	for _, vehicle := range vehicles {
+		err := cs.VehicleStorage.Delete(vehicle.GetID(), ctx)
+		if err != nil && err != storage.ErrNotFound {
+			ok = false
+			prometheusLog.Infof("Error deleting associated vehicle %s, %v", vehicle.GetID(), err)
+		}
+	}
+	return ok
+}
+
 // Update to satisfy api2go.CRUD interface
 func (cs CarShareResource) Update(obj interface{}, r api2go.Request) (api2go.Responder, error) {
 
@@ -301,6 +433,47 @@ func (cs CarShareResource) Update(obj interface{}, r api2go.Request) (api2go.Res
 		)
 	}
 
+	carShare.Unit, err = model.ParseUnit(string(carShare.Unit))
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid unit given to car share update: %v", obj), "unit must be km or miles", code)
+	}
+
+	carShare.Scoring, err = model.ParseScoring(string(carShare.Scoring))
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid scoring given to car share update: %v", obj), "scoring must be distance, passenger-distance, trips or short-trip-discount", code)
+	}
+
+	err = carShare.ValidateWindow()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid window given to car share update: %v", obj), err.Error(), code)
+	}
+
+	if carShare.Reminder != nil {
+		err = carShare.Reminder.Validate()
+		if err != nil {
+			code = http.StatusBadRequest
+			return &Response{}, api2go.NewHTTPError(
+				fmt.Errorf("Invalid reminder given to car share update: %v", obj), err.Error(), code)
+		}
+	}
+
+	// the notifier works out when a changed reminder is next due
+	if !reflect.DeepEqual(carShare.Reminder, existingCarShare.Reminder) {
+		carShare.NextReminder = time.Time{}
+	}
+
+	unit, err := displayUnit(r, carShare)
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, "unit must be km or miles", code)
+	}
+
 	// verify that tripID's link to real trips, and if required set those trips as belonging to this car share
 	for _, tripID := range carShare.TripIDs {
 
@@ -354,8 +527,37 @@ func (cs CarShareResource) Update(obj interface{}, r api2go.Request) (api2go.Res
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
 	}
 
+	// every trip's scores need recalculating under a new scoring strategy. Car shares stored
+	// before they had a strategy use the default
+	previousScoring, _ := model.ParseScoring(string(existingCarShare.Scoring))
+	if carShare.Scoring != previousScoring {
+		err = ledger.Rebuild(carShare, cs.TripStorage, r.Context)
+		if err != nil {
+			errMsg := fmt.Sprintf("Car share %s updated but error occurred while rebuilding its scores", carShare.GetID())
+			code = http.StatusInternalServerError
+			return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+		}
+	}
+
+	added, removed := []string{}, []string{}
+	for _, memberID := range carShare.MemberIDs {
+		if !existingCarShare.IsMember(memberID) {
+			added = append(added, memberID)
+		}
+	}
+	for _, memberID := range existingCarShare.MemberIDs {
+		if !carShare.IsMember(memberID) {
+			removed = append(removed, memberID)
+		}
+	}
+	notifyMembers(cs.Webhooks, cs.UserStorage, carShare.GetID(), model.MemberAdded, added, r.Context)
+	pushMembers(cs.Push, cs.UserStorage, carShare.GetID(), model.MemberAdded, added, requestingUser.GetID(), r.Context)
+	notifyMembers(cs.Webhooks, cs.UserStorage, carShare.GetID(), model.MemberRemoved, removed, r.Context)
+	pushMembers(cs.Push, cs.UserStorage, carShare.GetID(), model.MemberRemoved, removed, requestingUser.GetID(), r.Context)
+	publish(cs.Events, carShare.GetID(), events.Updated, carShare)
+
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
-	popErr := cs.populate(&carShare, r.Context)
+	popErr := cs.populate(&carShare, unit, r.Context)
 	if popErr != nil {
 		errMsg := fmt.Sprintf("Error when populating car share %s", carShare.GetID())
 		err = api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -365,8 +567,8 @@ func (cs CarShareResource) Update(obj interface{}, r api2go.Request) (api2go.Res
 	return &Response{Res: carShare, Code: code}, err
 }
 
-// populate the relationships for a car share
-func (cs CarShareResource) populate(carShare *model.CarShare, context api2go.APIContexter) error {
+// populate the relationships for a car share, presenting trip distances in the given unit
+func (cs CarShareResource) populate(carShare *model.CarShare, unit model.Unit, context api2go.APIContexter) error {
 
 	carShare.Trips = nil
 	for _, tripID := range carShare.TripIDs {
@@ -374,6 +576,7 @@ func (cs CarShareResource) populate(carShare *model.CarShare, context api2go.API
 		if err != nil {
 			return err
 		}
+		trip.FormatDistances(unit)
 		carShare.Trips = append(carShare.Trips, trip)
 	}
 
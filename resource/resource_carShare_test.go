@@ -3,6 +3,7 @@ package resource
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/LewisWatson/carshare-back/model"
 	"github.com/LewisWatson/carshare-back/storage"
@@ -41,6 +42,9 @@ var _ = Describe("car share resource", func() {
 			CarShareStorage: &mongodb.CarShareStorage{},
 			TripStorage:     &mongodb.TripStorage{},
 			UserStorage:     &mongodb.UserStorage{},
+			RouteStorage:    &mongodb.RouteStorage{},
+			ExpenseStorage:  &mongodb.ExpenseStorage{},
+			VehicleStorage:  &mongodb.VehicleStorage{},
 			TokenVerifier:   mockTokenVerifier,
 		}
 		context = &api2go.APIContext{}
@@ -339,6 +343,93 @@ var _ = Describe("car share resource", func() {
 
 		})
 
+		Context("scoring strategy", func() {
+
+			BeforeEach(func() {
+				db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).UpdateId(trip1ID, bson.M{"$set": bson.M{"driver": user1ID.Hex()}})
+				carShare, err = carShareResource.CarShareStorage.GetOne(carShare1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				carShare.Scoring = model.TripScoring
+				result, err = carShareResource.Update(carShare, request)
+			})
+
+			It("should rebuild the scores of the car share's trips with the new strategy", func() {
+				Expect(err).ToNot(HaveOccurred())
+				trip, err := carShareResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trip.Scores[user1ID.Hex()].MetresAsDriver).To(Equal(123))
+				Expect(trip.Scores[user1ID.Hex()].PointsAsDriver).To(Equal(1))
+			})
+
+			Context("that isn't built in", func() {
+
+				BeforeEach(func() {
+					carShare.Scoring = "coin-toss"
+					result, err = carShareResource.Update(carShare, request)
+				})
+
+				It("should return a bad request error", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+					Expect(err.Error()).To(HavePrefix("http error (400)"))
+				})
+
+			})
+
+		})
+
+		Context("negative rolling window", func() {
+
+			BeforeEach(func() {
+				carShare, err = carShareResource.CarShareStorage.GetOne(carShare1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				carShare.WindowDays = -90
+				result, err = carShareResource.Update(carShare, request)
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				Expect(err.Error()).To(HavePrefix("http error (400)"))
+			})
+
+		})
+
+		Context("reminder", func() {
+
+			BeforeEach(func() {
+				carShare, err = carShareResource.CarShareStorage.GetOne(carShare1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				carShare.NextReminder = time.Now().UTC()
+				carShare.Reminder = &model.Reminder{Weekdays: []string{"monday", "friday"}, Time: "18:00", TimeZone: "Europe/London"}
+				result, err = carShareResource.Update(carShare, request)
+			})
+
+			It("should leave the notifier to work out when it is next due", func() {
+				Expect(err).ToNot(HaveOccurred())
+				carShare, err = carShareResource.CarShareStorage.GetOne(carShare1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(carShare.Reminder.Weekdays).To(Equal([]string{"monday", "friday"}))
+				Expect(carShare.NextReminder.IsZero()).To(BeTrue())
+			})
+
+			Context("on an unknown weekday", func() {
+
+				BeforeEach(func() {
+					carShare.Reminder = &model.Reminder{Weekdays: []string{"caturday"}, Time: "18:00", TimeZone: "UTC"}
+					result, err = carShareResource.Update(carShare, request)
+				})
+
+				It("should return a bad request error", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+					Expect(err.Error()).To(HavePrefix("http error (400)"))
+				})
+
+			})
+
+		})
+
 		Context("relationship", func() {
 
 			BeforeEach(func() {
@@ -0,0 +1,155 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DeliveryResource for api2go routes. Deliveries are queued by car share events and sent by
+// the webhook deliverer, so are read only. Like their webhooks, only admins of the car share
+// can see them.
+type DeliveryResource struct {
+	DeliveryStorage storage.DeliveryStorage
+	WebhookStorage  storage.WebhookStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	deliveryFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "delivery_find_all_duration_seconds",
+		Help: "Time taken to find all deliveries",
+	}, []string{"code"})
+	deliveryFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "delivery_find_one_duration_seconds",
+		Help: "Time taken to find one delivery",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(deliveryFindAllDurationSeconds)
+	prometheus.MustRegister(deliveryFindOneDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Deliveries are only listed for a webhook,
+// through /webhooks/:id/deliveries
+func (d DeliveryResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		deliveryFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, d.TokenVerifier, d.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	if len(r.QueryParams["webhooksID"]) == 0 {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all deliveries not supported"),
+			"deliveries must be found through their webhook",
+			code,
+		)
+	}
+	webhookID := r.QueryParams["webhooksID"][0]
+
+	httpErr, code := d.verifyAdmin(requestingUser, webhookID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	result, err := d.DeliveryStorage.GetAll(webhookID, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving deliveries for webhook %s", webhookID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (d DeliveryResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		deliveryFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, d.TokenVerifier, d.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	delivery, err := d.DeliveryStorage.GetOne(ID, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find delivery %s", ID), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving delivery %s", ID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	httpErr, code := d.verifyAdmin(requestingUser, delivery.WebhookID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	code = http.StatusOK
+	return &Response{Res: delivery, Code: code}, nil
+}
+
+// verifyAdmin checks the user is an admin of the car share the webhook belongs to
+func (d DeliveryResource) verifyAdmin(user model.User, webhookID string, ctx api2go.APIContexter) (httpErr error, code int) {
+
+	webhook, httpErr, code := findWebhook(d.WebhookStorage, webhookID, ctx)
+	if httpErr != nil {
+		return httpErr, code
+	}
+
+	carShare, httpErr, code := findCarShare(d.CarShareStorage, webhook.CarShareID, ctx)
+	if httpErr != nil {
+		return httpErr, code
+	}
+
+	if !carShare.IsAdmin(user.GetID()) {
+		code = http.StatusForbidden
+		return api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to access deliveries to webhook %v", user.GetID(), webhook.GetID()),
+			http.StatusText(code),
+			code,
+		), code
+	}
+
+	return nil, code
+}
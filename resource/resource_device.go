@@ -0,0 +1,221 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DeviceResource for api2go routes. Users register their own devices for push notifications,
+// and can only see and remove their own.
+type DeviceResource struct {
+	DeviceStorage storage.DeviceStorage
+	UserStorage   storage.UserStorage
+	TokenVerifier fireauth.TokenVerifier
+	Clock         clock.Clock
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	deviceFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "device_find_all_duration_seconds",
+		Help: "Time taken to find all devices",
+	}, []string{"code"})
+	deviceFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "device_find_one_duration_seconds",
+		Help: "Time taken to find one device",
+	}, []string{"code"})
+	deviceCreateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "device_create_duration_seconds",
+		Help: "Time taken to register devices",
+	}, []string{"code"})
+	deviceDeleteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "device_delete_duration_seconds",
+		Help: "Time taken to delete devices",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(deviceFindAllDurationSeconds)
+	prometheus.MustRegister(deviceFindOneDurationSeconds)
+	prometheus.MustRegister(deviceCreateDurationSeconds)
+	prometheus.MustRegister(deviceDeleteDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Only the requesting user's devices are listed.
+func (d DeviceResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		deviceFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, d.TokenVerifier, d.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	result, err := d.DeviceStorage.GetAll(requestingUser.GetID(), r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving devices for user %s", requestingUser.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (d DeviceResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		deviceFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, d.TokenVerifier, d.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	device, httpErr, code := d.findOwnDevice(requestingUser, ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	code = http.StatusOK
+	return &Response{Res: device, Code: code}, nil
+}
+
+// Create to satisfy api2go.CRUD interface. A token registered before, by anyone, is moved to
+// the requesting user, as whoever last signed in on a device is the one it belongs to.
+func (d DeviceResource) Create(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		deviceCreateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, d.TokenVerifier, d.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	device, ok := obj.(model.Device)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to device create: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	err = device.Validate()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, err.Error(), code)
+	}
+
+	device.UserID = requestingUser.GetID()
+	device.Created = d.Clock.Now().UTC()
+
+	err = d.DeviceStorage.DeleteToken(device.Token, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while replacing existing registrations of device"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	id, err := d.DeviceStorage.Insert(device, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while persisting device"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	device.SetID(id)
+
+	code = http.StatusCreated
+	return &Response{Res: device, Code: code}, nil
+}
+
+// Delete to satisfy the api2go.CRUD interface, for users to stop notifications to a device
+func (d DeviceResource) Delete(id string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		deviceDeleteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, d.TokenVerifier, d.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	_, httpErr, code := d.findOwnDevice(requestingUser, id, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	err = d.DeviceStorage.Delete(id, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find device %s to delete", id), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting device %s", id)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Code: code}, nil
+}
+
+// findOwnDevice finds one of the user's devices. Other users' devices are treated as missing so
+// their existence isn't given away.
+func (d DeviceResource) findOwnDevice(user model.User, ID string, ctx api2go.APIContexter) (device model.Device, httpErr error, code int) {
+	device, err := d.DeviceStorage.GetOne(ID, ctx)
+	switch {
+	case err == nil && device.UserID == user.GetID():
+		return device, httpErr, http.StatusOK
+	case err == nil, err == storage.ErrNotFound, err == storage.ErrInvalidID:
+		code = http.StatusNotFound
+		return device, api2go.NewHTTPError(fmt.Errorf("unable to find device %s for user %s", ID, user.GetID()), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving device %s", ID)
+		code = http.StatusInternalServerError
+		return device, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
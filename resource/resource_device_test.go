@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Device Resource", func() {
+
+	var (
+		deviceResource  *DeviceResource
+		request         api2go.Request
+		context         *api2go.APIContext
+		mockVerifier    mockTokenVerifier
+		mockClock       *clock.Mock
+		aliceID         = bson.NewObjectId()
+		bobID           = bson.NewObjectId()
+		device1ID       = bson.NewObjectId()
+		result          api2go.Responder
+		err             error
+		asUser          func(firebaseUID string)
+		expectHTTPError func(code int)
+	)
+
+	asUser = func(firebaseUID string) {
+		mockVerifier.Claims.Set("sub", firebaseUID)
+	}
+
+	expectHTTPError = func(code int) {
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+		Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", code)))
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		asUser("aliceFirebaseUID")
+		mockClock = clock.NewMock()
+		mockClock.Set(time.Date(2017, time.March, 6, 8, 0, 0, 0, time.UTC))
+		deviceResource = &DeviceResource{
+			DeviceStorage: &mongodb.DeviceStorage{},
+			UserStorage:   &mongodb.UserStorage{},
+			TokenVerifier: mockVerifier,
+			Clock:         mockClock,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: aliceID, FirebaseUID: "aliceFirebaseUID"},
+			&model.User{ID: bobID, FirebaseUID: "bobFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.DevicesColl).Insert(
+			&model.Device{ID: device1ID, Token: "alice-phone", Platform: model.Android, UserID: aliceID.Hex()},
+			&model.Device{Token: "bob-phone", Platform: model.IOS, UserID: bobID.Hex()},
+		)
+	})
+
+	Describe("find all", func() {
+
+		It("should only list the requesting user's devices", func() {
+			result, err = deviceResource.FindAll(request)
+			Expect(err).ToNot(HaveOccurred())
+			devices := result.Result().([]model.Device)
+			Expect(devices).To(HaveLen(1))
+			Expect(devices[0].Token).To(Equal("alice-phone"))
+		})
+
+	})
+
+	Describe("find one", func() {
+
+		It("should find the requesting user's device", func() {
+			result, err = deviceResource.FindOne(device1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Result().(model.Device).Token).To(Equal("alice-phone"))
+		})
+
+		It("should not find another user's device", func() {
+			asUser("bobFirebaseUID")
+			result, err = deviceResource.FindOne(device1ID.Hex(), request)
+			expectHTTPError(http.StatusNotFound)
+		})
+
+	})
+
+	Describe("create", func() {
+
+		It("should register the device for the requesting user", func() {
+			result, err = deviceResource.Create(model.Device{Token: "alice-laptop", Platform: model.Web}, request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.StatusCode()).To(Equal(http.StatusCreated))
+			device := result.Result().(model.Device)
+			Expect(device.UserID).To(Equal(aliceID.Hex()))
+			Expect(device.Created).To(Equal(mockClock.Now().UTC()))
+		})
+
+		It("should move a token registered by another user to the requesting user", func() {
+			result, err = deviceResource.Create(model.Device{Token: "bob-phone", Platform: model.IOS}, request)
+			Expect(err).ToNot(HaveOccurred())
+			devices, err := deviceResource.DeviceStorage.GetAll(bobID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(devices).To(BeEmpty())
+		})
+
+		It("should reject a device on an unknown platform", func() {
+			result, err = deviceResource.Create(model.Device{Token: "alice-watch", Platform: "watch"}, request)
+			expectHTTPError(http.StatusBadRequest)
+		})
+
+	})
+
+	Describe("delete", func() {
+
+		It("should delete the requesting user's device", func() {
+			result, err = deviceResource.Delete(device1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			devices, err := deviceResource.DeviceStorage.GetAll(aliceID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(devices).To(BeEmpty())
+		})
+
+		It("should not delete another user's device", func() {
+			asUser("bobFirebaseUID")
+			result, err = deviceResource.Delete(device1ID.Hex(), request)
+			expectHTTPError(http.StatusNotFound)
+		})
+
+	})
+
+})
@@ -0,0 +1,269 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/websocket"
+)
+
+// heartbeatInterval is how often an idle stream is pinged so proxies don't close it
+const heartbeatInterval = 30 * time.Second
+
+// EventsResource streams changes to a car share to its members as they happen, as server-sent
+// events or over a WebSocket
+type EventsResource struct {
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Bus             *events.Bus
+	Tickets         *events.Tickets
+	Clock           clock.Clock
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	eventStreamsOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "event_streams_open",
+		Help: "Number of car share event streams currently open",
+	})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(eventStreamsOpen)
+
+}
+
+// eventWriter sends events to a client over one of the streaming transports
+type eventWriter interface {
+	write(event events.Event) error
+	ping() error
+}
+
+// IssueTicket gives a member of the car share a ticket to open a stream of its events with.
+// Browsers can't set headers on event streams or WebSockets, so they pass the ticket in the
+// ticket query parameter instead of their token.
+func (e EventsResource) IssueTicket(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code := http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, ID, r.Context)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+		return
+	}
+
+	ticket, err := e.Tickets.Issue(carShare.GetID(), requestingUser.GetID())
+	if err != nil {
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, "Error issuing ticket", code)
+		return
+	}
+
+	writeResource(w, ticket, http.StatusCreated)
+}
+
+// Stream the events of the car share. Clients that reconnect pass the ID of the last event
+// they saw in the Last-Event-ID header (or lastEventId query parameter) to catch up on what
+// they missed. Clients that can't set the Authorization header pass a ticket from IssueTicket
+// in the ticket query parameter.
+func (e EventsResource) Stream(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	requestingUser, err := e.streamingUser(ID, r)
+	if err != nil {
+		code := http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, ID, r.Context)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+		return
+	}
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" && len(r.QueryParams["lastEventId"]) > 0 {
+		lastEventID = r.QueryParams["lastEventId"][0]
+	}
+
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		websocket.Server{
+			Handler: func(conn *websocket.Conn) {
+				closed := make(chan struct{})
+				go func() {
+					// nothing is expected from the client, reading just notices it going away
+					var discard []byte
+					for websocket.Message.Receive(conn, &discard) == nil {
+					}
+					close(closed)
+				}()
+				subscription := e.Bus.Subscribe(carShare.GetID(), lastEventID)
+				defer subscription.Close()
+				e.stream(webSocketWriter{conn}, subscription, requestingUser, carShare.GetID(), closed, r.Context)
+			},
+		}.ServeHTTP(w, r.PlainRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		code = http.StatusInternalServerError
+		writeHTTPError(w, fmt.Errorf("response writer doesn't support flushing"), "streaming not supported", code)
+		return
+	}
+
+	// subscribe before responding so nothing published once the client is connected is missed
+	subscription := e.Bus.Subscribe(carShare.GetID(), lastEventID)
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	e.stream(sseWriter{w, flusher}, subscription, requestingUser, carShare.GetID(), r.PlainRequest.Context().Done(), r.Context)
+}
+
+// streamingUser is the user a ticket was issued to, or the user of the token otherwise
+func (e EventsResource) streamingUser(ID string, r api2go.Request) (model.User, error) {
+	if len(r.QueryParams["ticket"]) == 0 {
+		return getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	}
+	userID, ok := e.Tickets.Redeem(r.QueryParams["ticket"][0], ID)
+	if !ok {
+		return model.User{}, errors.New("invalid or expired ticket")
+	}
+	return e.UserStorage.GetOne(userID, r.Context)
+}
+
+// stream events to the client until it goes away, the bus drops it for falling behind or it
+// stops being a member of the car share
+func (e EventsResource) stream(out eventWriter, subscription *events.Subscription, user model.User, carShareID string, closed <-chan struct{}, ctx api2go.APIContexter) {
+
+	eventStreamsOpen.Inc()
+	defer eventStreamsOpen.Dec()
+
+	for _, event := range subscription.Replay {
+		if out.write(event) != nil {
+			return
+		}
+	}
+
+	heartbeat := e.Clock.Ticker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case <-heartbeat.C:
+			if out.ping() != nil {
+				return
+			}
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return
+			}
+			if out.write(event) != nil {
+				return
+			}
+			if strings.HasPrefix(event.Type, "carShares.") && !e.stillMember(user, carShareID, ctx) {
+				return
+			}
+		}
+	}
+}
+
+// stillMember checks the user hasn't been removed from the car share, or the car share deleted
+func (e EventsResource) stillMember(user model.User, carShareID string, ctx api2go.APIContexter) bool {
+	carShare, err := e.CarShareStorage.GetOne(carShareID, ctx)
+	if err != nil {
+		return false
+	}
+	return carShare.IsMember(user.GetID())
+}
+
+// sseWriter sends events as server-sent events
+type sseWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (s sseWriter) write(event events.Event) error {
+	var err error
+	if event.Type == events.Reset {
+		_, err = fmt.Fprintf(s.w, "id: %s\nevent: %s\ndata: {}\n\n", event.EventID(), event.Type)
+	} else {
+		_, err = fmt.Fprintf(s.w, "id: %s\nevent: %s\ndata: %s\n\n", event.EventID(), event.Type, event.Data)
+	}
+	s.flusher.Flush()
+	return err
+}
+
+func (s sseWriter) ping() error {
+	_, err := fmt.Fprint(s.w, ": ping\n\n")
+	s.flusher.Flush()
+	return err
+}
+
+// webSocketWriter sends events as JSON messages over a WebSocket
+type webSocketWriter struct {
+	conn *websocket.Conn
+}
+
+// webSocketMessage is the JSON sent for each event over a WebSocket, mirroring the fields of a
+// server-sent event
+type webSocketMessage struct {
+	ID    string          `json:"id,omitempty"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+func (s webSocketWriter) write(event events.Event) error {
+	return websocket.JSON.Send(s.conn, webSocketMessage{
+		ID:    event.EventID(),
+		Event: event.Type,
+		Data:  event.Data,
+	})
+}
+
+func (s webSocketWriter) ping() error {
+	return websocket.JSON.Send(s.conn, webSocketMessage{Event: "ping"})
+}
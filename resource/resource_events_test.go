@@ -0,0 +1,213 @@
+package resource
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Events Resource", func() {
+
+	var (
+		eventsResource *EventsResource
+		bus            *events.Bus
+		context        *api2go.APIContext
+		mockVerifier   mockTokenVerifier
+		server         *httptest.Server
+		responses      []*http.Response
+		memberID       = bson.NewObjectId()
+		outsiderID     = bson.NewObjectId()
+		carShareID     = bson.NewObjectId()
+		trip           model.Trip
+		connect        func(lastEventID string) (*http.Response, *bufio.Reader)
+		connectWith    func(query string) *http.Response
+		readEvent      func(in *bufio.Reader) map[string]string
+	)
+
+	// connect opens the stream, closing it again when the test is done
+	connect = func(lastEventID string) (*http.Response, *bufio.Reader) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		Expect(err).ToNot(HaveOccurred())
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).ToNot(HaveOccurred())
+		responses = append(responses, resp)
+		return resp, bufio.NewReader(resp.Body)
+	}
+
+	// connectWith opens the stream with the given query string
+	connectWith = func(query string) *http.Response {
+		resp, err := http.Get(server.URL + "?" + query)
+		Expect(err).ToNot(HaveOccurred())
+		responses = append(responses, resp)
+		return resp
+	}
+
+	// readEvent reads the fields of the next server-sent event, skipping pings
+	readEvent = func(in *bufio.Reader) map[string]string {
+		fields := map[string]string{}
+		for {
+			line, err := in.ReadString('\n')
+			Expect(err).ToNot(HaveOccurred())
+			line = strings.TrimSuffix(line, "\n")
+			if line == "" && len(fields) > 0 {
+				return fields
+			}
+			if parts := strings.SplitN(line, ": ", 2); len(parts) == 2 && parts[0] != "" {
+				fields[parts[0]] = parts[1]
+			}
+		}
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		mockVerifier.Claims.Set("sub", "memberFirebaseUID")
+		bus = events.NewBus(events.DefaultHistory, clock.New())
+		eventsResource = &EventsResource{
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+			Bus:             bus,
+			Tickets:         events.NewTickets(events.DefaultTicketTTL, clock.New()),
+			Clock:           clock.New(),
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+			&model.User{ID: outsiderID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{memberID.Hex()},
+				AdminIDs:  []string{memberID.Hex()},
+			},
+		)
+		trip = model.Trip{Metres: 1000, CarShareID: carShareID.Hex()}
+		trip.SetID(bson.NewObjectId().Hex())
+
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			eventsResource.Stream(carShareID.Hex(), w, api2go.Request{
+				PlainRequest: r,
+				QueryParams:  r.URL.Query(),
+				Header:       r.Header,
+				Context:      context,
+			})
+		}))
+	})
+
+	AfterEach(func() {
+		for _, resp := range responses {
+			resp.Body.Close()
+		}
+		responses = nil
+		server.CloseClientConnections()
+		server.Close()
+	})
+
+	It("should stream changes to the car share", func() {
+		resp, in := connect("")
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("Content-Type")).To(Equal("text/event-stream"))
+
+		Expect(bus.Publish(carShareID.Hex(), events.Created, trip)).To(Succeed())
+		event := readEvent(in)
+		Expect(event["event"]).To(Equal("trips.created"))
+		Expect(event["id"]).ToNot(BeEmpty())
+		Expect(event["data"]).To(ContainSubstring(trip.GetID()))
+	})
+
+	It("should replay changes missed since the last event seen", func() {
+		Expect(bus.Publish(carShareID.Hex(), events.Created, trip)).To(Succeed())
+		_, in := connect("")
+		Expect(bus.Publish(carShareID.Hex(), events.Updated, trip)).To(Succeed())
+		first := readEvent(in)
+
+		Expect(bus.Publish(carShareID.Hex(), events.Deleted, trip)).To(Succeed())
+		_, in = connect(first["id"])
+		Expect(readEvent(in)["event"]).To(Equal("trips.deleted"))
+	})
+
+	It("should not stream a car share to someone who isn't a member", func() {
+		mockVerifier.Claims.Set("sub", "outsiderFirebaseUID")
+		resp, _ := connect("")
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+	})
+
+	Describe("tickets", func() {
+
+		var ticket events.Ticket
+
+		BeforeEach(func() {
+			recorder := httptest.NewRecorder()
+			eventsResource.IssueTicket(carShareID.Hex(), recorder, api2go.Request{Context: context})
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			var document struct {
+				Data struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+			}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+			Expect(document.Data.Type).To(Equal("tickets"))
+			ticket.ID = document.Data.ID
+
+			// the stream can only be opened with the ticket from now on
+			eventsResource.TokenVerifier = mockTokenVerifier{Claims: mockVerifier.Claims, Error: errors.New("no token")}
+		})
+
+		It("should stream changes to the member the ticket was issued to", func() {
+			resp := connectWith("ticket=" + ticket.ID)
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		It("should only open one stream with a ticket", func() {
+			connectWith("ticket=" + ticket.ID)
+			resp := connectWith("ticket=" + ticket.ID)
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not open a stream with a ticket that wasn't issued", func() {
+			resp := connectWith("ticket=not-a-ticket")
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not accept a token in place of a ticket", func() {
+			resp := connectWith("token=token")
+			Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not issue tickets to someone who isn't a member", func() {
+			mockVerifier.Claims.Set("sub", "outsiderFirebaseUID")
+			eventsResource.TokenVerifier = mockVerifier
+			recorder := httptest.NewRecorder()
+			eventsResource.IssueTicket(carShareID.Hex(), recorder, api2go.Request{Context: context})
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+	})
+
+})
@@ -0,0 +1,442 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ExpenseResource for api2go routes. Expenses can be recorded by any member of their car
+// share but only changed by whoever paid them or an admin.
+type ExpenseResource struct {
+	ExpenseStorage  storage.ExpenseStorage
+	TripStorage     storage.TripStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Events          *events.Bus
+	Clock           clock.Clock
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	expenseFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "expense_find_all_duration_seconds",
+		Help: "Time taken to find all expenses",
+	}, []string{"code"})
+	expenseFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "expense_find_one_duration_seconds",
+		Help: "Time taken to find one expense",
+	}, []string{"code"})
+	expenseCreateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "expense_create_duration_seconds",
+		Help: "Time taken to create expenses",
+	}, []string{"code"})
+	expenseDeleteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "expense_delete_duration_seconds",
+		Help: "Time taken to delete expenses",
+	}, []string{"code"})
+	expenseUpdateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "expense_update_duration_seconds",
+		Help: "Time taken to update expenses",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(expenseFindAllDurationSeconds)
+	prometheus.MustRegister(expenseFindOneDurationSeconds)
+	prometheus.MustRegister(expenseCreateDurationSeconds)
+	prometheus.MustRegister(expenseDeleteDurationSeconds)
+	prometheus.MustRegister(expenseUpdateDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Expenses are only listed for a car share,
+// through /carShares/:id/expenses
+func (e ExpenseResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		expenseFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all expenses not supported"),
+			"expenses must be found through their car share",
+			code,
+		)
+	}
+	carShareID := r.QueryParams["carSharesID"][0]
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, carShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+	}
+
+	result, err := e.ExpenseStorage.GetAll(carShareID, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving expenses for car share %s", carShareID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (e ExpenseResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		expenseFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	expense, httpErr, code := e.expense(ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, expense.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to access expense %s for car share %s they are not a member of", requestingUser.GetID(), expense.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to access",
+			code,
+		)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: expense, Code: code}, nil
+}
+
+// Create to satisfy api2go.CRUD interface
+func (e ExpenseResource) Create(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		expenseCreateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	expense, ok := obj.(model.Expense)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to expense create: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	if expense.CarShareID == "" {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to expense create (missing carShareID): %v", obj),
+			"must provide a carShareID",
+			code,
+		)
+	}
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, expense.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to record expense for car share %s they are not a member of", requestingUser.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to record expenses",
+			code,
+		)
+	}
+
+	if expense.PayerID == "" {
+		expense.PayerID = requestingUser.GetID()
+	}
+
+	if expense.TimeStamp.IsZero() {
+		expense.TimeStamp = e.Clock.Now().UTC()
+	}
+
+	httpErr, code = e.prepare(&expense, carShare, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	id, err := e.ExpenseStorage.Insert(expense, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while persisting expense"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	expense.SetID(id)
+	publish(e.Events, expense.CarShareID, events.Created, expense)
+
+	code = http.StatusCreated
+	return &Response{Res: expense, Code: code}, nil
+}
+
+// Delete to satisfy the api2go.CRUD interface
+func (e ExpenseResource) Delete(id string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		expenseDeleteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	expense, httpErr, code := e.expense(id, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, expense.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if expense.PayerID != requestingUser.GetID() && !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %v attempting to delete expense %v paid by %v without being an admin", requestingUser.GetID(), expense.GetID(), expense.PayerID),
+			"only whoever paid an expense or an admin can delete it",
+			code,
+		)
+	}
+
+	err = e.ExpenseStorage.Delete(id, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find expense %s to delete", id), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting expense %s", id)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(e.Events, expense.CarShareID, events.Deleted, expense)
+
+	code = http.StatusOK
+	return &Response{Code: code}, nil
+}
+
+// Update to satisfy api2go.CRUD interface
+func (e ExpenseResource) Update(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		expenseUpdateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	expense, ok := obj.(model.Expense)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to expense update: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	expenseInDataStore, httpErr, code := e.expense(expense.GetID(), r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// Prevent expenses from being re-assigned car shares
+	if expenseInDataStore.CarShareID != expense.CarShareID {
+		errMsg := fmt.Sprintf("expense %s already belongs to another car share", expense.GetID())
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	carShare, httpErr, code := findCarShare(e.CarShareStorage, expense.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// important to check against the expense in the data store
+	if expenseInDataStore.PayerID != requestingUser.GetID() && !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %v attempting to update expense %v paid by %v without being an admin", requestingUser.GetID(), expense.GetID(), expenseInDataStore.PayerID),
+			"only whoever paid an expense or an admin can update it",
+			code,
+		)
+	}
+
+	httpErr, code = e.prepare(&expense, carShare, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	err = e.ExpenseStorage.Update(expense, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Unable to find expense %s to update", expense.GetID()), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while updating expense %s", expense.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(e.Events, expense.CarShareID, events.Updated, expense)
+
+	code = http.StatusNoContent
+	return &Response{Res: expense, Code: code}, nil
+}
+
+// expense retrieves an expense, translating storage errors into http errors
+func (e ExpenseResource) expense(ID string, ctx api2go.APIContexter) (expense model.Expense, httpErr error, code int) {
+
+	expense, err := e.ExpenseStorage.GetOne(ID, ctx)
+	switch err {
+	case nil:
+		return expense, nil, code
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return expense, api2go.NewHTTPError(fmt.Errorf("unable to find expense %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving expense %s", ID)
+		code = http.StatusInternalServerError
+		return expense, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
+
+// prepare validates an expense and shares it between its participants. Participants
+// default to everyone in the expense's trip, or otherwise every member of the car share.
+func (e ExpenseResource) prepare(expense *model.Expense, carShare model.CarShare, ctx api2go.APIContexter) (httpErr error, code int) {
+
+	badRequest := func(err error) (error, int) {
+		code = http.StatusBadRequest
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	err := expense.Validate()
+	if err != nil {
+		return badRequest(err)
+	}
+
+	if !carShare.IsMember(expense.PayerID) {
+		return badRequest(fmt.Errorf("payer %s is not a member of car share %s", expense.PayerID, carShare.GetID()))
+	}
+
+	if expense.TripID != "" {
+		trip, err := e.TripStorage.GetOne(expense.TripID, ctx)
+		switch err {
+		case nil:
+			break
+		case storage.ErrNotFound, storage.ErrInvalidID:
+			return badRequest(fmt.Errorf("unable to find trip %s", expense.TripID))
+		default:
+			errMsg := fmt.Sprintf("Error occurred while retrieving trip %s", expense.TripID)
+			code = http.StatusInternalServerError
+			return api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+		}
+		if trip.CarShareID != carShare.GetID() {
+			return badRequest(fmt.Errorf("trip %s belongs to another car share", expense.TripID))
+		}
+		if len(expense.ParticipantIDs) == 0 {
+			expense.ParticipantIDs = append([]string{}, trip.PassengerIDs...)
+			if trip.DriverID != "" {
+				expense.ParticipantIDs = append(expense.ParticipantIDs, trip.DriverID)
+			}
+		}
+	}
+
+	if len(expense.ParticipantIDs) == 0 {
+		expense.ParticipantIDs = append([]string{}, carShare.MemberIDs...)
+	}
+	sort.Strings(expense.ParticipantIDs)
+
+	for _, participantID := range expense.ParticipantIDs {
+		if !carShare.IsMember(participantID) {
+			return badRequest(fmt.Errorf("participant %s is not a member of car share %s", participantID, carShare.GetID()))
+		}
+	}
+
+	err = ledger.Share(expense, e.TripStorage, ctx)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while sharing expense between members of car share %s", carShare.GetID())
+		code = http.StatusInternalServerError
+		return api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	return nil, code
+}
@@ -0,0 +1,375 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Expense Resource", func() {
+
+	var (
+		expenseResource *ExpenseResource
+		request         api2go.Request
+		context         *api2go.APIContext
+		mockVerifier    mockTokenVerifier
+		adminID         = bson.NewObjectId()
+		memberID        = bson.NewObjectId()
+		outsiderID      = bson.NewObjectId()
+		carShareID      = bson.NewObjectId()
+		tripID          = bson.NewObjectId()
+		expense1ID      = bson.NewObjectId()
+		result          api2go.Responder
+		err             error
+		asUser          func(firebaseUID string)
+		expectHTTPError func(code int)
+	)
+
+	asUser = func(firebaseUID string) {
+		mockVerifier.Claims.Set("sub", firebaseUID)
+	}
+
+	expectHTTPError = func(code int) {
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+		Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", code)))
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		asUser("memberFirebaseUID")
+		expenseResource = &ExpenseResource{
+			ExpenseStorage:  &mongodb.ExpenseStorage{},
+			TripStorage:     &mongodb.TripStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+			Clock:           clock.NewMock(),
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+			&model.User{ID: outsiderID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), memberID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+				TripIDs:   []string{tripID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).Insert(
+			&model.Trip{
+				ID:           tripID,
+				Metres:       3000,
+				CarShareID:   carShareID.Hex(),
+				DriverID:     adminID.Hex(),
+				PassengerIDs: []string{memberID.Hex()},
+				Scores: map[string]model.Score{
+					adminID.Hex():  {MetresAsDriver: 3000},
+					memberID.Hex(): {MetresAsPassenger: 1000},
+				},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.ExpensesColl).Insert(
+			&model.Expense{
+				ID:             expense1ID,
+				Description:    "Parking",
+				Category:       model.ExpenseParking,
+				Amount:         400,
+				Split:          model.SplitEqually,
+				CarShareID:     carShareID.Hex(),
+				PayerID:        adminID.Hex(),
+				ParticipantIDs: []string{adminID.Hex(), memberID.Hex()},
+				Shares:         map[string]int{adminID.Hex(): 200, memberID.Hex(): 200},
+			},
+		)
+	})
+
+	Describe("get all", func() {
+
+		BeforeEach(func() {
+			request.QueryParams = map[string][]string{"carSharesID": {carShareID.Hex()}}
+		})
+
+		JustBeforeEach(func() {
+			result, err = expenseResource.FindAll(request)
+		})
+
+		It("should return the expenses of the car share", func() {
+			Expect(err).ToNot(HaveOccurred())
+			expenses := result.(*Response).Res.([]model.Expense)
+			Expect(expenses).To(HaveLen(1))
+			Expect(expenses[0].GetID()).To(Equal(expense1ID.Hex()))
+		})
+
+		Context("as a non member", func() {
+
+			BeforeEach(func() {
+				asUser("outsiderFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+		Context("without a car share", func() {
+
+			BeforeEach(func() {
+				request.QueryParams = nil
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should return the expense to a member", func() {
+			result, err = expenseResource.FindOne(expense1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Expense).Amount).To(Equal(400))
+		})
+
+		It("should return a forbidden error to a non member", func() {
+			asUser("outsiderFirebaseUID")
+			result, err = expenseResource.FindOne(expense1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+	})
+
+	Describe("create", func() {
+
+		var (
+			expense         model.Expense
+			expectNotStored func()
+		)
+
+		expectNotStored = func() {
+			expenses, err := expenseResource.ExpenseStorage.GetAll(carShareID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expenses).To(HaveLen(1))
+		}
+
+		BeforeEach(func() {
+			expense = model.Expense{
+				Description: "Fuel",
+				Category:    model.ExpenseFuel,
+				Amount:      1000,
+				CarShareID:  carShareID.Hex(),
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = expenseResource.Create(expense, request)
+		})
+
+		It("should be paid by the requesting member and shared equally between every member", func() {
+			Expect(err).ToNot(HaveOccurred())
+			created := result.(*Response).Res.(model.Expense)
+			Expect(created.PayerID).To(Equal(memberID.Hex()))
+			Expect(created.Split).To(Equal(model.SplitEqually))
+			Expect(created.Shares).To(Equal(map[string]int{adminID.Hex(): 500, memberID.Hex(): 500}))
+			_, err = expenseResource.ExpenseStorage.GetOne(created.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		Context("split by distance", func() {
+
+			BeforeEach(func() {
+				expense.Split = model.SplitByDistance
+			})
+
+			It("should share in proportion to the distance each member has travelled", func() {
+				Expect(err).ToNot(HaveOccurred())
+				created := result.(*Response).Res.(model.Expense)
+				Expect(created.Shares).To(Equal(map[string]int{adminID.Hex(): 750, memberID.Hex(): 250}))
+			})
+
+		})
+
+		Context("for a trip", func() {
+
+			BeforeEach(func() {
+				expense.TripID = tripID.Hex()
+			})
+
+			It("should be shared between everyone in the car", func() {
+				Expect(err).ToNot(HaveOccurred())
+				created := result.(*Response).Res.(model.Expense)
+				Expect(created.ParticipantIDs).To(ConsistOf(adminID.Hex(), memberID.Hex()))
+			})
+
+		})
+
+		Context("for a trip that does not exist", func() {
+
+			BeforeEach(func() {
+				expense.TripID = bson.NewObjectId().Hex()
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expectNotStored()
+			})
+
+		})
+
+		Context("without an amount", func() {
+
+			BeforeEach(func() {
+				expense.Amount = 0
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expectNotStored()
+			})
+
+		})
+
+		Context("paid by a non member", func() {
+
+			BeforeEach(func() {
+				expense.PayerID = outsiderID.Hex()
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expectNotStored()
+			})
+
+		})
+
+		Context("in an unknown category", func() {
+
+			BeforeEach(func() {
+				expense.Category = model.ExpenseCategory("snacks")
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expectNotStored()
+			})
+
+		})
+
+		Context("with an unknown split", func() {
+
+			BeforeEach(func() {
+				expense.Split = model.SplitMethod("by weight")
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expectNotStored()
+			})
+
+		})
+
+		Context("shared with a non member", func() {
+
+			BeforeEach(func() {
+				expense.ParticipantIDs = []string{memberID.Hex(), outsiderID.Hex()}
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expectNotStored()
+			})
+
+		})
+
+		Context("as a non member", func() {
+
+			BeforeEach(func() {
+				asUser("outsiderFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+	})
+
+	Describe("update", func() {
+
+		var expense model.Expense
+
+		BeforeEach(func() {
+			expense, err = expenseResource.ExpenseStorage.GetOne(expense1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			expense.Amount = 600
+		})
+
+		JustBeforeEach(func() {
+			result, err = expenseResource.Update(expense, request)
+		})
+
+		It("should return a forbidden error to a member who didn't pay it", func() {
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		Context("as the payer", func() {
+
+			BeforeEach(func() {
+				asUser("adminFirebaseUID")
+			})
+
+			It("should share the new amount", func() {
+				Expect(err).ToNot(HaveOccurred())
+				stored, err := expenseResource.ExpenseStorage.GetOne(expense1ID.Hex(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Shares).To(Equal(map[string]int{adminID.Hex(): 300, memberID.Hex(): 300}))
+			})
+
+		})
+
+	})
+
+	Describe("delete", func() {
+
+		It("should return a forbidden error to a member who didn't pay it", func() {
+			result, err = expenseResource.Delete(expense1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		It("should delete the expense for an admin", func() {
+			asUser("adminFirebaseUID")
+			result, err = expenseResource.Delete(expense1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = expenseResource.ExpenseStorage.GetOne(expense1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/export"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CarShareExportResource streams complete car share archives to car share members
+type CarShareExportResource struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	carShareExportDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "carshare_export_duration_seconds",
+		Help: "Time taken to export car shares",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(carShareExportDurationSeconds)
+
+}
+
+// Export writes an archive of the car share in the format given by the "format" query
+// parameter (json or csv, defaulting to json)
+func (e CarShareExportResource) Export(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		carShareExportDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	formatName := ""
+	if len(r.QueryParams["format"]) > 0 {
+		formatName = r.QueryParams["format"][0]
+	}
+	format, err := export.ParseFormat(formatName)
+	if err != nil {
+		code = http.StatusBadRequest
+		writeHTTPError(w, err, fmt.Sprintf("unknown export format \"%s\", expected json or csv", formatName), code)
+		return
+	}
+
+	carShare, err := e.CarShareStorage.GetOne(ID, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		writeHTTPError(w, fmt.Errorf("unable to find car share %s", ID), http.StatusText(code), code)
+		return
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving car share %s", ID)
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+		return
+	}
+
+	exporter := export.CarShareExporter{
+		TripStorage: e.TripStorage,
+		UserStorage: e.UserStorage,
+	}
+
+	archive, err := exporter.Prepare(carShare, format, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while exporting car share %s", carShare.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"carshare-%s.%s\"", carShare.GetID(), format))
+	w.WriteHeader(http.StatusOK)
+
+	// the status has already been sent, so all that can be done is stop writing
+	code = http.StatusOK
+	err = archive.Write(w, r.Context)
+	if err != nil {
+		log.Errorf("Error occurred while exporting car share %s, %s", carShare.GetID(), err)
+		return
+	}
+}
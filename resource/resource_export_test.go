@@ -0,0 +1,149 @@
+package resource
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Car Share Export Resource", func() {
+
+	var (
+		exportResource *CarShareExportResource
+		request        api2go.Request
+		context        *api2go.APIContext
+		mockVerifier   mockTokenVerifier
+		recorder       *httptest.ResponseRecorder
+		memberID       = bson.NewObjectId()
+		outsiderID     = bson.NewObjectId()
+		carShareID     = bson.NewObjectId()
+		tripID         = bson.NewObjectId()
+	)
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		mockVerifier.Claims.Set("sub", "memberFirebaseUID")
+		exportResource = &CarShareExportResource{
+			CarShareStorage: &mongodb.CarShareStorage{},
+			TripStorage:     &mongodb.TripStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		recorder = httptest.NewRecorder()
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{
+			Context:     context,
+			QueryParams: map[string][]string{},
+		}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID", DisplayName: "Member"},
+			&model.User{ID: outsiderID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				Name:      "Commute",
+				MemberIDs: []string{memberID.Hex()},
+				AdminIDs:  []string{memberID.Hex()},
+				TripIDs:   []string{tripID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).Insert(
+			&model.Trip{
+				ID:         tripID,
+				Metres:     1000,
+				TimeStamp:  time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC),
+				CarShareID: carShareID.Hex(),
+				DriverID:   memberID.Hex(),
+				Scores:     map[string]model.Score{memberID.Hex(): {MetresAsDriver: 1000}},
+			},
+		)
+	})
+
+	Describe("export", func() {
+
+		It("should write a {json:api} archive by default", func() {
+			exportResource.Export(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/vnd.api+json"))
+			Expect(recorder.Header().Get("Content-Disposition")).To(Equal("attachment; filename=\"carshare-" + carShareID.Hex() + ".json\""))
+
+			var document struct {
+				Data struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+				Included []struct {
+					Type string `json:"type"`
+				} `json:"included"`
+			}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+			Expect(document.Data.Type).To(Equal("carShares"))
+			Expect(document.Data.ID).To(Equal(carShareID.Hex()))
+			Expect(document.Included).To(HaveLen(2))
+		})
+
+		It("should write a csv archive when asked", func() {
+			request.QueryParams["format"] = []string{"csv"}
+			exportResource.Export(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusOK))
+			Expect(recorder.Header().Get("Content-Type")).To(HavePrefix("text/csv"))
+			Expect(recorder.Header().Get("Content-Disposition")).To(Equal("attachment; filename=\"carshare-" + carShareID.Hex() + ".csv\""))
+
+			rows, err := csv.NewReader(recorder.Body).ReadAll()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rows).To(HaveLen(2))
+			Expect(rows[1][0]).To(Equal(tripID.Hex()))
+			Expect(rows[1][3]).To(Equal("Member"))
+		})
+
+		It("should reject an unknown format", func() {
+			request.QueryParams["format"] = []string{"xml"}
+			exportResource.Export(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should forbid users outside the car share", func() {
+			mockVerifier.Claims.Set("sub", "outsiderFirebaseUID")
+			exportResource.Export(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+		It("should not find a car share that does not exist", func() {
+			exportResource.Export(bson.NewObjectId().Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should report an archive that cannot be prepared before sending anything", func() {
+			db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).UpdateId(carShareID, bson.M{"$push": bson.M{"members": bson.NewObjectId().Hex()}})
+			exportResource.Export(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusInternalServerError))
+			Expect(recorder.Header().Get("Content-Type")).To(Equal("application/vnd.api+json"))
+			Expect(recorder.Header().Get("Content-Disposition")).To(BeEmpty())
+
+			var document api2go.HTTPError
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+			Expect(document.Errors).To(HaveLen(1))
+			Expect(document.Errors[0].Status).To(Equal("500"))
+		})
+
+	})
+
+})
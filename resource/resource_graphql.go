@@ -0,0 +1,96 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/graph"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// GraphQLResource answers GraphQL queries over the car shares, trips and users the requesting
+// user can see
+type GraphQLResource struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	graphQLQueryDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "graphql_query_duration_seconds",
+		Help: "Time taken to answer graphql queries",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(graphQLQueryDurationSeconds)
+
+}
+
+// Query answers a GraphQL query, posted as JSON or given in the query, variables and
+// operationName parameters of a GET. As with other GraphQL servers, problems resolving the
+// query are reported in the errors of the response rather than by its status.
+func (g GraphQLResource) Query(w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		graphQLQueryDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, g.TokenVerifier, g.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	params := graph.Params{
+		Query:         r.PlainRequest.URL.Query().Get("query"),
+		OperationName: r.PlainRequest.URL.Query().Get("operationName"),
+	}
+	if variables := r.PlainRequest.URL.Query().Get("variables"); variables != "" {
+		err = json.Unmarshal([]byte(variables), &params.Variables)
+	}
+	if r.PlainRequest.Method == http.MethodPost {
+		err = json.NewDecoder(r.PlainRequest.Body).Decode(&params)
+	}
+	if err != nil {
+		code = http.StatusBadRequest
+		writeHTTPError(w, err, "Error occurred while reading graphql request", code)
+		return
+	}
+	if params.Query == "" {
+		code = http.StatusBadRequest
+		writeHTTPError(w, fmt.Errorf("user %s gave no query", requestingUser.GetID()), "a query must be given", code)
+		return
+	}
+
+	executor := graph.Executor{
+		CarShareStorage: g.CarShareStorage,
+		TripStorage:     g.TripStorage,
+		UserStorage:     g.UserStorage,
+	}
+	result := executor.Do(requestingUser, params, r.Context)
+
+	code = http.StatusOK
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(result)
+}
@@ -0,0 +1,167 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/push"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/webhook"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const guestParam = "name"
+
+// GuestResource lets car share admins turn a guest passenger into a member
+type GuestResource struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Webhooks        *webhook.Notifier
+	Push            *push.Pusher
+	Events          *events.Bus
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	guestPromoteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "guest_promote_duration_seconds",
+		Help: "Time taken to promote guests",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(guestPromoteDurationSeconds)
+
+}
+
+// Promote the guest named by the "name" query parameter to a user linked to the car share.
+// The new user takes the guest's place as a passenger on every trip they were on, and the
+// scores of the car share are rebuilt to include them.
+func (g GuestResource) Promote(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		guestPromoteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, g.TokenVerifier, g.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	carShare, httpErr, code := findCarShare(g.CarShareStorage, ID, r.Context)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("Non admin user %v attempting to promote a guest of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+		return
+	}
+
+	name := ""
+	if len(r.QueryParams[guestParam]) > 0 {
+		name = strings.TrimSpace(r.QueryParams[guestParam][0])
+	}
+	if name == "" {
+		code = http.StatusBadRequest
+		writeHTTPError(w, fmt.Errorf("no guest named to promote in car share %s", carShare.GetID()), "must provide the name of the guest", code)
+		return
+	}
+
+	trips := []model.Trip{}
+	err = g.TripStorage.Iterate(carShare.GetID(), func(trip model.Trip) error {
+		if trip.HasGuest(name) {
+			trips = append(trips, trip)
+		}
+		return nil
+	}, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while retrieving trips for car share %s", carShare.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+	if len(trips) == 0 {
+		code = http.StatusNotFound
+		writeHTTPError(w, fmt.Errorf("no trips in car share %s with guest %s", carShare.GetID(), name), "guest not found", code)
+		return
+	}
+
+	// the user is named as the guest was on their latest trip
+	for _, guest := range trips[len(trips)-1].Guests {
+		if guest.Is(name) {
+			name = guest.Name
+		}
+	}
+
+	user := model.User{
+		DisplayName:      name,
+		LinkedCarShareID: carShare.GetID(),
+	}
+	userID, err := g.UserStorage.Insert(user, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while creating user for guest %s", name)
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+	user.SetID(userID)
+
+	carShare.MemberIDs = append(carShare.MemberIDs, userID)
+	err = g.CarShareStorage.Update(carShare, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while adding user %s to car share %s", userID, carShare.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+	notify(g.Webhooks, carShare.GetID(), model.MemberAdded, user, r.Context)
+	pushEvent(g.Push, carShare.GetID(), model.MemberAdded, user, requestingUser.GetID(), r.Context)
+
+	for _, trip := range trips {
+		trip.PromoteGuest(name, userID)
+		err = g.TripStorage.Update(trip, r.Context)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error occurred while replacing guest %s on trip %s", name, trip.GetID())
+			code = http.StatusInternalServerError
+			writeHTTPError(w, err, errMsg, code)
+			return
+		}
+	}
+
+	err = ledger.Rebuild(carShare, g.TripStorage, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Guest %s promoted but error occurred while rebuilding scores for car share %s", name, carShare.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	publish(g.Events, carShare.GetID(), events.Updated, carShare)
+
+	code = http.StatusCreated
+	writeResource(w, user, code)
+}
@@ -0,0 +1,161 @@
+package resource
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Guest Resource", func() {
+
+	var (
+		guestResource *GuestResource
+		request       api2go.Request
+		context       *api2go.APIContext
+		mockVerifier  mockTokenVerifier
+		recorder      *httptest.ResponseRecorder
+		adminID       = bson.NewObjectId()
+		memberID      = bson.NewObjectId()
+		carShareID    = bson.NewObjectId()
+		trip1ID       = bson.NewObjectId()
+		trip2ID       = bson.NewObjectId()
+	)
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		mockVerifier.Claims.Set("sub", "adminFirebaseUID")
+		guestResource = &GuestResource{
+			CarShareStorage: &mongodb.CarShareStorage{},
+			TripStorage:     &mongodb.TripStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		recorder = httptest.NewRecorder()
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{
+			Context:     context,
+			QueryParams: map[string][]string{"name": {"visitor"}},
+		}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), memberID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).Insert(
+			&model.Trip{
+				ID:         trip1ID,
+				Metres:     1000,
+				TimeStamp:  time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC),
+				CarShareID: carShareID.Hex(),
+				DriverID:   adminID.Hex(),
+				Guests:     []model.Guest{{Name: "Visitor"}},
+				Scores:     map[string]model.Score{},
+			},
+			&model.Trip{
+				ID:         trip2ID,
+				Metres:     2000,
+				TimeStamp:  time.Date(2017, 11, 7, 8, 0, 0, 0, time.UTC),
+				CarShareID: carShareID.Hex(),
+				DriverID:   memberID.Hex(),
+				Scores:     map[string]model.Score{},
+			},
+		)
+	})
+
+	Describe("promote", func() {
+
+		It("should replace the guest with a user linked to the car share", func() {
+			guestResource.Promote(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			var document struct {
+				Data struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+			userID := document.Data.ID
+			user, err := guestResource.UserStorage.GetOne(userID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.DisplayName).To(Equal("Visitor"))
+			Expect(user.LinkedCarShareID).To(Equal(carShareID.Hex()))
+
+			carShare, err := guestResource.CarShareStorage.GetOne(carShareID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.MemberIDs).To(ContainElement(userID))
+
+			trip, err := guestResource.TripStorage.GetOne(trip1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.Guests).To(BeEmpty())
+			Expect(trip.PassengerIDs).To(Equal([]string{userID}))
+			Expect(trip.Scores[userID].MetresAsPassenger).To(Equal(1000))
+
+			latest, err := guestResource.TripStorage.GetOne(trip2ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(latest.Scores[userID].MetresAsPassenger).To(Equal(1000))
+		})
+
+		It("should keep the passengers of the trip in order", func() {
+			// created after the user the guest becomes, so their id sorts after it
+			passengerID := bson.NewObjectIdWithTime(time.Now().Add(24 * time.Hour))
+			db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).UpdateId(carShareID, bson.M{"$push": bson.M{"members": passengerID.Hex()}})
+			db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).UpdateId(trip1ID, bson.M{"$set": bson.M{"passengers": []string{passengerID.Hex()}}})
+
+			guestResource.Promote(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusCreated))
+
+			var document struct {
+				Data struct {
+					ID string `json:"id"`
+				} `json:"data"`
+			}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &document)).To(Succeed())
+
+			trip, err := guestResource.TripStorage.GetOne(trip1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.PassengerIDs).To(Equal([]string{document.Data.ID, passengerID.Hex()}))
+		})
+
+		It("should not find a guest who wasn't on any trips", func() {
+			request.QueryParams["name"] = []string{"stranger"}
+			guestResource.Promote(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusNotFound))
+		})
+
+		It("should require the name of the guest", func() {
+			request.QueryParams = nil
+			guestResource.Promote(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should only let admins promote guests", func() {
+			mockVerifier.Claims.Set("sub", "memberFirebaseUID")
+			guestResource.Promote(carShareID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusForbidden))
+		})
+
+	})
+
+})
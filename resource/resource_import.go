@@ -0,0 +1,134 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/importer"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// TripImportResource lets car share admins bring in historical trips from a CSV file
+type TripImportResource struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	tripImportDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "trip_import_duration_seconds",
+		Help: "Time taken to import trips",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(tripImportDurationSeconds)
+
+}
+
+// Import reads a CSV file of trips from the request body into the car share. Unknown
+// names are created as users linked to the car share if the "createUsers" query
+// parameter is true, otherwise they are reported as errors. Nothing is imported
+// unless every row is valid.
+func (t TripImportResource) Import(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		tripImportDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, t.TokenVerifier, t.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	carShare, err := t.CarShareStorage.GetOne(ID, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		writeHTTPError(w, fmt.Errorf("unable to find car share %s", ID), http.StatusText(code), code)
+		return
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving car share %s", ID)
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("Non admin user %v attempting to import trips into car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+		return
+	}
+
+	createUsers := false
+	if len(r.QueryParams["createUsers"]) > 0 {
+		createUsers, _ = strconv.ParseBool(r.QueryParams["createUsers"][0])
+	}
+
+	tripImporter := importer.TripImporter{
+		CarShareStorage: t.CarShareStorage,
+		TripStorage:     t.TripStorage,
+		UserStorage:     t.UserStorage,
+		CreateUsers:     createUsers,
+	}
+
+	result, err := tripImporter.Import(carShare, r.PlainRequest.Body, r.Context)
+	switch err {
+	case nil:
+		break
+	case importer.ErrInvalidRows:
+		code = http.StatusUnprocessableEntity
+		httpErr := api2go.HTTPError{}
+		for _, rowErr := range result.Errors {
+			httpErr.Errors = append(httpErr.Errors, api2go.Error{
+				Status: strconv.Itoa(code),
+				Title:  "invalid row",
+				Detail: rowErr.Err.Error(),
+				Meta:   map[string]interface{}{"row": rowErr.Row},
+			})
+		}
+		log.Errorf("Unable to import trips into car share %s, %d invalid rows", carShare.GetID(), len(result.Errors))
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(httpErr)
+		return
+	default:
+		errMsg := fmt.Sprintf("Error occurred while importing trips into car share %s, %d trips imported", carShare.GetID(), len(result.Imported))
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	code = http.StatusCreated
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"meta": map[string]interface{}{
+			"imported-trips": result.Imported,
+			"created-users":  result.CreatedUsers,
+		},
+	})
+}
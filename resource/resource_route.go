@@ -0,0 +1,387 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RouteResource for api2go routes. Routes can be used by any member of their car share
+// but only managed by its admins.
+type RouteResource struct {
+	RouteStorage    storage.RouteStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Events          *events.Bus
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	routeFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "route_find_all_duration_seconds",
+		Help: "Time taken to find all routes",
+	}, []string{"code"})
+	routeFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "route_find_one_duration_seconds",
+		Help: "Time taken to find one route",
+	}, []string{"code"})
+	routeCreateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "route_create_duration_seconds",
+		Help: "Time taken to create routes",
+	}, []string{"code"})
+	routeDeleteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "route_delete_duration_seconds",
+		Help: "Time taken to delete routes",
+	}, []string{"code"})
+	routeUpdateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "route_update_duration_seconds",
+		Help: "Time taken to update routes",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(routeFindAllDurationSeconds)
+	prometheus.MustRegister(routeFindOneDurationSeconds)
+	prometheus.MustRegister(routeCreateDurationSeconds)
+	prometheus.MustRegister(routeDeleteDurationSeconds)
+	prometheus.MustRegister(routeUpdateDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Routes are only listed for a car share,
+// through /carShares/:id/routes
+func (rs RouteResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		routeFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, rs.TokenVerifier, rs.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all routes not supported"),
+			"routes must be found through their car share",
+			code,
+		)
+	}
+	carShareID := r.QueryParams["carSharesID"][0]
+
+	carShare, httpErr, code := findCarShare(rs.CarShareStorage, carShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+	}
+
+	result, err := rs.RouteStorage.GetAll(carShareID, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving routes for car share %s", carShareID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (rs RouteResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		routeFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, rs.TokenVerifier, rs.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	route, httpErr, code := rs.route(ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(rs.CarShareStorage, route.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to access route %s for car share %s they are not a member of", requestingUser.GetID(), route.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to access",
+			code,
+		)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: route, Code: code}, nil
+}
+
+// Create to satisfy api2go.CRUD interface
+func (rs RouteResource) Create(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		routeCreateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, rs.TokenVerifier, rs.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	route, ok := obj.(model.Route)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to route create: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	if route.CarShareID == "" {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to route create (missing carShareID): %v", obj),
+			"must provide a carShareID",
+			code,
+		)
+	}
+
+	carShare, httpErr, code := findCarShare(rs.CarShareStorage, route.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to create route for car share %v", requestingUser.GetID(), carShare.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	httpErr, code = verifyRoute(route, carShare)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	id, err := rs.RouteStorage.Insert(route, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while persisting route"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	route.SetID(id)
+	publish(rs.Events, route.CarShareID, events.Created, route)
+
+	code = http.StatusCreated
+	return &Response{Res: route, Code: code}, nil
+}
+
+// Delete to satisfy the api2go.CRUD interface
+func (rs RouteResource) Delete(id string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		routeDeleteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, rs.TokenVerifier, rs.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	route, httpErr, code := rs.route(id, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(rs.CarShareStorage, route.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to delete route %v", requestingUser.GetID(), route.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	// trips keep their reference to the route, as a record of where they came from
+	err = rs.RouteStorage.Delete(id, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find route %s to delete", id), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting route %s", id)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(rs.Events, route.CarShareID, events.Deleted, route)
+
+	code = http.StatusOK
+	return &Response{Code: code}, nil
+}
+
+// Update to satisfy api2go.CRUD interface
+func (rs RouteResource) Update(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		routeUpdateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, rs.TokenVerifier, rs.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	route, ok := obj.(model.Route)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to route update: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	routeInDataStore, httpErr, code := rs.route(route.GetID(), r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// Prevent routes from being re-assigned car shares
+	if routeInDataStore.CarShareID != route.CarShareID {
+		errMsg := fmt.Sprintf("route %s already belongs to another car share", route.GetID())
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	carShare, httpErr, code := findCarShare(rs.CarShareStorage, route.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to update route %v", requestingUser.GetID(), route.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	httpErr, code = verifyRoute(route, carShare)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	err = rs.RouteStorage.Update(route, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Unable to find route %s to update", route.GetID()), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while updating route %s", route.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(rs.Events, route.CarShareID, events.Updated, route)
+
+	code = http.StatusNoContent
+	return &Response{Res: route, Code: code}, nil
+}
+
+// route retrieves a route, translating storage errors into http errors
+func (rs RouteResource) route(ID string, ctx api2go.APIContexter) (route model.Route, httpErr error, code int) {
+
+	route, err := rs.RouteStorage.GetOne(ID, ctx)
+	switch err {
+	case nil:
+		return route, nil, code
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return route, api2go.NewHTTPError(fmt.Errorf("unable to find route %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving route %s", ID)
+		code = http.StatusInternalServerError
+		return route, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
+
+// verifyRoute checks a route has a name and distance, and that its default driver and
+// passengers are members of the car share
+func verifyRoute(route model.Route, carShare model.CarShare) (httpErr error, code int) {
+
+	badRequest := func(err error) (error, int) {
+		code = http.StatusBadRequest
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	if route.Name == "" {
+		return badRequest(fmt.Errorf("route must have a name"))
+	}
+
+	if route.Metres <= 0 {
+		return badRequest(fmt.Errorf("route must have a distance in metres"))
+	}
+
+	return verifyMembers(route.DriverID, route.PassengerIDs, carShare)
+}
@@ -0,0 +1,283 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Route Resource", func() {
+
+	var (
+		routeResource   *RouteResource
+		request         api2go.Request
+		context         *api2go.APIContext
+		mockVerifier    mockTokenVerifier
+		adminID         = bson.NewObjectId()
+		memberID        = bson.NewObjectId()
+		outsiderID      = bson.NewObjectId()
+		carShareID      = bson.NewObjectId()
+		route1ID        = bson.NewObjectId()
+		result          api2go.Responder
+		err             error
+		asUser          func(firebaseUID string)
+		expectHTTPError func(code int)
+	)
+
+	asUser = func(firebaseUID string) {
+		mockVerifier.Claims.Set("sub", firebaseUID)
+	}
+
+	expectHTTPError = func(code int) {
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+		Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d) ", code)))
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		asUser("adminFirebaseUID")
+		routeResource = &RouteResource{
+			RouteStorage:    &mongodb.RouteStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+			&model.User{ID: outsiderID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), memberID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.RoutesColl).Insert(
+			&model.Route{
+				ID:         route1ID,
+				Name:       "Commute",
+				Metres:     15000,
+				CarShareID: carShareID.Hex(),
+				DriverID:   adminID.Hex(),
+			},
+		)
+	})
+
+	Describe("get all", func() {
+
+		BeforeEach(func() {
+			request.QueryParams = map[string][]string{"carSharesID": {carShareID.Hex()}}
+		})
+
+		JustBeforeEach(func() {
+			result, err = routeResource.FindAll(request)
+		})
+
+		It("should return the routes of the car share", func() {
+			Expect(err).ToNot(HaveOccurred())
+			routes := result.(*Response).Res.([]model.Route)
+			Expect(routes).To(HaveLen(1))
+			Expect(routes[0].GetID()).To(Equal(route1ID.Hex()))
+		})
+
+		Context("as a non member", func() {
+
+			BeforeEach(func() {
+				asUser("outsiderFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+		Context("without a car share", func() {
+
+			BeforeEach(func() {
+				request.QueryParams = nil
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should return the route to a member", func() {
+			asUser("memberFirebaseUID")
+			result, err = routeResource.FindOne(route1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Route).Name).To(Equal("Commute"))
+		})
+
+		It("should not return the route to a non member", func() {
+			asUser("outsiderFirebaseUID")
+			result, err = routeResource.FindOne(route1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		It("should return not found for a route that does not exist", func() {
+			result, err = routeResource.FindOne(bson.NewObjectId().Hex(), request)
+			expectHTTPError(http.StatusNotFound)
+		})
+
+	})
+
+	Describe("create", func() {
+
+		var route model.Route
+
+		BeforeEach(func() {
+			route = model.Route{
+				Name:         "School run",
+				Metres:       4000,
+				CarShareID:   carShareID.Hex(),
+				DriverID:     memberID.Hex(),
+				PassengerIDs: []string{adminID.Hex()},
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = routeResource.Create(route, request)
+		})
+
+		It("should store the route", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Code).To(Equal(http.StatusCreated))
+			stored, err := routeResource.RouteStorage.GetOne(result.(*Response).Res.(model.Route).GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Name).To(Equal("School run"))
+		})
+
+		Context("as a non admin member", func() {
+
+			BeforeEach(func() {
+				asUser("memberFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+		Context("without a name", func() {
+
+			BeforeEach(func() {
+				route.Name = ""
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				Expect(err.Error()).To(HaveSuffix(", route must have a name"))
+			})
+
+		})
+
+		Context("without a distance", func() {
+
+			BeforeEach(func() {
+				route.Metres = 0
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				Expect(err.Error()).To(HaveSuffix(", route must have a distance in metres"))
+			})
+
+		})
+
+		Context("with a passenger who isn't a member", func() {
+
+			BeforeEach(func() {
+				route.PassengerIDs = []string{outsiderID.Hex()}
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+				expected := fmt.Sprintf(", passenger %s is not a member of car share %s", outsiderID.Hex(), carShareID.Hex())
+				Expect(err.Error()).To(HaveSuffix(expected))
+			})
+
+		})
+
+	})
+
+	Describe("update", func() {
+
+		var route model.Route
+
+		BeforeEach(func() {
+			route, err = routeResource.RouteStorage.GetOne(route1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			route.Metres = 15500
+		})
+
+		JustBeforeEach(func() {
+			result, err = routeResource.Update(route, request)
+		})
+
+		It("should update the route in the data store", func() {
+			Expect(err).ToNot(HaveOccurred())
+			stored, err := routeResource.RouteStorage.GetOne(route1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Metres).To(Equal(15500))
+		})
+
+		Context("moving the route to another car share", func() {
+
+			BeforeEach(func() {
+				route.CarShareID = bson.NewObjectId().Hex()
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+	})
+
+	Describe("delete", func() {
+
+		It("should delete the route", func() {
+			result, err = routeResource.Delete(route1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = routeResource.RouteStorage.GetOne(route1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should not let a non admin delete the route", func() {
+			asUser("memberFirebaseUID")
+			result, err = routeResource.Delete(route1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+	})
+
+})
@@ -0,0 +1,422 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ScheduleResource for api2go routes. Schedules can be seen by any member of their car
+// share but only managed by its admins.
+type ScheduleResource struct {
+	ScheduleStorage storage.ScheduleStorage
+	CarShareStorage storage.CarShareStorage
+	RouteStorage    storage.RouteStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Events          *events.Bus
+	Clock           clock.Clock
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	scheduleFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "schedule_find_all_duration_seconds",
+		Help: "Time taken to find all schedules",
+	}, []string{"code"})
+	scheduleFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "schedule_find_one_duration_seconds",
+		Help: "Time taken to find one schedule",
+	}, []string{"code"})
+	scheduleCreateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "schedule_create_duration_seconds",
+		Help: "Time taken to create schedules",
+	}, []string{"code"})
+	scheduleDeleteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "schedule_delete_duration_seconds",
+		Help: "Time taken to delete schedules",
+	}, []string{"code"})
+	scheduleUpdateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "schedule_update_duration_seconds",
+		Help: "Time taken to update schedules",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(scheduleFindAllDurationSeconds)
+	prometheus.MustRegister(scheduleFindOneDurationSeconds)
+	prometheus.MustRegister(scheduleCreateDurationSeconds)
+	prometheus.MustRegister(scheduleDeleteDurationSeconds)
+	prometheus.MustRegister(scheduleUpdateDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Schedules are only listed for a car share,
+// through /carShares/:id/schedules
+func (ss ScheduleResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		scheduleFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ss.TokenVerifier, ss.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all schedules not supported"),
+			"schedules must be found through their car share",
+			code,
+		)
+	}
+	carShareID := r.QueryParams["carSharesID"][0]
+
+	carShare, httpErr, code := findCarShare(ss.CarShareStorage, carShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+	}
+
+	result, err := ss.ScheduleStorage.GetAll(carShareID, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving schedules for car share %s", carShareID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (ss ScheduleResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		scheduleFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ss.TokenVerifier, ss.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	schedule, httpErr, code := ss.schedule(ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(ss.CarShareStorage, schedule.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to access schedule %s for car share %s they are not a member of", requestingUser.GetID(), schedule.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to access",
+			code,
+		)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: schedule, Code: code}, nil
+}
+
+// Create to satisfy api2go.CRUD interface
+func (ss ScheduleResource) Create(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		scheduleCreateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ss.TokenVerifier, ss.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	schedule, ok := obj.(model.Schedule)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to schedule create: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	if schedule.CarShareID == "" {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to schedule create (missing carShareID): %v", obj),
+			"must provide a carShareID",
+			code,
+		)
+	}
+
+	carShare, httpErr, code := findCarShare(ss.CarShareStorage, schedule.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to create schedule for car share %v", requestingUser.GetID(), carShare.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	httpErr, code = ss.prepare(&schedule, carShare, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	id, err := ss.ScheduleStorage.Insert(schedule, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while persisting schedule"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	schedule.SetID(id)
+	publish(ss.Events, schedule.CarShareID, events.Created, schedule)
+
+	code = http.StatusCreated
+	return &Response{Res: schedule, Code: code}, nil
+}
+
+// Delete to satisfy the api2go.CRUD interface. Draft trips already created from the
+// schedule are kept.
+func (ss ScheduleResource) Delete(id string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		scheduleDeleteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ss.TokenVerifier, ss.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	schedule, httpErr, code := ss.schedule(id, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(ss.CarShareStorage, schedule.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to delete schedule %v", requestingUser.GetID(), schedule.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	err = ss.ScheduleStorage.Delete(id, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find schedule %s to delete", id), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting schedule %s", id)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(ss.Events, schedule.CarShareID, events.Deleted, schedule)
+
+	code = http.StatusOK
+	return &Response{Code: code}, nil
+}
+
+// Update to satisfy api2go.CRUD interface
+func (ss ScheduleResource) Update(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		scheduleUpdateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ss.TokenVerifier, ss.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	schedule, ok := obj.(model.Schedule)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to schedule update: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	scheduleInDataStore, httpErr, code := ss.schedule(schedule.GetID(), r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// Prevent schedules from being re-assigned car shares
+	if scheduleInDataStore.CarShareID != schedule.CarShareID {
+		errMsg := fmt.Sprintf("schedule %s already belongs to another car share", schedule.GetID())
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	carShare, httpErr, code := findCarShare(ss.CarShareStorage, schedule.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to update schedule %v", requestingUser.GetID(), schedule.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	httpErr, code = ss.prepare(&schedule, carShare, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	err = ss.ScheduleStorage.Update(schedule, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Unable to find schedule %s to update", schedule.GetID()), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while updating schedule %s", schedule.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(ss.Events, schedule.CarShareID, events.Updated, schedule)
+
+	code = http.StatusNoContent
+	return &Response{Res: schedule, Code: code}, nil
+}
+
+// schedule retrieves a schedule, translating storage errors into http errors
+func (ss ScheduleResource) schedule(ID string, ctx api2go.APIContexter) (schedule model.Schedule, httpErr error, code int) {
+
+	schedule, err := ss.ScheduleStorage.GetOne(ID, ctx)
+	switch err {
+	case nil:
+		return schedule, nil, code
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return schedule, api2go.NewHTTPError(fmt.Errorf("unable to find schedule %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving schedule %s", ID)
+		code = http.StatusInternalServerError
+		return schedule, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
+
+// prepare a schedule for storage. Details that haven't been given are filled in from its
+// route, the schedule is validated and its next occurrence worked out from now.
+func (ss ScheduleResource) prepare(schedule *model.Schedule, carShare model.CarShare, ctx api2go.APIContexter) (httpErr error, code int) {
+
+	badRequest := func(err error) (error, int) {
+		code = http.StatusBadRequest
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	if schedule.RouteID != "" {
+		route, err := ss.RouteStorage.GetOne(schedule.RouteID, ctx)
+		switch err {
+		case nil:
+			break
+		case storage.ErrNotFound, storage.ErrInvalidID:
+			return badRequest(fmt.Errorf("unable to find route %s", schedule.RouteID))
+		default:
+			errMsg := fmt.Sprintf("Error retrieving route %s", schedule.RouteID)
+			code = http.StatusInternalServerError
+			return api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+		}
+		if route.CarShareID != carShare.GetID() {
+			return badRequest(fmt.Errorf("route %s belongs to another car share", route.GetID()))
+		}
+		if schedule.Metres == 0 {
+			schedule.Metres = route.Metres
+		}
+		if schedule.DriverID == "" {
+			schedule.DriverID = route.DriverID
+		}
+		if len(schedule.PassengerIDs) == 0 {
+			schedule.PassengerIDs = append([]string{}, route.PassengerIDs...)
+		}
+	}
+
+	if schedule.Name == "" {
+		return badRequest(fmt.Errorf("schedule must have a name"))
+	}
+
+	if schedule.Metres <= 0 {
+		return badRequest(fmt.Errorf("schedule must have a distance in metres"))
+	}
+
+	next, err := schedule.NextOccurrence(ss.Clock.Now())
+	if err != nil {
+		return badRequest(err)
+	}
+	schedule.Next = next
+
+	return verifyMembers(schedule.DriverID, schedule.PassengerIDs, carShare)
+}
@@ -0,0 +1,208 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Schedule Resource", func() {
+
+	var (
+		scheduleResource *ScheduleResource
+		request          api2go.Request
+		context          *api2go.APIContext
+		mockVerifier     mockTokenVerifier
+		mockClock        *clock.Mock
+		adminID          = bson.NewObjectId()
+		memberID         = bson.NewObjectId()
+		carShareID       = bson.NewObjectId()
+		routeID          = bson.NewObjectId()
+		schedule1ID      = bson.NewObjectId()
+		result           api2go.Responder
+		err              error
+	)
+
+	expectHTTPError := func(code int) {
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+		Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", code)))
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		mockVerifier.Claims.Set("sub", "adminFirebaseUID")
+		mockClock = clock.NewMock()
+		mockClock.Set(time.Date(2017, 11, 5, 12, 0, 0, 0, time.UTC))
+		scheduleResource = &ScheduleResource{
+			ScheduleStorage: &mongodb.ScheduleStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			RouteStorage:    &mongodb.RouteStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+			Clock:           mockClock,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), memberID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.RoutesColl).Insert(
+			&model.Route{
+				ID:           routeID,
+				Name:         "Commute",
+				Metres:       15000,
+				CarShareID:   carShareID.Hex(),
+				DriverID:     adminID.Hex(),
+				PassengerIDs: []string{memberID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.SchedulesColl).Insert(
+			&model.Schedule{
+				ID:         schedule1ID,
+				Name:       "Football",
+				Weekdays:   []string{"saturday"},
+				Time:       "10:00",
+				TimeZone:   "UTC",
+				Metres:     8000,
+				Next:       time.Date(2017, 11, 11, 10, 0, 0, 0, time.UTC),
+				CarShareID: carShareID.Hex(),
+			},
+		)
+	})
+
+	Describe("get one", func() {
+
+		It("should return the schedule to a member", func() {
+			mockVerifier.Claims.Set("sub", "memberFirebaseUID")
+			result, err = scheduleResource.FindOne(schedule1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Schedule).Name).To(Equal("Football"))
+		})
+
+	})
+
+	Describe("create", func() {
+
+		var schedule model.Schedule
+
+		BeforeEach(func() {
+			schedule = model.Schedule{
+				Name:       "Commute",
+				Weekdays:   []string{"Monday", "Wednesday"},
+				Time:       "08:00",
+				TimeZone:   "Europe/London",
+				CarShareID: carShareID.Hex(),
+				RouteID:    routeID.Hex(),
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = scheduleResource.Create(schedule, request)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Code).To(Equal(http.StatusCreated))
+		})
+
+		It("should fill in the details from the route and work out the next occurrence", func() {
+			created := result.(*Response).Res.(model.Schedule)
+			stored, err := scheduleResource.ScheduleStorage.GetOne(created.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Metres).To(Equal(15000))
+			Expect(stored.DriverID).To(Equal(adminID.Hex()))
+			Expect(stored.PassengerIDs).To(Equal([]string{memberID.Hex()}))
+			Expect(stored.Next).To(Equal(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC)))
+		})
+
+		Context("as a non admin member", func() {
+
+			BeforeEach(func() {
+				mockVerifier.Claims.Set("sub", "memberFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+		Context("with an unknown weekday", func() {
+
+			BeforeEach(func() {
+				schedule.Weekdays = []string{"someday"}
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("with an invalid time", func() {
+
+			BeforeEach(func() {
+				schedule.Time = "8am"
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+	})
+
+	Describe("update", func() {
+
+		It("should work out the next occurrence again", func() {
+			schedule, err := scheduleResource.ScheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			schedule.Weekdays = []string{"sunday"}
+			result, err = scheduleResource.Update(schedule, request)
+			Expect(err).ToNot(HaveOccurred())
+			schedule, err = scheduleResource.ScheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedule.Next).To(Equal(time.Date(2017, 11, 12, 10, 0, 0, 0, time.UTC)))
+		})
+
+	})
+
+	Describe("delete", func() {
+
+		It("should delete the schedule", func() {
+			result, err = scheduleResource.Delete(schedule1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = scheduleResource.ScheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// StandingsResource for api2go routes. Standings are worked out from the trips of a car
+// share whenever they are requested, so are read only and identified by their car share.
+type StandingsResource struct {
+	TripStorage     storage.TripStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Clock           clock.Clock
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	standingsFindDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "standings_find_duration_seconds",
+		Help: "Time taken to find standings",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(standingsFindDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. A car share only has the one set of standings,
+// found through /carShares/:id/standings
+func (s StandingsResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code := http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all standings not supported"),
+			"standings must be found through their car share",
+			code,
+		)
+	}
+	return s.FindOne(r.QueryParams["carSharesID"][0], r)
+}
+
+// FindOne to satisfy api2go.CRUD interface. The ID of the standings is that of their car share.
+func (s StandingsResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		standingsFindDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, s.TokenVerifier, s.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	carShare, httpErr, code := findCarShare(s.CarShareStorage, ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to access standings for car share %s they are not a member of", requestingUser.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to access",
+			code,
+		)
+	}
+
+	unit, err := displayUnit(r, carShare)
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, "unit must be km or miles", code)
+	}
+
+	standings, err := ledger.Standings(carShare, s.TripStorage, s.Clock.Now().UTC(), r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while working out standings for car share %s", carShare.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	// members who haven't been on any trips yet have nothing to their name
+	for _, memberID := range carShare.MemberIDs {
+		if _, ok := standings.AllTime[memberID]; !ok {
+			standings.AllTime[memberID] = model.Score{}
+		}
+		if _, ok := standings.Windowed[memberID]; !ok {
+			standings.Windowed[memberID] = model.Score{}
+		}
+	}
+	standings.FormatDistances(unit)
+
+	code = http.StatusOK
+	return &Response{Res: standings, Code: code}, nil
+}
@@ -0,0 +1,131 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Standings Resource", func() {
+
+	var (
+		standingsResource *StandingsResource
+		request           api2go.Request
+		context           *api2go.APIContext
+		mockVerifier      mockTokenVerifier
+		mockClock         *clock.Mock
+		user1ID           = bson.NewObjectId()
+		user2ID           = bson.NewObjectId()
+		user3ID           = bson.NewObjectId()
+		carShareID        = bson.NewObjectId()
+		now               = time.Date(2018, 1, 29, 0, 0, 0, 0, time.UTC)
+		result            api2go.Responder
+		err               error
+	)
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		mockVerifier.Claims.Set("sub", "user1FirebaseUID")
+		mockClock = clock.NewMock()
+		mockClock.Set(now)
+		standingsResource = &StandingsResource{
+			TripStorage:     &mongodb.TripStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+			Clock:           mockClock,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: user1ID, FirebaseUID: "user1FirebaseUID"},
+			&model.User{ID: user2ID, FirebaseUID: "user2FirebaseUID"},
+			&model.User{ID: user3ID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:         carShareID,
+				MemberIDs:  []string{user1ID.Hex(), user2ID.Hex()},
+				WindowDays: 40,
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).Insert(
+			&model.Trip{
+				Metres:     1000,
+				TimeStamp:  now.AddDate(0, 0, -60),
+				CarShareID: carShareID.Hex(),
+				DriverID:   user1ID.Hex(),
+				Scores: map[string]model.Score{
+					user1ID.Hex(): {MetresAsDriver: 1000, PointsAsDriver: 1000},
+				},
+			},
+			&model.Trip{
+				Metres:     2000,
+				TimeStamp:  now.AddDate(0, 0, -30),
+				CarShareID: carShareID.Hex(),
+				DriverID:   user1ID.Hex(),
+				Scores: map[string]model.Score{
+					user1ID.Hex(): {MetresAsDriver: 3000, PointsAsDriver: 3000},
+				},
+			},
+		)
+	})
+
+	Describe("get one", func() {
+
+		It("should return all time and windowed scores for every member", func() {
+			result, err = standingsResource.FindOne(carShareID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			standings := result.(*Response).Res.(model.Standings)
+			Expect(standings.GetID()).To(Equal(carShareID.Hex()))
+			Expect(standings.WindowDays).To(Equal(40))
+			Expect(standings.AllTime[user1ID.Hex()].MetresAsDriver).To(Equal(3000))
+			Expect(standings.Windowed[user1ID.Hex()].MetresAsDriver).To(Equal(2000))
+			Expect(standings.Windowed[user1ID.Hex()].FormattedAsDriver).To(Equal("2.0 km"))
+			Expect(standings.AllTime).To(HaveKey(user2ID.Hex()))
+			Expect(standings.Windowed).To(HaveKey(user2ID.Hex()))
+		})
+
+		It("should return a forbidden error to a non member", func() {
+			mockVerifier.Claims.Set("sub", "outsiderFirebaseUID")
+			result, err = standingsResource.FindOne(carShareID.Hex(), request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", http.StatusForbidden)))
+		})
+
+	})
+
+	Describe("get all", func() {
+
+		It("should find the standings through their car share", func() {
+			request.QueryParams = map[string][]string{"carSharesID": {carShareID.Hex()}}
+			result, err = standingsResource.FindAll(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Standings).GetID()).To(Equal(carShareID.Hex()))
+		})
+
+		It("should return a bad request error without a car share", func() {
+			result, err = standingsResource.FindAll(request)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", http.StatusBadRequest)))
+		})
+
+	})
+
+})
@@ -5,8 +5,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/ledger"
 	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/push"
 	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/webhook"
 	"github.com/LewisWatson/firebase-jwt-auth"
 	"github.com/benbjohnson/clock"
 	"github.com/manyminds/api2go"
@@ -18,8 +22,14 @@ type TripResource struct {
 	TripStorage     storage.TripStorage
 	UserStorage     storage.UserStorage
 	CarShareStorage storage.CarShareStorage
+	RouteStorage    storage.RouteStorage
+	VehicleStorage  storage.VehicleStorage
 	TokenVerifier   fireauth.TokenVerifier
 	Clock           clock.Clock
+	FuelPrices      model.FuelPrices
+	Webhooks        *webhook.Notifier
+	Push            *push.Pusher
+	Events          *events.Bus
 }
 
 var (
@@ -104,8 +114,8 @@ func (t TripResource) FindOne(ID string, r api2go.Request) (api2go.Responder, er
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
 	}
 
-	httpErr, code := t.verifyCarShareMember(requestingUser, trip, r.Context)
-	if httpErr.Errors != nil {
+	_, httpErr, code := t.verifyCarShareMember(requestingUser, trip, r.Context)
+	if httpErr != nil {
 		return &Response{}, httpErr
 	}
 
@@ -128,8 +138,14 @@ func (t TripResource) FindOne(ID string, r api2go.Request) (api2go.Responder, er
 		)
 	}
 
+	unit, err := displayUnit(r, carShare)
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, "unit must be km or miles", code)
+	}
+
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
-	popErr := t.populate(&trip, r.Context)
+	popErr := t.populate(&trip, unit, r.Context)
 	if popErr != nil {
 		errMsg := fmt.Sprintf("Error when populating trip %s", trip.GetID())
 		err = api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -172,11 +188,40 @@ func (t TripResource) Create(obj interface{}, r api2go.Request) (api2go.Responde
 		)
 	}
 
-	httpErr, code := t.verifyCarShareMember(requestingUser, trip, r.Context)
-	if httpErr.Errors != nil {
+	carShare, httpErr, code := t.verifyCarShareMember(requestingUser, trip, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	unit, httpErr, code := t.unitsFor(&trip, r)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if trip.RouteID != "" {
+		httpErr, code = t.followRoute(&trip, r.Context)
+		if httpErr != nil {
+			return &Response{}, httpErr
+		}
+	}
+
+	err = trip.ValidateGuests()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, err.Error(), code)
+	}
+
+	httpErr, code = t.price(&trip, r.Context)
+	if httpErr != nil {
 		return &Response{}, httpErr
 	}
 
+	// passengers confirm they were in the car before the trip counts towards the scores,
+	// other than whoever is recording it
+	trip.ConfirmedBy = nil
+	trip.DisputedBy = nil
+	trip.Confirm(requestingUser.GetID())
+
 	trip.Scores = make(map[string]model.Score)
 
 	// TODO make custom store method to just return the scores
@@ -190,7 +235,7 @@ func (t TripResource) Create(obj interface{}, r api2go.Request) (api2go.Responde
 			code,
 		)
 	}
-	trip.CalculateScores(latestTrip.Scores)
+	trip.CalculateScores(latestTrip.Scores, carShare.Scoring.Strategy())
 
 	trip.TimeStamp = t.Clock.Now().UTC()
 
@@ -211,8 +256,12 @@ func (t TripResource) Create(obj interface{}, r api2go.Request) (api2go.Responde
 		return &Response{}, api2go.NewHTTPError(err, err.Error(), code)
 	}
 
+	notify(t.Webhooks, trip.CarShareID, model.TripCreated, trip, r.Context)
+	pushEvent(t.Push, trip.CarShareID, model.TripCreated, trip, requestingUser.GetID(), r.Context)
+	publish(t.Events, trip.CarShareID, events.Created, trip)
+
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
-	popErr := t.populate(&trip, r.Context)
+	popErr := t.populate(&trip, unit, r.Context)
 	if popErr != nil {
 		errMsg := fmt.Sprintf("Error when populating trip %s", trip.GetID())
 		err = api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -257,8 +306,8 @@ func (t TripResource) Delete(id string, r api2go.Request) (api2go.Responder, err
 		)
 	}
 
-	httpErr, code := t.verifyCarShareMember(requestingUser, trip, r.Context)
-	if httpErr.Errors != nil {
+	_, httpErr, code := t.verifyCarShareMember(requestingUser, trip, r.Context)
+	if httpErr != nil {
 		return &Response{}, httpErr
 	}
 
@@ -301,6 +350,10 @@ func (t TripResource) Delete(id string, r api2go.Request) (api2go.Responder, err
 	}
 	t.CarShareStorage.Update(carShare, r.Context)
 
+	notify(t.Webhooks, trip.CarShareID, model.TripDeleted, trip, r.Context)
+	pushEvent(t.Push, trip.CarShareID, model.TripDeleted, trip, requestingUser.GetID(), r.Context)
+	publish(t.Events, trip.CarShareID, events.Deleted, trip)
+
 	code = http.StatusOK
 	return &Response{Code: code}, nil
 }
@@ -356,14 +409,30 @@ func (t TripResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 		)
 	}
 
+	// trips are confirmed and disputed through their own endpoints
+	trip.Status = tripInDataStore.Status
+	trip.ConfirmedBy = tripInDataStore.ConfirmedBy
+	trip.DisputedBy = tripInDataStore.DisputedBy
+	trip.ScheduleID = tripInDataStore.ScheduleID
+
 	// important to check against the trip in the data store
-	httpErr, code := t.verifyCarShareMember(requestingUser, tripInDataStore, r.Context)
-	if httpErr.Errors != nil {
+	carShare, httpErr, code := t.verifyCarShareMember(requestingUser, tripInDataStore, r.Context)
+	if httpErr != nil {
 		return &Response{}, httpErr
 	}
 
 	httpErr, code = t.addToCarShareTripList(trip, r.Context)
-	if httpErr.Errors != nil {
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// distances presented when the trip was found come back with the update, so they are
+	// only converted if the metres haven't been changed directly and the distance has
+	if trip.Metres != tripInDataStore.Metres || !distanceChanged(trip, tripInDataStore, carShare, r) {
+		trip.Unit = ""
+	}
+	unit, httpErr, code := t.unitsFor(&trip, r)
+	if httpErr != nil {
 		return &Response{}, httpErr
 	}
 
@@ -392,6 +461,31 @@ func (t TripResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 		}
 	}
 
+	// verify guests
+	err = trip.ValidateGuests()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, err.Error(), code)
+	}
+
+	// a change of passengers needs confirming again by any new passengers, other than
+	// whoever is making it
+	if !samePassengers(trip.PassengerIDs, tripInDataStore.PassengerIDs) {
+		confirmedBy := trip.ConfirmedBy
+		trip.ConfirmedBy = nil
+		for _, userID := range confirmedBy {
+			if trip.IsParticipant(userID) {
+				trip.ConfirmedBy = append(trip.ConfirmedBy, userID)
+			}
+		}
+		trip.Confirm(requestingUser.GetID())
+	}
+
+	httpErr, code = t.price(&trip, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
 	// TODO recalculate scores for trips that occur after this one as well
 	latestTrip, err := t.TripStorage.GetLatest(trip.CarShareID, r.Context)
 	if err != nil && err != storage.ErrNotFound {
@@ -399,7 +493,7 @@ func (t TripResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 		code = http.StatusInternalServerError
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
 	}
-	trip.CalculateScores(latestTrip.Scores)
+	trip.CalculateScores(latestTrip.Scores, carShare.Scoring.Strategy())
 
 	err = t.TripStorage.Update(trip, r.Context)
 	switch err {
@@ -422,8 +516,27 @@ func (t TripResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 		)
 	}
 
+	if trip.Status.Counts() != tripInDataStore.Status.Counts() {
+		err = ledger.Rebuild(carShare, t.TripStorage, r.Context)
+		if err != nil {
+			errMsg := fmt.Sprintf("Trip %s updated but error occurred while rebuilding scores for car share %s", trip.GetID(), trip.CarShareID)
+			code = http.StatusInternalServerError
+			return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+		}
+		trip, err = t.TripStorage.GetOne(trip.GetID(), r.Context)
+		if err != nil {
+			errMsg := fmt.Sprintf("Error occurred while retrieving trip %s", trip.GetID())
+			code = http.StatusInternalServerError
+			return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+		}
+	}
+
+	notify(t.Webhooks, trip.CarShareID, model.TripUpdated, trip, r.Context)
+	pushEvent(t.Push, trip.CarShareID, model.TripUpdated, trip, requestingUser.GetID(), r.Context)
+	publish(t.Events, trip.CarShareID, events.Updated, trip)
+
 	// if an error occurs while populating, still attempt to send the remainder of the response. Don't store code for metrics
-	popErr := t.populate(&trip, r.Context)
+	popErr := t.populate(&trip, unit, r.Context)
 	if popErr != nil {
 		errMsg := fmt.Sprintf("Error when populating trip %s", trip.GetID())
 		err = api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, http.StatusInternalServerError)
@@ -433,8 +546,26 @@ func (t TripResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 	return &Response{Res: trip, Code: code}, err
 }
 
+// samePassengers returns true if both lists hold the same passengers, in any order
+func samePassengers(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int)
+	for _, ID := range a {
+		counts[ID]++
+	}
+	for _, ID := range b {
+		counts[ID]--
+		if counts[ID] < 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // addToCarShareTripList updates the associated carshare and ensures that it has the trip in its list of trips
-func (t TripResource) addToCarShareTripList(trip model.Trip, ctx api2go.APIContexter) (httpErr api2go.HTTPError, code int) {
+func (t TripResource) addToCarShareTripList(trip model.Trip, ctx api2go.APIContexter) (httpErr error, code int) {
 
 	carShare, err := t.CarShareStorage.GetOne(trip.CarShareID, ctx)
 	switch err {
@@ -469,11 +600,120 @@ func (t TripResource) addToCarShareTripList(trip model.Trip, ctx api2go.APIConte
 		}
 	}
 
-	return api2go.HTTPError{}, code
+	return nil, code
+}
+
+// price works out the cost of a trip from the vehicle it was made in, at the current fuel
+// prices, once it has checked the driver has access to the vehicle and it has enough seats
+func (t TripResource) price(trip *model.Trip, ctx api2go.APIContexter) (httpErr error, code int) {
+
+	if trip.VehicleID == "" {
+		trip.Cost = 0
+		trip.Contribution = 0
+		return nil, code
+	}
+
+	vehicle, err := t.VehicleStorage.GetOne(trip.VehicleID, ctx)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		code = http.StatusBadRequest
+		err = fmt.Errorf("unable to find vehicle %s", trip.VehicleID)
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	default:
+		errMsg := fmt.Sprintf("Error retrieving vehicle %s", trip.VehicleID)
+		code = http.StatusInternalServerError
+		return api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	switch {
+	case vehicle.CarShareID != trip.CarShareID:
+		err = fmt.Errorf("vehicle %s belongs to another car share", vehicle.GetID())
+	case trip.DriverID != "" && !vehicle.CanDrive(trip.DriverID):
+		err = fmt.Errorf("driver %s doesn't have access to vehicle %s", trip.DriverID, vehicle.GetID())
+	case !vehicle.Carries(len(trip.PassengerIDs) + len(trip.Guests)):
+		err = fmt.Errorf("vehicle %s only has %d seats, not enough for the driver and %d passengers", vehicle.GetID(), vehicle.Seats, len(trip.PassengerIDs)+len(trip.Guests))
+	}
+	if err != nil {
+		code = http.StatusBadRequest
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	if _, ok := t.FuelPrices[vehicle.FuelType]; !ok {
+		log.Warningf("no price configured for %s, trips in vehicle %s are only costed for wear", vehicle.FuelType, vehicle.GetID())
+	}
+	trip.Price(vehicle, t.FuelPrices)
+
+	return nil, code
 }
 
-// populate the relationships for a trip
-func (t TripResource) populate(trip *model.Trip, context api2go.APIContexter) error {
+// followRoute fills in the distance, driver and passengers of a trip from its route, where
+// they haven't been given
+func (t TripResource) followRoute(trip *model.Trip, ctx api2go.APIContexter) (httpErr error, code int) {
+
+	route, err := t.RouteStorage.GetOne(trip.RouteID, ctx)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		code = http.StatusBadRequest
+		err = fmt.Errorf("unable to find route %s", trip.RouteID)
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	default:
+		errMsg := fmt.Sprintf("Error retrieving route %s", trip.RouteID)
+		code = http.StatusInternalServerError
+		return api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	if route.CarShareID != trip.CarShareID {
+		code = http.StatusBadRequest
+		err = fmt.Errorf("route %s belongs to another car share", route.GetID())
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	trip.FollowRoute(route)
+
+	return nil, code
+}
+
+// unitsFor converts a trip distance given in km or miles to metres, and returns the unit
+// distances should be presented in for the response
+func (t TripResource) unitsFor(trip *model.Trip, r api2go.Request) (unit model.Unit, httpErr error, code int) {
+
+	err := trip.ConvertDistance()
+	if err != nil {
+		code = http.StatusBadRequest
+		return unit, api2go.NewHTTPError(
+			fmt.Errorf("Invalid distance given for trip %s, %s", trip.GetID(), err),
+			"distance must be a positive number of km or miles",
+			code,
+		), code
+	}
+
+	carShare, err := t.CarShareStorage.GetOne(trip.CarShareID, r.Context)
+	if err != nil {
+		code = http.StatusInternalServerError
+		return unit, api2go.NewHTTPError(
+			fmt.Errorf("unable to find car share %s linked to trip %s: %s", trip.CarShareID, trip.GetID(), err),
+			"Error finding associated car share",
+			code,
+		), code
+	}
+
+	unit, err = displayUnit(r, carShare)
+	if err != nil {
+		code = http.StatusBadRequest
+		return unit, api2go.NewHTTPError(err, "unit must be km or miles", code), code
+	}
+
+	return unit, nil, code
+}
+
+// populate the relationships for a trip, and present its distances in the given unit
+func (t TripResource) populate(trip *model.Trip, unit model.Unit, context api2go.APIContexter) error {
+
+	trip.FormatDistances(unit)
 
 	trip.Driver = nil
 	if trip.DriverID != "" {
@@ -497,12 +737,13 @@ func (t TripResource) populate(trip *model.Trip, context api2go.APIContexter) er
 }
 
 // verifyCarShareMember will return an error if the supplied user is not a member of the car share associated with the provided trip.
-func (t TripResource) verifyCarShareMember(user model.User, trip model.Trip, ctx api2go.APIContexter) (httpErr api2go.HTTPError, code int) {
+// Otherwise the car share is returned.
+func (t TripResource) verifyCarShareMember(user model.User, trip model.Trip, ctx api2go.APIContexter) (carShare model.CarShare, httpErr error, code int) {
 
 	carShare, err := t.CarShareStorage.GetOne(trip.CarShareID, ctx)
 	if err != nil {
 		code = http.StatusInternalServerError
-		return api2go.NewHTTPError(
+		return carShare, api2go.NewHTTPError(
 			fmt.Errorf("unable to find car share %s linked to trip %s: %s", trip.CarShareID, trip.GetID(), err),
 			"Error finding associated car share",
 			code,
@@ -511,12 +752,12 @@ func (t TripResource) verifyCarShareMember(user model.User, trip model.Trip, ctx
 
 	if !carShare.IsMember(user.GetID()) {
 		code = http.StatusForbidden
-		return api2go.NewHTTPError(
+		return carShare, api2go.NewHTTPError(
 			fmt.Errorf("user %s attempting to access trip %s for car share %s they are not a member of", user.GetID(), trip.GetID(), trip.CarShareID),
 			"must be a member or admin for associated carshare to access",
 			code,
 		), code
 	}
 
-	return api2go.HTTPError{}, code
+	return carShare, nil, code
 }
@@ -0,0 +1,270 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// overrideParam is the query parameter admins use to confirm a trip regardless of
+// outstanding confirmations or disputes
+const overrideParam = "override"
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	tripConfirmDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "trip_confirm_duration_seconds",
+		Help: "Time taken to confirm trips",
+	}, []string{"code"})
+	tripDisputeDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "trip_dispute_duration_seconds",
+		Help: "Time taken to dispute trips",
+	}, []string{"code"})
+	tripCancelDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "trip_cancel_duration_seconds",
+		Help: "Time taken to cancel draft and disputed trips",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(tripConfirmDurationSeconds)
+	prometheus.MustRegister(tripDisputeDurationSeconds)
+	prometheus.MustRegister(tripCancelDurationSeconds)
+
+}
+
+// Confirm a trip on behalf of its driver or one of its passengers. The trip counts towards
+// the car share scores once every passenger has confirmed it, or an admin overrides.
+func (t TripResource) Confirm(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		tripConfirmDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	trip, requestingUser, carShare, httpErr, code := t.tripForMember(ID, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	if trip.Status.Counts() {
+		code = http.StatusConflict
+		err := fmt.Errorf("trip %s is already confirmed", ID)
+		writeHTTPError(w, err, err.Error(), code)
+		return
+	}
+
+	before := trip.Status
+	switch {
+	case len(r.QueryParams[overrideParam]) > 0 && r.QueryParams[overrideParam][0] == "true":
+		if !carShare.IsAdmin(requestingUser.GetID()) {
+			code = http.StatusForbidden
+			writeHTTPError(w, fmt.Errorf("user %s attempting to override trip %s without being an admin", requestingUser.GetID(), ID), "only admins can override", code)
+			return
+		}
+		trip.Override()
+	case trip.IsParticipant(requestingUser.GetID()):
+		trip.Confirm(requestingUser.GetID())
+	default:
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("user %s attempting to confirm trip %s they weren't in", requestingUser.GetID(), ID), "only the driver and passengers can confirm a trip", code)
+		return
+	}
+
+	trip, httpErr, code = t.changeStatus(trip, before, carShare, requestingUser, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	code = http.StatusOK
+	writeResource(w, trip, code)
+}
+
+// Dispute a trip on behalf of its driver or one of its passengers, excluding it from the
+// car share scores until it is confirmed again or an admin overrides
+func (t TripResource) Dispute(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		tripDisputeDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	trip, requestingUser, carShare, httpErr, code := t.tripForMember(ID, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	if !trip.IsParticipant(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("user %s attempting to dispute trip %s they weren't in", requestingUser.GetID(), ID), "only the driver and passengers can dispute a trip", code)
+		return
+	}
+
+	before := trip.Status
+	trip.Dispute(requestingUser.GetID())
+
+	trip, httpErr, code = t.changeStatus(trip, before, carShare, requestingUser, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	code = http.StatusOK
+	writeResource(w, trip, code)
+}
+
+// Cancel a draft trip on behalf of a member, or a disputed trip on behalf of an admin,
+// removing it from the car share
+func (t TripResource) Cancel(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		tripCancelDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	trip, requestingUser, carShare, httpErr, code := t.tripForMember(ID, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	switch trip.Status {
+	case model.TripDraft:
+		break
+	case model.TripDisputed:
+		if !carShare.IsAdmin(requestingUser.GetID()) {
+			code = http.StatusForbidden
+			writeHTTPError(w, fmt.Errorf("user %s attempting to cancel disputed trip %s without being an admin", requestingUser.GetID(), ID), "only admins can cancel a disputed trip", code)
+			return
+		}
+	default:
+		code = http.StatusConflict
+		err := fmt.Errorf("trip %s is confirmed", ID)
+		writeHTTPError(w, err, err.Error(), code)
+		return
+	}
+
+	// draft and disputed trips don't count towards scores, so there is nothing to rebuild
+	err := t.TripStorage.Delete(ID, r.Context)
+	if err != nil {
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, fmt.Sprintf("Error occurred while cancelling trip %s", ID), code)
+		return
+	}
+
+	for index := range carShare.TripIDs {
+		if carShare.TripIDs[index] == ID {
+			carShare.TripIDs = append(carShare.TripIDs[:index], carShare.TripIDs[index+1:]...)
+			break
+		}
+	}
+	err = t.CarShareStorage.Update(carShare, r.Context)
+	if err != nil {
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, fmt.Sprintf("Trip cancelled but error occurred while updating car share %s", carShare.GetID()), code)
+		return
+	}
+
+	code = http.StatusNoContent
+	w.WriteHeader(code)
+}
+
+// changeStatus persists a trip whose status the requesting user may have changed, rebuilding
+// the scores of its car share if it has started or stopped counting towards them
+func (t TripResource) changeStatus(trip model.Trip, before model.TripStatus, carShare model.CarShare, requestingUser model.User, r api2go.Request) (model.Trip, error, int) {
+
+	err := t.TripStorage.Update(trip, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while updating trip %s", trip.GetID())
+		code := http.StatusInternalServerError
+		return trip, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	notify(t.Webhooks, trip.CarShareID, model.TripUpdated, trip, r.Context)
+	pushEvent(t.Push, trip.CarShareID, model.TripUpdated, trip, requestingUser.GetID(), r.Context)
+	publish(t.Events, trip.CarShareID, events.Updated, trip)
+
+	if before.Counts() == trip.Status.Counts() {
+		return trip, nil, http.StatusOK
+	}
+
+	// the trip may have been followed by others, whose scores now need recalculating
+	err = ledger.Rebuild(carShare, t.TripStorage, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Trip %s updated but error occurred while rebuilding scores for car share %s", trip.GetID(), trip.CarShareID)
+		code := http.StatusInternalServerError
+		return trip, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	trip, err = t.TripStorage.GetOne(trip.GetID(), r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while retrieving trip %s", trip.GetID())
+		code := http.StatusInternalServerError
+		return trip, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	return trip, nil, http.StatusOK
+}
+
+// tripForMember retrieves a trip, along with its car share, on behalf of a member of that car share
+func (t TripResource) tripForMember(ID string, r api2go.Request) (trip model.Trip, requestingUser model.User, carShare model.CarShare, httpErr error, code int) {
+
+	requestingUser, err := getRequestUser(r, t.TokenVerifier, t.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return trip, requestingUser, carShare, api2go.NewHTTPError(err, http.StatusText(code), code), code
+	}
+
+	trip, err = t.TripStorage.GetOne(ID, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return trip, requestingUser, carShare, api2go.NewHTTPError(fmt.Errorf("unable to find trip %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving trip %s", ID)
+		code = http.StatusInternalServerError
+		return trip, requestingUser, carShare, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	carShare, httpErr, code = findCarShare(t.CarShareStorage, trip.CarShareID, r.Context)
+	if httpErr != nil {
+		return trip, requestingUser, carShare, httpErr, code
+	}
+
+	// checked here rather than by each status change, so none of them can be made by outsiders
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return trip, requestingUser, carShare, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to change the status of trip %s for car share %s they are not a member of", requestingUser.GetID(), ID, carShare.GetID()),
+			"must be a member of associated carshare to change the status of its trips",
+			code,
+		), code
+	}
+
+	return trip, requestingUser, carShare, nil, code
+}
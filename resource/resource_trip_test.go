@@ -3,11 +3,14 @@ package resource
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"time"
 
 	"github.com/LewisWatson/carshare-back/model"
 	"github.com/LewisWatson/carshare-back/storage"
 	"github.com/LewisWatson/carshare-back/storage/mongodb"
 
+	"github.com/benbjohnson/clock"
 	"github.com/manyminds/api2go"
 
 	. "github.com/onsi/ginkgo"
@@ -41,7 +44,11 @@ var _ = Describe("Trip Resource", func() {
 			TripStorage:     &mongodb.TripStorage{},
 			UserStorage:     &mongodb.UserStorage{},
 			CarShareStorage: &mongodb.CarShareStorage{},
+			RouteStorage:    &mongodb.RouteStorage{},
+			VehicleStorage:  &mongodb.VehicleStorage{},
 			TokenVerifier:   mockTokenVerifier,
+			Clock:           clock.NewMock(),
+			FuelPrices:      model.FuelPrices{model.Diesel: 140},
 		}
 		context = &api2go.APIContext{}
 		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
@@ -139,9 +146,50 @@ var _ = Describe("Trip Resource", func() {
 			Expect(result).ToNot(BeNil())
 			response, ok := result.(*Response)
 			Expect(ok).To(Equal(true))
+			trip.FormatDistances(model.DefaultUnit)
 			Expect(response.Res).To(Equal(trip))
 		})
 
+		It("should present distances in the car share's preferred unit", func() {
+			response, ok := result.(*Response)
+			Expect(ok).To(Equal(true))
+			Expect(response.Res.(model.Trip).Unit).To(Equal(model.Kilometres))
+			Expect(response.Res.(model.Trip).Formatted).To(Equal("0.1 km"))
+		})
+
+		Context("requesting miles", func() {
+
+			BeforeEach(func() {
+				request.QueryParams = map[string][]string{"unit": {"miles"}}
+				result, err = tripResource.FindOne(trip1ID.Hex(), request)
+			})
+
+			It("should present distances in miles", func() {
+				Expect(err).ToNot(HaveOccurred())
+				response, ok := result.(*Response)
+				Expect(ok).To(Equal(true))
+				Expect(response.Res.(model.Trip).Unit).To(Equal(model.Miles))
+				Expect(response.Res.(model.Trip).Distance).To(BeNumerically("~", 123/model.MetresPerMile))
+			})
+
+		})
+
+		Context("requesting an unknown unit", func() {
+
+			BeforeEach(func() {
+				request.QueryParams = map[string][]string{"unit": {"furlongs"}}
+				result, err = tripResource.FindOne(trip1ID.Hex(), request)
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				expected := fmt.Sprintf("http error (400) unit must be km or miles and 0 more errors, %s", model.ErrUnknownUnit)
+				Expect(err.Error()).To(Equal(expected))
+			})
+
+		})
+
 		Context("invalid id", func() {
 
 			Context("trip does not exist", func() {
@@ -222,6 +270,7 @@ var _ = Describe("Trip Resource", func() {
 				Expect(result).ToNot(BeNil())
 				response, ok := result.(*Response)
 				Expect(ok).To(Equal(true))
+				trip.FormatDistances(model.DefaultUnit)
 				Expect(response.Res).To(Equal(trip))
 			})
 
@@ -234,6 +283,99 @@ var _ = Describe("Trip Resource", func() {
 
 		})
 
+		Context("update distance in miles", func() {
+
+			BeforeEach(func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				trip.Distance = 2.5
+				trip.Unit = model.Miles
+				result, err = tripResource.Update(trip, request)
+			})
+
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should store the distance in metres", func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trip.Metres).To(Equal(4023))
+			})
+
+		})
+
+		Context("update unit only", func() {
+
+			BeforeEach(func() {
+				var found api2go.Responder
+				found, err = tripResource.FindOne(trip1ID.Hex(), request)
+				Expect(err).NotTo(HaveOccurred())
+				trip = found.Result().(model.Trip)
+				trip.Unit = model.Miles
+				result, err = tripResource.Update(trip, request)
+			})
+
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should keep the stored distance", func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trip.Metres).To(Equal(123))
+			})
+
+		})
+
+		Context("update unit without a distance", func() {
+
+			BeforeEach(func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				trip.Unit = model.Miles
+				result, err = tripResource.Update(trip, request)
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				expected := fmt.Sprintf("http error (400) distance must be a positive number of km or miles and 0 more errors, Invalid distance given for trip %s, %s", trip1ID.Hex(), model.ErrMissingDistance)
+				Expect(err.Error()).To(Equal(expected))
+			})
+
+			It("should not change the trip", func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trip.Metres).To(Equal(123))
+			})
+
+		})
+
+		Context("update distance in an unknown unit", func() {
+
+			BeforeEach(func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				trip.Distance = 2.5
+				trip.Unit = model.Unit("leagues")
+				result, err = tripResource.Update(trip, request)
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				expected := fmt.Sprintf("http error (400) distance must be a positive number of km or miles and 0 more errors, Invalid distance given for trip %s, %s", trip1ID.Hex(), model.ErrUnknownUnit)
+				Expect(err.Error()).To(Equal(expected))
+			})
+
+			It("should not change the trip", func() {
+				trip, err = tripResource.TripStorage.GetOne(trip1ID.Hex(), context)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(trip.Metres).To(Equal(123))
+			})
+
+		})
+
 		Context("update relationship", func() {
 
 			Context("hasOne car share", func() {
@@ -403,6 +545,546 @@ var _ = Describe("Trip Resource", func() {
 
 	})
 
+	Describe("create from a route", func() {
+
+		var (
+			routeID = bson.NewObjectId()
+			trip    model.Trip
+			result  api2go.Responder
+			err     error
+		)
+
+		BeforeEach(func() {
+			db.DB(mongodb.CarShareDB).C(mongodb.RoutesColl).Insert(
+				&model.Route{
+					ID:           routeID,
+					Name:         "Commute",
+					Metres:       15000,
+					CarShareID:   carShare1ID.Hex(),
+					DriverID:     user1ID.Hex(),
+					PassengerIDs: []string{user2ID.Hex()},
+				},
+			)
+			trip = model.Trip{
+				CarShareID: carShare1ID.Hex(),
+				RouteID:    routeID.Hex(),
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = tripResource.Create(trip, request)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fill in the distance, driver and passengers from the route", func() {
+			response, ok := result.(*Response)
+			Expect(ok).To(BeTrue())
+			created := response.Res.(model.Trip)
+			Expect(created.Metres).To(Equal(15000))
+			Expect(created.DriverID).To(Equal(user1ID.Hex()))
+			Expect(created.PassengerIDs).To(Equal([]string{user2ID.Hex()}))
+			Expect(created.RouteID).To(Equal(routeID.Hex()))
+		})
+
+		Context("overriding the route", func() {
+
+			BeforeEach(func() {
+				trip.Metres = 16000
+				trip.PassengerIDs = []string{user3ID.Hex()}
+			})
+
+			It("should keep the details given", func() {
+				response, ok := result.(*Response)
+				Expect(ok).To(BeTrue())
+				created := response.Res.(model.Trip)
+				Expect(created.Metres).To(Equal(16000))
+				Expect(created.DriverID).To(Equal(user1ID.Hex()))
+				Expect(created.PassengerIDs).To(Equal([]string{user3ID.Hex()}))
+			})
+
+		})
+
+		Context("route does not exist", func() {
+
+			BeforeEach(func() {
+				trip.RouteID = bson.NewObjectId().Hex()
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				Expect(err.Error()).To(HavePrefix("http error (400)"))
+			})
+
+		})
+
+	})
+
+	Describe("create in a vehicle", func() {
+
+		var (
+			vehicleID = bson.NewObjectId()
+			trip      model.Trip
+			result    api2go.Responder
+			err       error
+		)
+
+		BeforeEach(func() {
+			db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).Insert(
+				&model.Vehicle{
+					ID:          vehicleID,
+					Name:        "Estate",
+					FuelType:    model.Diesel,
+					Consumption: 5,
+					WearRate:    2,
+					Seats:       5,
+					CarShareID:  carShare1ID.Hex(),
+					OwnerID:     user1ID.Hex(),
+				},
+			)
+			trip = model.Trip{
+				Metres:       10000,
+				CarShareID:   carShare1ID.Hex(),
+				DriverID:     user1ID.Hex(),
+				PassengerIDs: []string{user2ID.Hex(), user3ID.Hex()},
+				VehicleID:    vehicleID.Hex(),
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = tripResource.Create(trip, request)
+		})
+
+		It("should cost the trip and share it between everyone in the car", func() {
+			Expect(err).ToNot(HaveOccurred())
+			created := result.(*Response).Res.(model.Trip)
+			Expect(created.Cost).To(Equal(90))
+			Expect(created.Contribution).To(Equal(30))
+		})
+
+		Context("vehicle belongs to another car share", func() {
+
+			BeforeEach(func() {
+				db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).UpdateId(vehicleID, bson.M{"$set": bson.M{"car-share": carShare2ID.Hex()}})
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				reason := fmt.Sprintf("vehicle %s belongs to another car share", vehicleID.Hex())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("http error (400) %s and 0 more errors, %s", reason, reason)))
+			})
+
+			It("should not store the trip", func() {
+				trips, err := tripResource.TripStorage.GetAll(context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(trips).To(HaveLen(3))
+			})
+
+		})
+
+		Context("more passengers than the vehicle has seats for", func() {
+
+			BeforeEach(func() {
+				db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).UpdateId(vehicleID, bson.M{"$set": bson.M{"seats": 2}})
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				reason := fmt.Sprintf("vehicle %s only has 2 seats, not enough for the driver and 2 passengers", vehicleID.Hex())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("http error (400) %s and 0 more errors, %s", reason, reason)))
+			})
+
+			It("should not store the trip", func() {
+				trips, err := tripResource.TripStorage.GetAll(context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(trips).To(HaveLen(3))
+			})
+
+		})
+
+		Context("updated once the vehicle has fewer seats", func() {
+
+			JustBeforeEach(func() {
+				Expect(err).ToNot(HaveOccurred())
+				trip, err = tripResource.TripStorage.GetOne(result.(*Response).Res.(model.Trip).GetID(), context)
+				Expect(err).ToNot(HaveOccurred())
+				db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).UpdateId(vehicleID, bson.M{"$set": bson.M{"seats": 2}})
+				result, err = tripResource.Update(trip, request)
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				reason := fmt.Sprintf("vehicle %s only has 2 seats, not enough for the driver and 2 passengers", vehicleID.Hex())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("http error (400) %s and 0 more errors, %s", reason, reason)))
+			})
+
+		})
+
+		Context("driver doesn't have access to the vehicle", func() {
+
+			BeforeEach(func() {
+				db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).UpdateId(vehicleID, bson.M{"$set": bson.M{"owner": user3ID.Hex()}})
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				reason := fmt.Sprintf("driver %s doesn't have access to vehicle %s", user1ID.Hex(), vehicleID.Hex())
+				Expect(err.Error()).To(Equal(fmt.Sprintf("http error (400) %s and 0 more errors, %s", reason, reason)))
+			})
+
+			It("should not store the trip", func() {
+				trips, err := tripResource.TripStorage.GetAll(context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(trips).To(HaveLen(3))
+			})
+
+			Context("until the owner lets them drive it", func() {
+
+				BeforeEach(func() {
+					db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).UpdateId(vehicleID, bson.M{"$set": bson.M{"drivers": []string{user1ID.Hex()}}})
+				})
+
+				It("should create the trip", func() {
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.(*Response).Res.(model.Trip).Cost).To(Equal(90))
+				})
+
+			})
+
+		})
+
+		Context("guests taking the last seat", func() {
+
+			BeforeEach(func() {
+				db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).UpdateId(vehicleID, bson.M{"$set": bson.M{"seats": 3}})
+				trip.Guests = []model.Guest{{Name: "Visitor"}}
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				Expect(err.Error()).To(HavePrefix("http error (400)"))
+			})
+
+		})
+
+	})
+
+	Describe("create with guests", func() {
+
+		var (
+			trip   model.Trip
+			result api2go.Responder
+			err    error
+		)
+
+		BeforeEach(func() {
+			trip = model.Trip{
+				Metres:       1000,
+				CarShareID:   carShare1ID.Hex(),
+				DriverID:     user1ID.Hex(),
+				PassengerIDs: []string{user2ID.Hex()},
+				Guests:       []model.Guest{{Name: "Visitor", Counted: true}, {Name: "Hitchhiker"}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = tripResource.Create(trip, request)
+		})
+
+		It("should record the guests on the trip, only scoring those that are counted", func() {
+			Expect(err).ToNot(HaveOccurred())
+			created := result.(*Response).Res.(model.Trip)
+			Expect(created.Guests).To(HaveLen(2))
+			Expect(created.Scores["guest:visitor"].MetresAsPassenger).To(Equal(1000))
+			Expect(created.Scores).ToNot(HaveKey("guest:hitchhiker"))
+		})
+
+		It("should not make the guests members of the car share", func() {
+			carShare, err := tripResource.CarShareStorage.GetOne(carShare1ID.Hex(), request.Context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.MemberIDs).To(Equal([]string{user1ID.Hex()}))
+		})
+
+		Context("with the same guest named twice", func() {
+
+			BeforeEach(func() {
+				trip.Guests = append(trip.Guests, model.Guest{Name: " visitor "})
+			})
+
+			It("should return a bad request error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+				Expect(err.Error()).To(HavePrefix("http error (400)"))
+			})
+
+		})
+
+	})
+
+	Describe("status", func() {
+
+		var (
+			draftID  = bson.NewObjectId()
+			recorder *httptest.ResponseRecorder
+		)
+
+		// actAs switches the requesting user to the given member of car share 1
+		actAs := func(firebaseUID string) {
+			recorder = httptest.NewRecorder()
+			tripResource.TokenVerifier.(mockTokenVerifier).Claims.Set("sub", firebaseUID)
+		}
+
+		BeforeEach(func() {
+			recorder = httptest.NewRecorder()
+			db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).UpdateId(user2ID, bson.M{"$set": bson.M{"firebase-uid": "user2FirebaseUID"}})
+			db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).UpdateId(user3ID, bson.M{"$set": bson.M{"firebase-uid": "user3FirebaseUID"}})
+			db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).Insert(
+				&model.Trip{
+					ID:           draftID,
+					Metres:       1000,
+					TimeStamp:    time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC),
+					Status:       model.TripDraft,
+					CarShareID:   carShare1ID.Hex(),
+					DriverID:     user1ID.Hex(),
+					PassengerIDs: []string{user2ID.Hex()},
+					Scores:       map[string]model.Score{},
+				},
+			)
+			db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).UpdateId(carShare1ID, bson.M{
+				"$push": bson.M{"trips": draftID.Hex()},
+				"$set":  bson.M{"members": []string{user1ID.Hex(), user2ID.Hex(), user3ID.Hex()}, "admins": []string{user3ID.Hex()}},
+			})
+		})
+
+		Describe("confirm", func() {
+
+			Context("by the driver", func() {
+
+				BeforeEach(func() {
+					tripResource.Confirm(draftID.Hex(), recorder, request)
+				})
+
+				It("should still await the passenger", func() {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					trip, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(trip.Status).To(Equal(model.TripDraft))
+					Expect(trip.AwaitingConfirmation()).To(Equal([]string{user2ID.Hex()}))
+				})
+
+				Context("and then the passenger", func() {
+
+					BeforeEach(func() {
+						actAs("user2FirebaseUID")
+						tripResource.Confirm(draftID.Hex(), recorder, request)
+					})
+
+					It("should respond with the confirmed trip", func() {
+						Expect(recorder.Code).To(Equal(http.StatusOK))
+						Expect(recorder.Body.String()).To(ContainSubstring(`"status":"confirmed"`))
+					})
+
+					It("should count the trip towards the scores", func() {
+						trip, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+						Expect(err).ToNot(HaveOccurred())
+						Expect(trip.Status).To(Equal(model.TripConfirmed))
+						Expect(trip.Scores[user1ID.Hex()].MetresAsDriver).To(Equal(1000))
+						Expect(trip.Scores[user2ID.Hex()].MetresAsPassenger).To(Equal(1000))
+					})
+
+					Context("a trip that is already confirmed", func() {
+
+						BeforeEach(func() {
+							recorder = httptest.NewRecorder()
+							tripResource.Confirm(draftID.Hex(), recorder, request)
+						})
+
+						It("should respond with a conflict", func() {
+							Expect(recorder.Code).To(Equal(http.StatusConflict))
+						})
+
+					})
+
+				})
+
+			})
+
+			Context("by a member who wasn't in the car", func() {
+
+				BeforeEach(func() {
+					actAs("user3FirebaseUID")
+					tripResource.Confirm(draftID.Hex(), recorder, request)
+				})
+
+				It("should respond with forbidden", func() {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				})
+
+			})
+
+			Context("overridden by an admin", func() {
+
+				BeforeEach(func() {
+					actAs("user3FirebaseUID")
+					request.QueryParams = map[string][]string{"override": {"true"}}
+					tripResource.Confirm(draftID.Hex(), recorder, request)
+				})
+
+				It("should confirm the trip without awaiting the passenger", func() {
+					Expect(recorder.Code).To(Equal(http.StatusOK))
+					trip, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(trip.Status).To(Equal(model.TripConfirmed))
+					Expect(trip.Scores[user2ID.Hex()].MetresAsPassenger).To(Equal(1000))
+				})
+
+			})
+
+			Context("overridden by a member who isn't an admin", func() {
+
+				BeforeEach(func() {
+					request.QueryParams = map[string][]string{"override": {"true"}}
+					tripResource.Confirm(draftID.Hex(), recorder, request)
+				})
+
+				It("should respond with forbidden", func() {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				})
+
+			})
+
+		})
+
+		Describe("dispute", func() {
+
+			BeforeEach(func() {
+				actAs("user3FirebaseUID")
+				request.QueryParams = map[string][]string{"override": {"true"}}
+				tripResource.Confirm(draftID.Hex(), recorder, request)
+				request.QueryParams = nil
+				actAs("user2FirebaseUID")
+				tripResource.Dispute(draftID.Hex(), recorder, request)
+			})
+
+			It("should respond with the disputed trip", func() {
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+				Expect(recorder.Body.String()).To(ContainSubstring(`"status":"disputed"`))
+			})
+
+			It("should exclude the trip from the scores", func() {
+				trip, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(trip.DisputedBy).To(Equal([]string{user2ID.Hex()}))
+				Expect(trip.Scores[user2ID.Hex()].MetresAsPassenger).To(BeZero())
+			})
+
+			Context("withdrawn by confirming", func() {
+
+				BeforeEach(func() {
+					actAs("user2FirebaseUID")
+					tripResource.Confirm(draftID.Hex(), recorder, request)
+				})
+
+				It("should count the trip towards the scores again", func() {
+					trip, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(trip.Status).To(Equal(model.TripConfirmed))
+					Expect(trip.Scores[user2ID.Hex()].MetresAsPassenger).To(Equal(1000))
+				})
+
+			})
+
+			Context("cancelled by a member who isn't an admin", func() {
+
+				BeforeEach(func() {
+					actAs("user1FirebaseUID")
+					tripResource.Cancel(draftID.Hex(), recorder, request)
+				})
+
+				It("should respond with forbidden", func() {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				})
+
+			})
+
+			Context("by a member who wasn't in the car", func() {
+
+				BeforeEach(func() {
+					actAs("user3FirebaseUID")
+					tripResource.Dispute(draftID.Hex(), recorder, request)
+				})
+
+				It("should respond with forbidden", func() {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				})
+
+			})
+
+		})
+
+		Describe("cancel", func() {
+
+			BeforeEach(func() {
+				tripResource.Cancel(draftID.Hex(), recorder, request)
+			})
+
+			It("should respond with no content", func() {
+				Expect(recorder.Code).To(Equal(http.StatusNoContent))
+			})
+
+			It("should delete the trip and remove it from the car share", func() {
+				_, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+				Expect(err).To(Equal(storage.ErrNotFound))
+				carShare, err := tripResource.CarShareStorage.GetOne(carShare1ID.Hex(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(carShare.TripIDs).ToNot(ContainElement(draftID.Hex()))
+			})
+
+		})
+
+		Describe("as a non member", func() {
+
+			BeforeEach(func() {
+				actAs("nonMemberFirebaseUID")
+				db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(&model.User{FirebaseUID: "nonMemberFirebaseUID"})
+				tripResource.Confirm(draftID.Hex(), recorder, request)
+			})
+
+			It("should respond with forbidden", func() {
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			})
+
+			Context("cancelling", func() {
+
+				BeforeEach(func() {
+					recorder = httptest.NewRecorder()
+					tripResource.Cancel(draftID.Hex(), recorder, request)
+				})
+
+				It("should respond with forbidden", func() {
+					Expect(recorder.Code).To(Equal(http.StatusForbidden))
+				})
+
+				It("should leave the trip in place", func() {
+					trip, err := tripResource.TripStorage.GetOne(draftID.Hex(), context)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(trip.Status).To(Equal(model.TripDraft))
+				})
+
+			})
+
+		})
+
+	})
+
 	Describe("delete", func() {
 
 		var (
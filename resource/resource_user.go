@@ -6,18 +6,33 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/LewisWatson/carshare-back/account"
+	"github.com/LewisWatson/carshare-back/events"
 	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/push"
 	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/webhook"
 	"github.com/manyminds/api2go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/LewisWatson/firebase-jwt-auth"
 )
 
+// meID finds the requesting user
+const meID = "me"
+
 // UserResource for api2go routes
 type UserResource struct {
 	UserStorage     storage.UserStorage
 	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	RouteStorage    storage.RouteStorage
+	ScheduleStorage storage.ScheduleStorage
+	VehicleStorage  storage.VehicleStorage
+	ExpenseStorage  storage.ExpenseStorage
 	TokenVerifier   fireauth.TokenVerifier
+	Webhooks        *webhook.Notifier
+	Push            *push.Pusher
+	Events          *events.Bus
 }
 
 var (
@@ -71,15 +86,68 @@ func (u UserResource) FindAll(r api2go.Request) (api2go.Responder, error) {
 	return &Response{}, api2go.NewHTTPError(fmt.Errorf("Find all users not supported"), http.StatusText(code), code)
 }
 
-// FindOne to satisfy api2go.CRUD interface
+// FindOne to satisfy api2go.CRUD interface. Users can find themselves, also as "me", and
+// anyone they share a car share with. Only users finding themselves see their email and
+// firebase UID.
 func (u UserResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
 
 	// metrics collection. Need to be careful to capture return code before returning
 	start := time.Now()
-	code := http.StatusMethodNotAllowed
-	defer userFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	code := http.StatusInternalServerError
+	defer userFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+
+	requestingUser, err := getRequestUser(r, u.TokenVerifier, u.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Error retrieving user, %s", err), http.StatusText(code), code)
+	}
+
+	if ID == meID || ID == requestingUser.GetID() {
+		code = http.StatusOK
+		return &Response{Res: requestingUser.Private(), Code: code}, nil
+	}
+
+	user, err := u.UserStorage.GetOne(ID, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find user %s", ID), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving user %s", ID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
 
-	return &Response{}, api2go.NewHTTPError(fmt.Errorf("Find one users not supported"), http.StatusText(code), code)
+	shared, err := u.sharesCarShare(requestingUser, user, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while retrieving car shares of user %s", requestingUser.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+	if !shared {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("User %v doesn't share a car share with user %v", requestingUser.GetID(), ID), http.StatusText(code), code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: user, Code: code}, nil
+}
+
+// sharesCarShare returns true if the requesting user is a member of a car share the user is a
+// member of or linked to
+func (u UserResource) sharesCarShare(requestingUser model.User, user model.User, context api2go.APIContexter) (bool, error) {
+	carShares, err := u.CarShareStorage.GetAll(requestingUser.GetID(), context)
+	if err != nil {
+		return false, err
+	}
+	for _, carShare := range carShares {
+		if carShare.IsMember(user.GetID()) || carShare.GetID() == user.LinkedCarShareID {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 // Create to satisfy api2go.CRUD interface
@@ -172,6 +240,15 @@ func (u UserResource) Delete(id string, r api2go.Request) (api2go.Responder, err
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("error deleting user, %s", err), http.StatusText(code), code)
 	}
 
+	if id == meID || id == requestingUser.GetID() {
+		httpErr, status := u.erase(requestingUser, r)
+		code = status
+		if httpErr != nil {
+			return &Response{}, httpErr
+		}
+		return &Response{Code: code}, nil
+	}
+
 	targetUser, err := u.UserStorage.GetOne(id, r.Context)
 	switch err {
 	case nil:
@@ -192,7 +269,7 @@ func (u UserResource) Delete(id string, r api2go.Request) (api2go.Responder, err
 		)
 	}
 
-	// not allowing firebase users to be deleted. This might be added in future, but we would need to cascade the delete so it won't be a straight forward operation
+	// firebase users can only delete themselves
 	if targetUser.FirebaseUID != "" {
 		code = http.StatusForbidden
 		return &Response{}, api2go.NewHTTPError(
@@ -233,6 +310,9 @@ func (u UserResource) Delete(id string, r api2go.Request) (api2go.Responder, err
 					code,
 				)
 			}
+			notify(u.Webhooks, carShare.GetID(), model.MemberRemoved, targetUser, r.Context)
+			pushEvent(u.Push, carShare.GetID(), model.MemberRemoved, targetUser, requestingUser.GetID(), r.Context)
+			publish(u.Events, carShare.GetID(), events.Updated, carShare)
 			break
 		}
 	}
@@ -268,6 +348,14 @@ func (u UserResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Error updating user, %s", err), http.StatusText(code), code)
 	}
 
+	// users updating themselves were found privately, but can't change their private details
+	// other than which emails and notifications they receive
+	if private, ok := obj.(model.PrivateUser); ok {
+		private.User.EmailOptOut = private.EmailOptOut
+		private.User.PushOptOut = private.PushOptOut
+		obj = private.User
+	}
+
 	user, ok := obj.(model.User)
 	if !ok {
 		code = http.StatusBadRequest
@@ -284,6 +372,26 @@ func (u UserResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Error updating user, %s", err), msg, code)
 	}
 
+	err = user.ValidateEmailOptOut()
+	if err == nil {
+		err = user.ValidatePushOptOut()
+	}
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Error updating user, %s", err), err.Error(), code)
+	}
+
+	// profile fields the user edits are no longer refreshed from firebase when they sign in
+	if existing, err := u.UserStorage.GetOne(user.GetID(), r.Context); err == nil {
+		user.Overrides = existing.Overrides
+		if user.DisplayName != existing.DisplayName {
+			user.Override(model.DisplayNameField)
+		}
+		if user.PhotoURL != existing.PhotoURL {
+			user.Override(model.PhotoURLField)
+		}
+	}
+
 	switch u.UserStorage.Update(user, r.Context) {
 	case nil:
 		break
@@ -304,6 +412,36 @@ func (u UserResource) Update(obj interface{}, r api2go.Request) (api2go.Responde
 	return &Response{Res: user, Code: code}, err
 }
 
+// erase the requesting user's account, anonymising them throughout their car shares
+func (u UserResource) erase(user model.User, r api2go.Request) (httpErr error, code int) {
+
+	eraser := account.Eraser{
+		UserStorage:     u.UserStorage,
+		CarShareStorage: u.CarShareStorage,
+		TripStorage:     u.TripStorage,
+		RouteStorage:    u.RouteStorage,
+		ScheduleStorage: u.ScheduleStorage,
+		VehicleStorage:  u.VehicleStorage,
+		ExpenseStorage:  u.ExpenseStorage,
+	}
+	err := eraser.Erase(user, r.Context)
+	switch err {
+	case nil:
+		return nil, http.StatusOK
+	case account.ErrSoleAdmin:
+		code = http.StatusConflict
+		return api2go.NewHTTPError(
+			fmt.Errorf("error deleting user %s, %s", user.GetID(), err),
+			"make another member admin of your car shares before deleting your account",
+			code,
+		), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting user %s", user.GetID())
+		code = http.StatusInternalServerError
+		return api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
+
 func (u UserResource) validateUpsert(user model.User, requestingUser model.User, context api2go.APIContexter) (msg string, status int, err error) {
 
 	if user.FirebaseUID == "" && user.LinkedCarShareID == "" {
@@ -0,0 +1,83 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/export"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UserExportResource streams archives of everything stored about a user to that user
+type UserExportResource struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	userExportDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "user_export_duration_seconds",
+		Help: "Time taken to export users",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(userExportDurationSeconds)
+
+}
+
+// Export writes a JSON archive of the requesting user, who can be given as "me" or by their id
+func (e UserExportResource) Export(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		userExportDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, e.TokenVerifier, e.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	if ID != meID && ID != requestingUser.GetID() {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("User %v attempting to export user %v", requestingUser.GetID(), ID), http.StatusText(code), code)
+		return
+	}
+
+	w.Header().Set("Content-Type", export.JSON.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-%s.%s\"", requestingUser.GetID(), export.JSON))
+	w.WriteHeader(http.StatusOK)
+
+	exporter := export.UserExporter{
+		CarShareStorage: e.CarShareStorage,
+		TripStorage:     e.TripStorage,
+	}
+
+	// the status has already been sent, so all that can be done is stop writing
+	err = exporter.Export(requestingUser, w, r.Context)
+	if err != nil {
+		log.Errorf("Error occurred while exporting user %s, %s", requestingUser.GetID(), err)
+		return
+	}
+
+	code = http.StatusOK
+}
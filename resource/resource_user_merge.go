@@ -0,0 +1,187 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/account"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UserMergeResource lets people who sign up claim the placeholder user an admin created
+// for them, and admins approve the claim by merging the placeholder into them
+type UserMergeResource struct {
+	UserStorage     storage.UserStorage
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	RouteStorage    storage.RouteStorage
+	ScheduleStorage storage.ScheduleStorage
+	VehicleStorage  storage.VehicleStorage
+	ExpenseStorage  storage.ExpenseStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	userClaimDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "user_claim_duration_seconds",
+		Help: "Time taken to claim placeholder users",
+	}, []string{"code"})
+	userMergeDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "user_merge_duration_seconds",
+		Help: "Time taken to merge placeholder users",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(userClaimDurationSeconds)
+	prometheus.MustRegister(userMergeDurationSeconds)
+
+}
+
+// Claim a placeholder user on behalf of the requesting user, who is who the placeholder was
+// created for. Nothing changes until an admin of the placeholder's car share merges them.
+func (u UserMergeResource) Claim(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		userClaimDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, u.TokenVerifier, u.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	placeholder, httpErr, code := u.placeholder(ID, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	placeholder.ClaimedBy = requestingUser.GetID()
+	err = u.UserStorage.Update(placeholder, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while claiming user %s", ID)
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	code = http.StatusAccepted
+	writeResource(w, placeholder, code)
+}
+
+// Merge a claimed placeholder user into whoever claimed it, on behalf of an admin of the car
+// share the placeholder was created for. Everything the placeholder was part of is moved
+// over to the claiming user and the placeholder deleted.
+func (u UserMergeResource) Merge(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		userMergeDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, u.TokenVerifier, u.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	placeholder, httpErr, code := u.placeholder(ID, r)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	carShare, httpErr, code := findCarShare(u.CarShareStorage, placeholder.LinkedCarShareID, r.Context)
+	if httpErr != nil {
+		writeError(w, httpErr, code)
+		return
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("Non admin user %v attempting to merge user %v linked to car share %v", requestingUser.GetID(), ID, carShare.GetID()), http.StatusText(code), code)
+		return
+	}
+
+	if placeholder.ClaimedBy == "" {
+		code = http.StatusConflict
+		err = fmt.Errorf("user %s hasn't been claimed", ID)
+		writeHTTPError(w, err, err.Error(), code)
+		return
+	}
+
+	claimingUser, err := u.UserStorage.GetOne(placeholder.ClaimedBy, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while retrieving user %s who claimed user %s", placeholder.ClaimedBy, ID)
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	merger := account.Merger{
+		UserStorage:     u.UserStorage,
+		CarShareStorage: u.CarShareStorage,
+		TripStorage:     u.TripStorage,
+		RouteStorage:    u.RouteStorage,
+		ScheduleStorage: u.ScheduleStorage,
+		VehicleStorage:  u.VehicleStorage,
+		ExpenseStorage:  u.ExpenseStorage,
+	}
+	err = merger.Merge(placeholder, claimingUser, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while merging user %s into %s", ID, claimingUser.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	code = http.StatusOK
+	writeResource(w, claimingUser, code)
+}
+
+// placeholder retrieves a user that can be claimed and merged
+func (u UserMergeResource) placeholder(ID string, r api2go.Request) (placeholder model.User, httpErr error, code int) {
+
+	placeholder, err := u.UserStorage.GetOne(ID, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		code = http.StatusNotFound
+		return placeholder, api2go.NewHTTPError(fmt.Errorf("unable to find user %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving user %s", ID)
+		code = http.StatusInternalServerError
+		return placeholder, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+
+	if !placeholder.IsPlaceholder() {
+		code = http.StatusBadRequest
+		err = fmt.Errorf("user %s signed up for themselves so can't be claimed", ID)
+		return placeholder, api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	return placeholder, nil, code
+}
@@ -0,0 +1,150 @@
+package resource
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("User Merge Resource", func() {
+
+	var (
+		userMergeResource *UserMergeResource
+		request           api2go.Request
+		context           *api2go.APIContext
+		mockVerifier      mockTokenVerifier
+		recorder          *httptest.ResponseRecorder
+		adminID           = bson.NewObjectId()
+		placeholderID     = bson.NewObjectId()
+		newcomerID        = bson.NewObjectId()
+		carShareID        = bson.NewObjectId()
+		tripID            = bson.NewObjectId()
+	)
+
+	// actAs switches the requesting user
+	actAs := func(firebaseUID string) {
+		mockVerifier.Claims.Set("sub", firebaseUID)
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		actAs("newcomerFirebaseUID")
+		userMergeResource = &UserMergeResource{
+			UserStorage:     &mongodb.UserStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			TripStorage:     &mongodb.TripStorage{},
+			RouteStorage:    &mongodb.RouteStorage{},
+			ScheduleStorage: &mongodb.ScheduleStorage{},
+			VehicleStorage:  &mongodb.VehicleStorage{},
+			ExpenseStorage:  &mongodb.ExpenseStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		recorder = httptest.NewRecorder()
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: placeholderID, DisplayName: "Sam", LinkedCarShareID: carShareID.Hex()},
+			&model.User{ID: newcomerID, FirebaseUID: "newcomerFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), placeholderID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+				TripIDs:   []string{tripID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.TripsColl).Insert(
+			&model.Trip{
+				ID:           tripID,
+				Metres:       1000,
+				CarShareID:   carShareID.Hex(),
+				DriverID:     adminID.Hex(),
+				PassengerIDs: []string{placeholderID.Hex()},
+				Scores: map[string]model.Score{
+					adminID.Hex():       {MetresAsDriver: 1000},
+					placeholderID.Hex(): {MetresAsPassenger: 1000},
+				},
+			},
+		)
+	})
+
+	Describe("claim", func() {
+
+		It("should record who claimed the placeholder", func() {
+			userMergeResource.Claim(placeholderID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusAccepted))
+			placeholder, err := userMergeResource.UserStorage.GetOne(placeholderID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(placeholder.ClaimedBy).To(Equal(newcomerID.Hex()))
+		})
+
+		It("should not let users who signed up for themselves be claimed", func() {
+			userMergeResource.Claim(adminID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusBadRequest))
+		})
+
+	})
+
+	Describe("merge", func() {
+
+		BeforeEach(func() {
+			actAs("adminFirebaseUID")
+		})
+
+		It("should not merge a placeholder that hasn't been claimed", func() {
+			userMergeResource.Merge(placeholderID.Hex(), recorder, request)
+			Expect(recorder.Code).To(Equal(http.StatusConflict))
+		})
+
+		Context("once claimed", func() {
+
+			BeforeEach(func() {
+				db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).UpdateId(placeholderID, bson.M{"$set": bson.M{"claimed-by": newcomerID.Hex()}})
+			})
+
+			It("should move the placeholder's history to the claiming user", func() {
+				userMergeResource.Merge(placeholderID.Hex(), recorder, request)
+				Expect(recorder.Code).To(Equal(http.StatusOK))
+
+				carShare, err := userMergeResource.CarShareStorage.GetOne(carShareID.Hex(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(carShare.MemberIDs).To(ConsistOf(adminID.Hex(), newcomerID.Hex()))
+
+				trip, err := userMergeResource.TripStorage.GetOne(tripID.Hex(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(trip.PassengerIDs).To(Equal([]string{newcomerID.Hex()}))
+				Expect(trip.Scores).To(HaveKey(newcomerID.Hex()))
+				Expect(trip.Scores).ToNot(HaveKey(placeholderID.Hex()))
+
+				_, err = userMergeResource.UserStorage.GetOne(placeholderID.Hex(), context)
+				Expect(err).To(Equal(storage.ErrNotFound))
+			})
+
+			It("should only let admins of the placeholder's car share merge it", func() {
+				actAs("newcomerFirebaseUID")
+				userMergeResource.Merge(placeholderID.Hex(), recorder, request)
+				Expect(recorder.Code).To(Equal(http.StatusForbidden))
+			})
+
+		})
+
+	})
+
+})
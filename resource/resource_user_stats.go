@@ -0,0 +1,88 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// UserStatsResource totals up a user's trips across every car share they belong to
+type UserStatsResource struct {
+	CarShareStorage storage.CarShareStorage
+	TripStorage     storage.TripStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	userStatsDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "user_stats_duration_seconds",
+		Help: "Time taken to work out user stats",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(userStatsDurationSeconds)
+
+}
+
+// Stats of the requesting user, who can be given as "me" or by their id: metres and trips
+// as driver and passenger, month by month and in total, and who they travel with most
+func (u UserStatsResource) Stats(ID string, w http.ResponseWriter, r api2go.Request) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		userStatsDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, u.TokenVerifier, u.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		writeHTTPError(w, err, http.StatusText(code), code)
+		return
+	}
+
+	if ID != meID && ID != requestingUser.GetID() {
+		code = http.StatusForbidden
+		writeHTTPError(w, fmt.Errorf("User %v attempting to view stats of user %v", requestingUser.GetID(), ID), http.StatusText(code), code)
+		return
+	}
+
+	carShares, err := u.CarShareStorage.GetAll(requestingUser.GetID(), r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while retrieving car shares of user %s", requestingUser.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+	carShareIDs := []string{}
+	for _, carShare := range carShares {
+		carShareIDs = append(carShareIDs, carShare.GetID())
+	}
+
+	stats, err := u.TripStorage.Stats(requestingUser.GetID(), carShareIDs, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error occurred while working out stats of user %s", requestingUser.GetID())
+		code = http.StatusInternalServerError
+		writeHTTPError(w, err, errMsg, code)
+		return
+	}
+
+	code = http.StatusOK
+	writeResource(w, stats, code)
+}
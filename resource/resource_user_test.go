@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 
@@ -26,6 +27,11 @@ var _ = Describe("User Resource", func() {
 		userResource = &UserResource{
 			UserStorage:     &mongodb.UserStorage{},
 			CarShareStorage: &mongodb.CarShareStorage{},
+			TripStorage:     &mongodb.TripStorage{},
+			RouteStorage:    &mongodb.RouteStorage{},
+			ScheduleStorage: &mongodb.ScheduleStorage{},
+			VehicleStorage:  &mongodb.VehicleStorage{},
+			ExpenseStorage:  &mongodb.ExpenseStorage{},
 		}
 		fbUser = model.User{
 			ID:          bson.NewObjectId(),
@@ -87,16 +93,111 @@ var _ = Describe("User Resource", func() {
 
 	Describe("get one", func() {
 
-		var err error
+		var (
+			ID     string
+			result api2go.Responder
+			err    error
+		)
 
 		BeforeEach(func() {
-			_, err = userResource.FindOne("", request)
+			ID = ""
+			mockTokenVerifier := mockTokenVerifier{}
+			mockTokenVerifier.Claims = make(jwt.Claims)
+			mockTokenVerifier.Claims.Set("sub", fbUser.FirebaseUID)
+			userResource.TokenVerifier = mockTokenVerifier
+
+			sharedCarShare := model.CarShare{
+				ID:        bson.NewObjectId(),
+				Name:      "Car share of fbUser and fbUser2",
+				AdminIDs:  []string{fbUser.GetID()},
+				MemberIDs: []string{fbUser.GetID(), fbUser2.GetID()},
+			}
+			err := db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(sharedCarShare)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		JustBeforeEach(func() {
+			result, err = userResource.FindOne(ID, request)
 		})
 
 		It("should throw an error", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		Context("me", func() {
+
+			BeforeEach(func() {
+				ID = "me"
+			})
+
+			It("should return the requesting user with their private details", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.StatusCode()).To(Equal(http.StatusOK))
+				Expect(result.Result()).To(Equal(fbUser.Private()))
+				attributes, err := json.Marshal(result.Result())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(attributes)).To(ContainSubstring(`"firebase-uid":"fbUserfirebaseuid"`))
+			})
+
+		})
+
+		Context("own id", func() {
+
+			BeforeEach(func() {
+				ID = fbUser.GetID()
+			})
+
+			It("should return the requesting user with their private details", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Result()).To(Equal(fbUser.Private()))
+			})
+
+		})
+
+		Context("fellow member of a car share", func() {
+
+			BeforeEach(func() {
+				ID = fbUser2.GetID()
+			})
+
+			It("should return the user without their private details", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.StatusCode()).To(Equal(http.StatusOK))
+				Expect(result.Result()).To(Equal(fbUser2))
+				attributes, err := json.Marshal(result.Result())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(attributes)).ToNot(ContainSubstring("firebase"))
+				Expect(string(attributes)).ToNot(ContainSubstring("email"))
+			})
+
+		})
+
+		Context("user who doesn't share a car share", func() {
+
+			BeforeEach(func() {
+				ID = csLinkedUser.GetID()
+			})
+
+			It("should return a 403 error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("http error (403)"))
+			})
+
+		})
+
+		Context("non existing user", func() {
+
+			BeforeEach(func() {
+				ID = bson.NewObjectId().Hex()
+			})
+
+			It("should return a 404 error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("http error (404)"))
+			})
+
+		})
+
 	})
 
 	Describe("create", func() {
@@ -356,6 +457,65 @@ var _ = Describe("User Resource", func() {
 
 		})
 
+		Context("opting out of emails", func() {
+
+			var optOut []string
+
+			BeforeEach(func() {
+				private := fbUser.Private()
+				private.EmailOptOut = optOut
+				result, err = userResource.Update(private, request)
+			})
+
+			Context("of known kinds", func() {
+
+				BeforeEach(func() {
+					optOut = []string{model.DigestEmail}
+				})
+
+				It("should persist the opt out", func() {
+					Expect(err).ToNot(HaveOccurred())
+					persistedUser, err := userResource.UserStorage.GetOne(fbUser.GetID(), request.Context)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(persistedUser.EmailOptOut).To(Equal([]string{model.DigestEmail}))
+					Expect(persistedUser.WantsEmail(model.DigestEmail)).To(BeFalse())
+				})
+
+			})
+
+			Context("of pushed events", func() {
+
+				BeforeEach(func() {
+					private := fbUser.Private()
+					private.PushOptOut = []string{model.TripUpdated}
+					result, err = userResource.Update(private, request)
+				})
+
+				It("should persist the opt out", func() {
+					Expect(err).ToNot(HaveOccurred())
+					persistedUser, err := userResource.UserStorage.GetOne(fbUser.GetID(), request.Context)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(persistedUser.WantsPush(model.TripUpdated)).To(BeFalse())
+					Expect(persistedUser.WantsPush(model.TripCreated)).To(BeTrue())
+				})
+
+			})
+
+			Context("of an unknown kind", func() {
+
+				BeforeEach(func() {
+					optOut = []string{"spam"}
+				})
+
+				It("should return a 400 error", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(HavePrefix("http error (400)"))
+				})
+
+			})
+
+		})
+
 		Context("update a user not associated with firebase", func() {
 
 			Context("requesting user is car share admin", func() {
@@ -436,9 +596,117 @@ var _ = Describe("User Resource", func() {
 				result, err = userResource.Delete(fbUser.GetID(), request)
 			})
 
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should return http status ok", func() {
+				Expect(result.StatusCode()).To(Equal(http.StatusOK))
+			})
+
+			It("should delete the user", func() {
+				_, err := userResource.UserStorage.GetOne(fbUser.GetID(), request.Context)
+				Expect(err).To(Equal(storage.ErrNotFound))
+			})
+
+		})
+
+		Context("firebase user deleting themselves from a car share", func() {
+
+			var (
+				sharedCarShare model.CarShare
+				tripID         string
+			)
+
+			BeforeEach(func() {
+				mockTokenVerifier := mockTokenVerifier{}
+				mockTokenVerifier.Claims = make(jwt.Claims)
+				mockTokenVerifier.Claims.Set("sub", fbUser.FirebaseUID)
+				userResource.TokenVerifier = mockTokenVerifier
+
+				sharedCarShare = model.CarShare{
+					ID:        bson.NewObjectId(),
+					Name:      "Car share of fbUser and fbUser2",
+					AdminIDs:  []string{fbUser.GetID()},
+					MemberIDs: []string{fbUser.GetID(), fbUser2.GetID()},
+				}
+				err := db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(sharedCarShare)
+				Expect(err).ToNot(HaveOccurred())
+				tripID, err = userResource.TripStorage.Insert(model.Trip{
+					CarShareID:   sharedCarShare.GetID(),
+					Metres:       1000,
+					DriverID:     fbUser.GetID(),
+					PassengerIDs: []string{fbUser2.GetID()},
+					Scores: map[string]model.Score{
+						fbUser.GetID():  {MetresAsDriver: 1000, PointsAsDriver: 1000},
+						fbUser2.GetID(): {MetresAsPassenger: 1000, PointsAsPassenger: 1000},
+					},
+				}, request.Context)
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			JustBeforeEach(func() {
+				result, err = userResource.Delete("me", request)
+			})
+
+			It("should not throw an error", func() {
+				Expect(err).ToNot(HaveOccurred())
+			})
+
+			It("should make the other member admin in their place", func() {
+				carShare, err := userResource.CarShareStorage.GetOne(sharedCarShare.GetID(), request.Context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(carShare.MemberIDs).To(Equal([]string{fbUser2.GetID()}))
+				Expect(carShare.AdminIDs).To(Equal([]string{fbUser2.GetID()}))
+			})
+
+			It("should anonymise them in the trip, leaving the other member's score alone", func() {
+				trip, err := userResource.TripStorage.GetOne(tripID, request.Context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(trip.DriverID).ToNot(Equal(fbUser.GetID()))
+				Expect(trip.Scores).ToNot(HaveKey(fbUser.GetID()))
+				Expect(trip.Scores[fbUser2.GetID()].MetresAsPassenger).To(Equal(1000))
+				anonymous, err := userResource.UserStorage.GetOne(trip.DriverID, request.Context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(anonymous.Erased).To(BeTrue())
+				Expect(anonymous.FirebaseUID).To(BeEmpty())
+			})
+
+			Context("other member never signed up", func() {
+
+				BeforeEach(func() {
+					sharedCarShare.MemberIDs = []string{fbUser.GetID(), csLinkedUser.GetID()}
+					Expect(userResource.CarShareStorage.Update(sharedCarShare, request.Context)).To(Succeed())
+				})
+
+				It("should return a 409 error", func() {
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(ContainSubstring("http error (409)"))
+				})
+
+				It("should not delete the user", func() {
+					_, err := userResource.UserStorage.GetOne(fbUser.GetID(), request.Context)
+					Expect(err).ToNot(HaveOccurred())
+				})
+
+			})
+
+		})
+
+		Context("firebase user deleting another firebase user", func() {
+
+			BeforeEach(func() {
+				mockTokenVerifier := mockTokenVerifier{}
+				mockTokenVerifier.Claims = make(jwt.Claims)
+				mockTokenVerifier.Claims.Set("sub", fbUser2.FirebaseUID)
+				userResource.TokenVerifier = mockTokenVerifier
+
+				result, err = userResource.Delete(fbUser.GetID(), request)
+			})
+
 			It("should throw an error", func() {
 				Expect(err).To(HaveOccurred())
-				Expect(err.Error()).To(Equal("http error (403) unable to delete users linked to Firebase and 0 more errors, error deleting user, user " + fbUser.GetID() + " attempting to delete firebase user " + fbUser.GetID()))
+				Expect(err.Error()).To(Equal("http error (403) unable to delete users linked to Firebase and 0 more errors, error deleting user, user " + fbUser2.GetID() + " attempting to delete firebase user " + fbUser.GetID()))
 			})
 
 		})
@@ -0,0 +1,393 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// VehicleResource for api2go routes. Vehicles can be added by any member of their car
+// share but only changed by their owner or an admin.
+type VehicleResource struct {
+	VehicleStorage  storage.VehicleStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+	Events          *events.Bus
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	vehicleFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "vehicle_find_all_duration_seconds",
+		Help: "Time taken to find all vehicles",
+	}, []string{"code"})
+	vehicleFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "vehicle_find_one_duration_seconds",
+		Help: "Time taken to find one vehicle",
+	}, []string{"code"})
+	vehicleCreateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "vehicle_create_duration_seconds",
+		Help: "Time taken to create vehicles",
+	}, []string{"code"})
+	vehicleDeleteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "vehicle_delete_duration_seconds",
+		Help: "Time taken to delete vehicles",
+	}, []string{"code"})
+	vehicleUpdateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "vehicle_update_duration_seconds",
+		Help: "Time taken to update vehicles",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(vehicleFindAllDurationSeconds)
+	prometheus.MustRegister(vehicleFindOneDurationSeconds)
+	prometheus.MustRegister(vehicleCreateDurationSeconds)
+	prometheus.MustRegister(vehicleDeleteDurationSeconds)
+	prometheus.MustRegister(vehicleUpdateDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Vehicles are only listed for a car share,
+// through /carShares/:id/vehicles
+func (v VehicleResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		vehicleFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, v.TokenVerifier, v.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all vehicles not supported"),
+			"vehicles must be found through their car share",
+			code,
+		)
+	}
+	carShareID := r.QueryParams["carSharesID"][0]
+
+	carShare, httpErr, code := findCarShare(v.CarShareStorage, carShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("User %v not member of car share %v", requestingUser.GetID(), carShare.GetID()), http.StatusText(code), code)
+	}
+
+	result, err := v.VehicleStorage.GetAll(carShareID, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving vehicles for car share %s", carShareID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (v VehicleResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		vehicleFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, v.TokenVerifier, v.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	vehicle, httpErr, code := v.vehicle(ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(v.CarShareStorage, vehicle.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %s attempting to access vehicle %s for car share %s they are not a member of", requestingUser.GetID(), vehicle.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to access",
+			code,
+		)
+	}
+
+	code = http.StatusOK
+	return &Response{Res: vehicle, Code: code}, nil
+}
+
+// Create to satisfy api2go.CRUD interface
+func (v VehicleResource) Create(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		vehicleCreateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, v.TokenVerifier, v.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	vehicle, ok := obj.(model.Vehicle)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to vehicle create: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	if vehicle.CarShareID == "" {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to vehicle create (missing carShareID): %v", obj),
+			"must provide a carShareID",
+			code,
+		)
+	}
+
+	carShare, httpErr, code := findCarShare(v.CarShareStorage, vehicle.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsMember(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %v attempting to add vehicle to car share %v they are not a member of", requestingUser.GetID(), carShare.GetID()),
+			"must be a member of associated carshare to add vehicles",
+			code,
+		)
+	}
+
+	if vehicle.OwnerID == "" {
+		vehicle.OwnerID = requestingUser.GetID()
+	}
+
+	httpErr, code = verifyVehicle(vehicle, carShare)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	id, err := v.VehicleStorage.Insert(vehicle, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while persisting vehicle"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	vehicle.SetID(id)
+	publish(v.Events, vehicle.CarShareID, events.Created, vehicle)
+
+	code = http.StatusCreated
+	return &Response{Res: vehicle, Code: code}, nil
+}
+
+// Delete to satisfy the api2go.CRUD interface
+func (v VehicleResource) Delete(id string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		vehicleDeleteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, v.TokenVerifier, v.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	vehicle, httpErr, code := v.vehicle(id, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(v.CarShareStorage, vehicle.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if vehicle.OwnerID != requestingUser.GetID() && !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %v attempting to delete vehicle %v owned by %v without being an admin", requestingUser.GetID(), vehicle.GetID(), vehicle.OwnerID),
+			"only the owner of a vehicle or an admin can delete it",
+			code,
+		)
+	}
+
+	// trips keep their reference to the vehicle, and the cost they were priced at
+	err = v.VehicleStorage.Delete(id, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find vehicle %s to delete", id), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting vehicle %s", id)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(v.Events, vehicle.CarShareID, events.Deleted, vehicle)
+
+	code = http.StatusOK
+	return &Response{Code: code}, nil
+}
+
+// Update to satisfy api2go.CRUD interface
+func (v VehicleResource) Update(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		vehicleUpdateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, v.TokenVerifier, v.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	vehicle, ok := obj.(model.Vehicle)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to vehicle update: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	vehicleInDataStore, httpErr, code := v.vehicle(vehicle.GetID(), r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// Prevent vehicles from being re-assigned car shares
+	if vehicleInDataStore.CarShareID != vehicle.CarShareID {
+		errMsg := fmt.Sprintf("vehicle %s already belongs to another car share", vehicle.GetID())
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	carShare, httpErr, code := findCarShare(v.CarShareStorage, vehicle.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// important to check against the vehicle in the data store
+	if vehicleInDataStore.OwnerID != requestingUser.GetID() && !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("user %v attempting to update vehicle %v owned by %v without being an admin", requestingUser.GetID(), vehicle.GetID(), vehicleInDataStore.OwnerID),
+			"only the owner of a vehicle or an admin can update it",
+			code,
+		)
+	}
+
+	httpErr, code = verifyVehicle(vehicle, carShare)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	err = v.VehicleStorage.Update(vehicle, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Unable to find vehicle %s to update", vehicle.GetID()), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while updating vehicle %s", vehicle.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	publish(v.Events, vehicle.CarShareID, events.Updated, vehicle)
+
+	code = http.StatusNoContent
+	return &Response{Res: vehicle, Code: code}, nil
+}
+
+// vehicle retrieves a vehicle, translating storage errors into http errors
+func (v VehicleResource) vehicle(ID string, ctx api2go.APIContexter) (vehicle model.Vehicle, httpErr error, code int) {
+
+	vehicle, err := v.VehicleStorage.GetOne(ID, ctx)
+	switch err {
+	case nil:
+		return vehicle, nil, code
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return vehicle, api2go.NewHTTPError(fmt.Errorf("unable to find vehicle %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving vehicle %s", ID)
+		code = http.StatusInternalServerError
+		return vehicle, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
+
+// verifyVehicle checks a vehicle is valid and that its owner and drivers are members of
+// the car share
+func verifyVehicle(vehicle model.Vehicle, carShare model.CarShare) (httpErr error, code int) {
+
+	err := vehicle.Validate()
+	if err == nil && !carShare.IsMember(vehicle.OwnerID) {
+		err = fmt.Errorf("owner %s is not a member of car share %s", vehicle.OwnerID, carShare.GetID())
+	}
+	for _, driverID := range vehicle.DriverIDs {
+		if err == nil && !carShare.IsMember(driverID) {
+			err = fmt.Errorf("driver %s is not a member of car share %s", driverID, carShare.GetID())
+		}
+	}
+	if err != nil {
+		code = http.StatusBadRequest
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	return nil, code
+}
@@ -0,0 +1,289 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Vehicle Resource", func() {
+
+	var (
+		vehicleResource *VehicleResource
+		request         api2go.Request
+		context         *api2go.APIContext
+		mockVerifier    mockTokenVerifier
+		adminID         = bson.NewObjectId()
+		memberID        = bson.NewObjectId()
+		outsiderID      = bson.NewObjectId()
+		carShareID      = bson.NewObjectId()
+		vehicle1ID      = bson.NewObjectId()
+		result          api2go.Responder
+		err             error
+		asUser          func(firebaseUID string)
+		expectHTTPError func(code int)
+	)
+
+	asUser = func(firebaseUID string) {
+		mockVerifier.Claims.Set("sub", firebaseUID)
+	}
+
+	expectHTTPError = func(code int) {
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+		Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", code)))
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		asUser("memberFirebaseUID")
+		vehicleResource = &VehicleResource{
+			VehicleStorage:  &mongodb.VehicleStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+			&model.User{ID: outsiderID, FirebaseUID: "outsiderFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), memberID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.VehiclesColl).Insert(
+			&model.Vehicle{
+				ID:          vehicle1ID,
+				Name:        "Estate",
+				FuelType:    model.Diesel,
+				Consumption: 5,
+				Seats:       5,
+				CarShareID:  carShareID.Hex(),
+				OwnerID:     adminID.Hex(),
+			},
+		)
+	})
+
+	Describe("get all", func() {
+
+		BeforeEach(func() {
+			request.QueryParams = map[string][]string{"carSharesID": {carShareID.Hex()}}
+		})
+
+		JustBeforeEach(func() {
+			result, err = vehicleResource.FindAll(request)
+		})
+
+		It("should return the vehicles of the car share", func() {
+			Expect(err).ToNot(HaveOccurred())
+			vehicles := result.(*Response).Res.([]model.Vehicle)
+			Expect(vehicles).To(HaveLen(1))
+			Expect(vehicles[0].GetID()).To(Equal(vehicle1ID.Hex()))
+		})
+
+		Context("as a non member", func() {
+
+			BeforeEach(func() {
+				asUser("outsiderFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should return the vehicle to a member", func() {
+			result, err = vehicleResource.FindOne(vehicle1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Vehicle).Name).To(Equal("Estate"))
+		})
+
+		It("should not return the vehicle to a non member", func() {
+			asUser("outsiderFirebaseUID")
+			result, err = vehicleResource.FindOne(vehicle1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+	})
+
+	Describe("create", func() {
+
+		var vehicle model.Vehicle
+
+		BeforeEach(func() {
+			vehicle = model.Vehicle{
+				Name:        "Hatchback",
+				FuelType:    model.Petrol,
+				Consumption: 6.5,
+				WearRate:    3,
+				Seats:       5,
+				CarShareID:  carShareID.Hex(),
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = vehicleResource.Create(vehicle, request)
+		})
+
+		It("should store the vehicle, owned by the requesting member", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Code).To(Equal(http.StatusCreated))
+			stored, err := vehicleResource.VehicleStorage.GetOne(result.(*Response).Res.(model.Vehicle).GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Name).To(Equal("Hatchback"))
+			Expect(stored.OwnerID).To(Equal(memberID.Hex()))
+		})
+
+		Context("with an unknown fuel type", func() {
+
+			BeforeEach(func() {
+				vehicle.FuelType = "steam"
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("without a consumption", func() {
+
+			BeforeEach(func() {
+				vehicle.Consumption = 0
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("without any seats", func() {
+
+			BeforeEach(func() {
+				vehicle.Seats = 0
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("driven by someone who isn't a member", func() {
+
+			BeforeEach(func() {
+				vehicle.DriverIDs = []string{adminID.Hex(), outsiderID.Hex()}
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("owned by someone who isn't a member", func() {
+
+			BeforeEach(func() {
+				vehicle.OwnerID = outsiderID.Hex()
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("as a non member", func() {
+
+			BeforeEach(func() {
+				asUser("outsiderFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+	})
+
+	Describe("update", func() {
+
+		var vehicle model.Vehicle
+
+		BeforeEach(func() {
+			vehicle, err = vehicleResource.VehicleStorage.GetOne(vehicle1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			vehicle.Consumption = 5.5
+		})
+
+		JustBeforeEach(func() {
+			result, err = vehicleResource.Update(vehicle, request)
+		})
+
+		It("should not let a member who doesn't own the vehicle update it", func() {
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		Context("as the owner", func() {
+
+			BeforeEach(func() {
+				asUser("adminFirebaseUID")
+			})
+
+			It("should update the vehicle in the data store", func() {
+				Expect(err).ToNot(HaveOccurred())
+				stored, err := vehicleResource.VehicleStorage.GetOne(vehicle1ID.Hex(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stored.Consumption).To(Equal(5.5))
+			})
+
+		})
+
+	})
+
+	Describe("delete", func() {
+
+		It("should not let a member who doesn't own the vehicle delete it", func() {
+			result, err = vehicleResource.Delete(vehicle1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		It("should delete the vehicle for its owner", func() {
+			asUser("adminFirebaseUID")
+			result, err = vehicleResource.Delete(vehicle1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = vehicleResource.VehicleStorage.GetOne(vehicle1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
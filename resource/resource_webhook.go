@@ -0,0 +1,380 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/firebase-jwt-auth"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// WebhookResource for api2go routes. Webhooks can only be seen and managed by admins of their
+// car share. Their secrets are write only, they are never included in responses.
+type WebhookResource struct {
+	WebhookStorage  storage.WebhookStorage
+	CarShareStorage storage.CarShareStorage
+	UserStorage     storage.UserStorage
+	TokenVerifier   fireauth.TokenVerifier
+}
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	webhookFindAllDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "webhook_find_all_duration_seconds",
+		Help: "Time taken to find all webhooks",
+	}, []string{"code"})
+	webhookFindOneDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "webhook_find_one_duration_seconds",
+		Help: "Time taken to find one webhook",
+	}, []string{"code"})
+	webhookCreateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "webhook_create_duration_seconds",
+		Help: "Time taken to create webhooks",
+	}, []string{"code"})
+	webhookDeleteDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "webhook_delete_duration_seconds",
+		Help: "Time taken to delete webhooks",
+	}, []string{"code"})
+	webhookUpdateDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "webhook_update_duration_seconds",
+		Help: "Time taken to update webhooks",
+	}, []string{"code"})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(webhookFindAllDurationSeconds)
+	prometheus.MustRegister(webhookFindOneDurationSeconds)
+	prometheus.MustRegister(webhookCreateDurationSeconds)
+	prometheus.MustRegister(webhookDeleteDurationSeconds)
+	prometheus.MustRegister(webhookUpdateDurationSeconds)
+
+}
+
+// FindAll to satisfy api2go.FindAll interface. Webhooks are only listed for a car share,
+// through /carShares/:id/webhooks
+func (ws WebhookResource) FindAll(r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		webhookFindAllDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ws.TokenVerifier, ws.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	if len(r.QueryParams["carSharesID"]) == 0 {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Find all webhooks not supported"),
+			"webhooks must be found through their car share",
+			code,
+		)
+	}
+	carShareID := r.QueryParams["carSharesID"][0]
+
+	carShare, httpErr, code := findCarShare(ws.CarShareStorage, carShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to list webhooks of car share %v", requestingUser.GetID(), carShare.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	result, err := ws.WebhookStorage.GetAll(carShareID, r.Context)
+	if err != nil {
+		errMsg := fmt.Sprintf("Error retrieving webhooks for car share %s", carShareID)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	for i := range result {
+		result[i].Secret = ""
+	}
+
+	code = http.StatusOK
+	return &Response{Res: result, Code: code}, nil
+}
+
+// FindOne to satisfy api2go.CRUD interface
+func (ws WebhookResource) FindOne(ID string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		webhookFindOneDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ws.TokenVerifier, ws.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	webhook, httpErr, code := findWebhook(ws.WebhookStorage, ID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(ws.CarShareStorage, webhook.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to access webhook %v", requestingUser.GetID(), webhook.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	webhook.Secret = ""
+
+	code = http.StatusOK
+	return &Response{Res: webhook, Code: code}, nil
+}
+
+// Create to satisfy api2go.CRUD interface
+func (ws WebhookResource) Create(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		webhookCreateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ws.TokenVerifier, ws.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	webhook, ok := obj.(model.Webhook)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to webhook create: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	if webhook.CarShareID == "" {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to webhook create (missing carShareID): %v", obj),
+			"must provide a carShareID",
+			code,
+		)
+	}
+
+	carShare, httpErr, code := findCarShare(ws.CarShareStorage, webhook.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to create webhook for car share %v", requestingUser.GetID(), carShare.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	err = webhook.Validate()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, err.Error(), code)
+	}
+
+	id, err := ws.WebhookStorage.Insert(webhook, r.Context)
+	if err != nil {
+		errMsg := "Error occurred while persisting webhook"
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	webhook.SetID(id)
+	webhook.Secret = ""
+
+	code = http.StatusCreated
+	return &Response{Res: webhook, Code: code}, nil
+}
+
+// Delete to satisfy the api2go.CRUD interface. Deliveries still queued for the webhook are
+// abandoned.
+func (ws WebhookResource) Delete(id string, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		webhookDeleteDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ws.TokenVerifier, ws.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	webhook, httpErr, code := findWebhook(ws.WebhookStorage, id, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	carShare, httpErr, code := findCarShare(ws.CarShareStorage, webhook.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to delete webhook %v", requestingUser.GetID(), webhook.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	err = ws.WebhookStorage.Delete(id, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("unable to find webhook %s to delete", id), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while deleting webhook %s", id)
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	code = http.StatusOK
+	return &Response{Code: code}, nil
+}
+
+// Update to satisfy api2go.CRUD interface. The webhook keeps its secret unless a new one is
+// given.
+func (ws WebhookResource) Update(obj interface{}, r api2go.Request) (api2go.Responder, error) {
+
+	// metrics collection. Need to be careful to capture return code before returning
+	start := time.Now()
+	code := http.StatusInternalServerError
+	defer func() {
+		webhookUpdateDurationSeconds.WithLabelValues(fmt.Sprintf("%d", code)).Observe(time.Since(start).Seconds())
+	}()
+
+	requestingUser, err := getRequestUser(r, ws.TokenVerifier, ws.UserStorage)
+	if err != nil {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(err, http.StatusText(code), code)
+	}
+
+	webhook, ok := obj.(model.Webhook)
+	if !ok {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Invalid instance given to webhook update: %v", obj),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	webhookInDataStore, httpErr, code := findWebhook(ws.WebhookStorage, webhook.GetID(), r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	// Prevent webhooks from being re-assigned car shares
+	if webhookInDataStore.CarShareID != webhook.CarShareID {
+		errMsg := fmt.Sprintf("webhook %s already belongs to another car share", webhook.GetID())
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s", errMsg), errMsg, code)
+	}
+
+	carShare, httpErr, code := findCarShare(ws.CarShareStorage, webhook.CarShareID, r.Context)
+	if httpErr != nil {
+		return &Response{}, httpErr
+	}
+
+	if !carShare.IsAdmin(requestingUser.GetID()) {
+		code = http.StatusForbidden
+		return &Response{}, api2go.NewHTTPError(
+			fmt.Errorf("Non admin user %v attempting to update webhook %v", requestingUser.GetID(), webhook.GetID()),
+			http.StatusText(code),
+			code,
+		)
+	}
+
+	if webhook.Secret == "" {
+		webhook.Secret = webhookInDataStore.Secret
+	}
+
+	err = webhook.Validate()
+	if err != nil {
+		code = http.StatusBadRequest
+		return &Response{}, api2go.NewHTTPError(err, err.Error(), code)
+	}
+
+	err = ws.WebhookStorage.Update(webhook, r.Context)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("Unable to find webhook %s to update", webhook.GetID()), http.StatusText(code), code)
+	default:
+		errMsg := fmt.Sprintf("Error occurred while updating webhook %s", webhook.GetID())
+		code = http.StatusInternalServerError
+		return &Response{}, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code)
+	}
+
+	webhook.Secret = ""
+
+	code = http.StatusNoContent
+	return &Response{Res: webhook, Code: code}, nil
+}
+
+// findWebhook retrieves a webhook, translating storage errors into http errors
+func findWebhook(webhookStorage storage.WebhookStorage, ID string, ctx api2go.APIContexter) (webhook model.Webhook, httpErr error, code int) {
+
+	webhook, err := webhookStorage.GetOne(ID, ctx)
+	switch err {
+	case nil:
+		return webhook, nil, code
+	case storage.ErrNotFound, storage.ErrInvalidID:
+		code = http.StatusNotFound
+		return webhook, api2go.NewHTTPError(fmt.Errorf("unable to find webhook %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving webhook %s", ID)
+		code = http.StatusInternalServerError
+		return webhook, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
@@ -0,0 +1,257 @@
+package resource
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/mongodb"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/jose.v1/jwt"
+	"gopkg.in/mgo.v2/bson"
+)
+
+var _ = Describe("Webhook Resource", func() {
+
+	var (
+		webhookResource  *WebhookResource
+		deliveryResource *DeliveryResource
+		request          api2go.Request
+		context          *api2go.APIContext
+		mockVerifier     mockTokenVerifier
+		adminID          = bson.NewObjectId()
+		memberID         = bson.NewObjectId()
+		carShareID       = bson.NewObjectId()
+		webhook1ID       = bson.NewObjectId()
+		delivery1ID      = bson.NewObjectId()
+		result           api2go.Responder
+		err              error
+		asUser           func(firebaseUID string)
+		expectHTTPError  func(code int)
+	)
+
+	asUser = func(firebaseUID string) {
+		mockVerifier.Claims.Set("sub", firebaseUID)
+	}
+
+	expectHTTPError = func(code int) {
+		Expect(err).To(HaveOccurred())
+		Expect(err).To(BeAssignableToTypeOf(api2go.HTTPError{}))
+		Expect(err.Error()).To(HavePrefix(fmt.Sprintf("http error (%d)", code)))
+	}
+
+	BeforeEach(func() {
+		mockVerifier = mockTokenVerifier{Claims: make(jwt.Claims)}
+		asUser("adminFirebaseUID")
+		webhookResource = &WebhookResource{
+			WebhookStorage:  &mongodb.WebhookStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		deliveryResource = &DeliveryResource{
+			DeliveryStorage: &mongodb.DeliveryStorage{},
+			WebhookStorage:  &mongodb.WebhookStorage{},
+			CarShareStorage: &mongodb.CarShareStorage{},
+			UserStorage:     &mongodb.UserStorage{},
+			TokenVerifier:   mockVerifier,
+		}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = mongodb.ConnectToMongoDB(db, pool, containerResource)
+		Expect(db).ToNot(BeNil())
+		err := db.DB(mongodb.CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		request = api2go.Request{Context: context}
+		db.DB(mongodb.CarShareDB).C(mongodb.UsersColl).Insert(
+			&model.User{ID: adminID, FirebaseUID: "adminFirebaseUID"},
+			&model.User{ID: memberID, FirebaseUID: "memberFirebaseUID"},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.CarSharesColl).Insert(
+			&model.CarShare{
+				ID:        carShareID,
+				MemberIDs: []string{adminID.Hex(), memberID.Hex()},
+				AdminIDs:  []string{adminID.Hex()},
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.WebhooksColl).Insert(
+			&model.Webhook{
+				ID:         webhook1ID,
+				URL:        "https://example.com/hook",
+				Secret:     "shh",
+				Events:     []string{model.TripCreated},
+				CarShareID: carShareID.Hex(),
+			},
+		)
+		db.DB(mongodb.CarShareDB).C(mongodb.DeliveriesColl).Insert(
+			&model.Delivery{
+				ID:        delivery1ID,
+				Event:     model.TripCreated,
+				Status:    model.DeliveryPending,
+				WebhookID: webhook1ID.Hex(),
+			},
+		)
+	})
+
+	Describe("get all", func() {
+
+		BeforeEach(func() {
+			request.QueryParams = map[string][]string{"carSharesID": {carShareID.Hex()}}
+		})
+
+		JustBeforeEach(func() {
+			result, err = webhookResource.FindAll(request)
+		})
+
+		It("should return the webhooks of the car share without their secrets", func() {
+			Expect(err).ToNot(HaveOccurred())
+			webhooks := result.(*Response).Res.([]model.Webhook)
+			Expect(webhooks).To(HaveLen(1))
+			Expect(webhooks[0].GetID()).To(Equal(webhook1ID.Hex()))
+			Expect(webhooks[0].Secret).To(BeEmpty())
+		})
+
+		Context("as a member who isn't an admin", func() {
+
+			BeforeEach(func() {
+				asUser("memberFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+	})
+
+	Describe("create", func() {
+
+		var webhook model.Webhook
+
+		BeforeEach(func() {
+			webhook = model.Webhook{
+				URL:        "https://example.com/another",
+				Secret:     "shh",
+				Events:     []string{model.MemberAdded},
+				CarShareID: carShareID.Hex(),
+			}
+		})
+
+		JustBeforeEach(func() {
+			result, err = webhookResource.Create(webhook, request)
+		})
+
+		It("should create the webhook", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.StatusCode()).To(Equal(http.StatusCreated))
+			Expect(result.(*Response).Res.(model.Webhook).Secret).To(BeEmpty())
+		})
+
+		Context("with a plain http url", func() {
+
+			BeforeEach(func() {
+				webhook.URL = "http://example.com/another"
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("with an unknown event", func() {
+
+			BeforeEach(func() {
+				webhook.Events = []string{"car.crashed"}
+			})
+
+			It("should return a bad request error", func() {
+				expectHTTPError(http.StatusBadRequest)
+			})
+
+		})
+
+		Context("as a member who isn't an admin", func() {
+
+			BeforeEach(func() {
+				asUser("memberFirebaseUID")
+			})
+
+			It("should return a forbidden error", func() {
+				expectHTTPError(http.StatusForbidden)
+			})
+
+		})
+
+	})
+
+	Describe("update", func() {
+
+		It("should keep the secret when none is given", func() {
+			webhook := model.Webhook{
+				ID:         webhook1ID,
+				URL:        "https://example.com/hook",
+				Events:     []string{model.TripCreated, model.TripDeleted},
+				CarShareID: carShareID.Hex(),
+			}
+			result, err = webhookResource.Update(webhook, request)
+			Expect(err).ToNot(HaveOccurred())
+			stored, err := webhookResource.WebhookStorage.GetOne(webhook1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Secret).To(Equal("shh"))
+			Expect(stored.Events).To(HaveLen(2))
+		})
+
+	})
+
+	Describe("delete", func() {
+
+		It("should not let a member who isn't an admin delete the webhook", func() {
+			asUser("memberFirebaseUID")
+			result, err = webhookResource.Delete(webhook1ID.Hex(), request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		It("should delete the webhook", func() {
+			result, err = webhookResource.Delete(webhook1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = webhookResource.FindOne(webhook1ID.Hex(), request)
+			expectHTTPError(http.StatusNotFound)
+		})
+
+	})
+
+	Describe("deliveries", func() {
+
+		BeforeEach(func() {
+			request.QueryParams = map[string][]string{"webhooksID": {webhook1ID.Hex()}}
+		})
+
+		It("should list the deliveries to the webhook", func() {
+			result, err = deliveryResource.FindAll(request)
+			Expect(err).ToNot(HaveOccurred())
+			deliveries := result.(*Response).Res.([]model.Delivery)
+			Expect(deliveries).To(HaveLen(1))
+			Expect(deliveries[0].GetID()).To(Equal(delivery1ID.Hex()))
+		})
+
+		It("should not list them to a member who isn't an admin", func() {
+			asUser("memberFirebaseUID")
+			result, err = deliveryResource.FindAll(request)
+			expectHTTPError(http.StatusForbidden)
+		})
+
+		It("should return a single delivery", func() {
+			result, err = deliveryResource.FindOne(delivery1ID.Hex(), request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.(*Response).Res.(model.Delivery).Event).To(Equal(model.TripCreated))
+		})
+
+	})
+
+})
@@ -1,5 +1,20 @@
 package resource
 
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/push"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/webhook"
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
 // The Response struct implements api2go.Responder
 type Response struct {
 	Res  interface{}
@@ -20,3 +35,155 @@ func (r Response) Result() interface{} {
 func (r Response) StatusCode() int {
 	return r.Code
 }
+
+// writeHTTPError writes a {json:api} error document. Used by handlers that are served
+// outside of api2go and so can't rely on it to render returned errors.
+func writeHTTPError(w http.ResponseWriter, err error, msg string, code int) {
+	log.Errorf("%s, %s", msg, err)
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(api2go.HTTPError{
+		Errors: []api2go.Error{
+			{
+				Status: strconv.Itoa(code),
+				Title:  msg,
+			},
+		},
+	})
+}
+
+// writeError writes an error returned by one of the helpers shared with the {json:api}
+// resources. api2go keeps the message of its errors to itself, so the status text is given.
+func writeError(w http.ResponseWriter, httpErr error, code int) {
+	writeHTTPError(w, httpErr, http.StatusText(code), code)
+}
+
+// writeResource writes a {json:api} document for a single resource
+func writeResource(w http.ResponseWriter, res interface{}, code int) {
+	document, err := jsonapi.Marshal(res)
+	if err != nil {
+		writeHTTPError(w, err, "Error occurred while marshalling response", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/vnd.api+json")
+	w.WriteHeader(code)
+	w.Write(document)
+}
+
+// findCarShare retrieves a car share, translating storage errors into http errors
+func findCarShare(carShareStorage storage.CarShareStorage, ID string, ctx api2go.APIContexter) (carShare model.CarShare, httpErr error, code int) {
+
+	carShare, err := carShareStorage.GetOne(ID, ctx)
+	switch err {
+	case nil:
+		return carShare, nil, code
+	case storage.ErrNotFound:
+		code = http.StatusNotFound
+		return carShare, api2go.NewHTTPError(fmt.Errorf("unable to find car share %s", ID), http.StatusText(code), code), code
+	default:
+		errMsg := fmt.Sprintf("Error occurred while retrieving car share %s", ID)
+		code = http.StatusInternalServerError
+		return carShare, api2go.NewHTTPError(fmt.Errorf("%s, %s", errMsg, err), errMsg, code), code
+	}
+}
+
+// verifyMembers checks that a driver and passengers are all members of the car share,
+// and that the driver isn't also a passenger
+func verifyMembers(driverID string, passengerIDs []string, carShare model.CarShare) (httpErr error, code int) {
+
+	badRequest := func(err error) (error, int) {
+		code = http.StatusBadRequest
+		return api2go.NewHTTPError(err, err.Error(), code), code
+	}
+
+	if driverID != "" && !carShare.IsMember(driverID) {
+		return badRequest(fmt.Errorf("driver %s is not a member of car share %s", driverID, carShare.GetID()))
+	}
+
+	for _, passengerID := range passengerIDs {
+		if passengerID == driverID {
+			return badRequest(fmt.Errorf("passenger %s is set as driver", passengerID))
+		}
+		if !carShare.IsMember(passengerID) {
+			return badRequest(fmt.Errorf("passenger %s is not a member of car share %s", passengerID, carShare.GetID()))
+		}
+	}
+
+	return nil, code
+}
+
+// notify the car share's webhooks of an event. Webhooks are a side effect of the request, so
+// problems queuing deliveries are logged rather than failing it.
+func notify(notifier *webhook.Notifier, carShareID, event string, resource jsonapi.MarshalIdentifier, ctx api2go.APIContexter) {
+	if notifier == nil {
+		return
+	}
+	err := notifier.Notify(carShareID, event, resource, ctx)
+	if err != nil {
+		log.Errorf("Error notifying webhooks of car share %s of %s, %s", carShareID, event, err)
+	}
+}
+
+// notifyMembers notifies the car share's webhooks that each of the users has been added to or
+// removed from it
+func notifyMembers(notifier *webhook.Notifier, userStorage storage.UserStorage, carShareID, event string, userIDs []string, ctx api2go.APIContexter) {
+	if notifier == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		user, err := userStorage.GetOne(userID, ctx)
+		if err != nil {
+			// the user may have been deleted along with their membership
+			user = model.User{}
+			user.SetID(userID)
+		}
+		notify(notifier, carShareID, event, user, ctx)
+	}
+}
+
+// pushEvent to the devices of the car share's members, other than the user who caused it. It is
+// pushed in the background so a slow push service doesn't hold up the response.
+func pushEvent(pusher *push.Pusher, carShareID, event string, resource jsonapi.MarshalIdentifier, actorID string, ctx api2go.APIContexter) {
+	if pusher == nil {
+		return
+	}
+	// api2go resets the request's context once the response is sent, so the push is given its
+	// own holding the same database connection
+	pushCtx := &api2go.APIContext{}
+	if db, ok := ctx.Get("db"); ok {
+		pushCtx.Set("db", db)
+	}
+	go func() {
+		_, err := pusher.Push(carShareID, event, resource, actorID, pushCtx)
+		if err != nil {
+			log.Errorf("Error pushing %s to members of car share %s, %s", event, carShareID, err)
+		}
+	}()
+}
+
+// pushMembers pushes to the devices of the car share's members that each of the users has been
+// added to or removed from it
+func pushMembers(pusher *push.Pusher, userStorage storage.UserStorage, carShareID, event string, userIDs []string, actorID string, ctx api2go.APIContexter) {
+	if pusher == nil {
+		return
+	}
+	for _, userID := range userIDs {
+		user, err := userStorage.GetOne(userID, ctx)
+		if err != nil {
+			user = model.User{}
+			user.SetID(userID)
+		}
+		pushEvent(pusher, carShareID, event, user, actorID, ctx)
+	}
+}
+
+// publish a change to a resource of a car share to anyone streaming its events
+func publish(bus *events.Bus, carShareID, action string, resource jsonapi.MarshalIdentifier) {
+	if bus == nil {
+		return
+	}
+	err := bus.Publish(carShareID, action, resource)
+	if err != nil {
+		log.Errorf("Error publishing change to car share %s, %s", carShareID, err)
+	}
+}
@@ -0,0 +1,66 @@
+package resource
+
+import (
+	"errors"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/push"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// dbCarShareStorage fails lookups made without a database in the context, as the mongodb
+// storage does
+type dbCarShareStorage struct {
+	*memory.CarShareStorage
+}
+
+func (s dbCarShareStorage) GetOne(id string, context api2go.APIContexter) (model.CarShare, error) {
+	if _, ok := context.Get("db"); !ok {
+		return model.CarShare{}, errors.New("no database in context")
+	}
+	return s.CarShareStorage.GetOne(id, context)
+}
+
+var _ = Describe("Push Event", func() {
+
+	It("should push after the request's context has been reset", func() {
+		context := &api2go.APIContext{}
+		context.Set("db", "connection")
+		sender := &push.MemorySender{}
+		carShareStorage := memory.NewCarShareStorage()
+		userStorage := memory.NewUserStorage()
+		deviceStorage := memory.NewDeviceStorage()
+		pusher := &push.Pusher{
+			CarShareStorage: dbCarShareStorage{carShareStorage},
+			UserStorage:     userStorage,
+			DeviceStorage:   deviceStorage,
+			Sender:          sender,
+		}
+
+		driverID, err := userStorage.Insert(model.User{DisplayName: "Driver"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		passengerID, err := userStorage.Insert(model.User{DisplayName: "Passenger"}, context)
+		Expect(err).ToNot(HaveOccurred())
+		carShareID, err := carShareStorage.Insert(model.CarShare{
+			Name:      "Commute",
+			MemberIDs: []string{driverID, passengerID},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = deviceStorage.Insert(model.Device{Token: "passenger-phone", Platform: model.Android, UserID: passengerID}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		trip := model.Trip{Metres: 1000, CarShareID: carShareID, DriverID: driverID}
+		trip.SetID("58c6a1e2a5fe0e2a4c1d6e54")
+
+		pushEvent(pusher, carShareID, model.TripCreated, trip, driverID, context)
+		context.Reset()
+
+		Eventually(sender.Sent).Should(HaveLen(1))
+	})
+
+})
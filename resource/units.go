@@ -0,0 +1,29 @@
+package resource
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// unitParam is the query parameter clients use to request distances in a particular unit
+const unitParam = "unit"
+
+// displayUnit for distances in a response. The unit requested in the query parameters,
+// otherwise the preferred unit of the car share.
+func displayUnit(r api2go.Request, carShare model.CarShare) (model.Unit, error) {
+	if len(r.QueryParams[unitParam]) > 0 {
+		return model.ParseUnit(r.QueryParams[unitParam][0])
+	}
+	return carShare.PreferredUnit(), nil
+}
+
+// distanceChanged reports whether the distance of an updated trip differs from the one
+// presented for the stored trip, which the update is applied over. A trip only changing
+// unit keeps the distance presented in the previous one.
+func distanceChanged(trip, stored model.Trip, carShare model.CarShare, r api2go.Request) bool {
+	unit, err := displayUnit(r, carShare)
+	if err != nil {
+		return true
+	}
+	return trip.Distance != unit.FromMetres(stored.Metres)
+}
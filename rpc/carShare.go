@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"github.com/manyminds/api2go"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ListCarShares the requesting user is a member of
+func (s Server) ListCarShares(ctx context.Context, req *carsharepb.ListCarSharesRequest) (*carsharepb.ListCarSharesResponse, error) {
+	res, err := s.CarShares.FindAll(s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	carShares, _ := res.Result().([]model.CarShare)
+	response := &carsharepb.ListCarSharesResponse{}
+	for _, carShare := range carShares {
+		response.CarShares = append(response.CarShares, carShareMessage(carShare))
+	}
+	return response, nil
+}
+
+// GetCarShare the requesting user is a member of
+func (s Server) GetCarShare(ctx context.Context, req *carsharepb.GetCarShareRequest) (*carsharepb.CarShare, error) {
+	carShare, err := s.findCarShare(req.GetId(), s.request(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return carShareMessage(carShare), nil
+}
+
+// CreateCarShare with the requesting user as a member and admin
+func (s Server) CreateCarShare(ctx context.Context, req *carsharepb.CreateCarShareRequest) (*carsharepb.CarShare, error) {
+	carShare, err := carShareModel(req.GetCarShare())
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.CarShares.Create(carShare, s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return carShareMessage(res.Result().(model.CarShare)), nil
+}
+
+// UpdateCarShare name, unit and scoring
+func (s Server) UpdateCarShare(ctx context.Context, req *carsharepb.UpdateCarShareRequest) (*carsharepb.CarShare, error) {
+	return s.updateCarShare(ctx, req.GetCarShare().GetId(), func(carShare *model.CarShare) {
+		carShare.Name = req.GetCarShare().GetName()
+		carShare.Unit = model.Unit(req.GetCarShare().GetUnit())
+		carShare.Scoring = model.Scoring(req.GetCarShare().GetScoring())
+		carShare.WindowDays = int(req.GetCarShare().GetWindowDays())
+		carShare.HalfLifeDays = int(req.GetCarShare().GetHalfLifeDays())
+	})
+}
+
+// DeleteCarShare along with its trips
+func (s Server) DeleteCarShare(ctx context.Context, req *carsharepb.DeleteCarShareRequest) (*emptypb.Empty, error) {
+	_, err := s.CarShares.Delete(req.GetId(), s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// AddMembers to a car share, ignoring those who already are
+func (s Server) AddMembers(ctx context.Context, req *carsharepb.MembersRequest) (*carsharepb.CarShare, error) {
+	return s.updateCarShare(ctx, req.GetCarShareId(), func(carShare *model.CarShare) {
+		for _, userID := range req.GetUserIds() {
+			if !carShare.IsMember(userID) {
+				carShare.AddToManyIDs("members", []string{userID})
+			}
+		}
+	})
+}
+
+// RemoveMembers from a car share, ignoring those who aren't members
+func (s Server) RemoveMembers(ctx context.Context, req *carsharepb.MembersRequest) (*carsharepb.CarShare, error) {
+	return s.updateCarShare(ctx, req.GetCarShareId(), func(carShare *model.CarShare) {
+		remaining := []string{}
+		for _, memberID := range carShare.MemberIDs {
+			if !contains(req.GetUserIds(), memberID) {
+				remaining = append(remaining, memberID)
+			}
+		}
+		carShare.MemberIDs = remaining
+	})
+}
+
+// updateCarShare as it is found by the requesting user, in the same way a {json:api} client
+// patches one
+func (s Server) updateCarShare(ctx context.Context, id string, change func(carShare *model.CarShare)) (*carsharepb.CarShare, error) {
+
+	r := s.request(ctx)
+
+	carShare, err := s.findCarShare(id, r)
+	if err != nil {
+		return nil, err
+	}
+
+	change(&carShare)
+
+	res, err := s.CarShares.Update(carShare, r)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return carShareMessage(res.Result().(model.CarShare)), nil
+}
+
+func (s Server) findCarShare(id string, r api2go.Request) (model.CarShare, error) {
+	res, err := s.CarShares.FindOne(id, r)
+	if err != nil {
+		return model.CarShare{}, statusOf(err)
+	}
+	return res.Result().(model.CarShare), nil
+}
+
+func contains(IDs []string, ID string) bool {
+	for _, candidate := range IDs {
+		if candidate == ID {
+			return true
+		}
+	}
+	return false
+}
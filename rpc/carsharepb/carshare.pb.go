@@ -0,0 +1,1790 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        v5.27.1
+// source: carsharepb/carshare.proto
+
+// Car shares, their trips, members and scores for services that would rather talk gRPC than
+// {json:api}. Calls are authenticated with the same firebase tokens as the rest of the API, given
+// as "authorization" metadata, and users see and change the same things they can through it.
+
+package carsharepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// User of the system. Only users looking at themselves see their email
+type User struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id          string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DisplayName string `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Email       string `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	PhotoUrl    string `protobuf:"bytes,4,opt,name=photo_url,json=photoUrl,proto3" json:"photo_url,omitempty"`
+	IsAnon      bool   `protobuf:"varint,5,opt,name=is_anon,json=isAnon,proto3" json:"is_anon,omitempty"`
+	Erased      bool   `protobuf:"varint,6,opt,name=erased,proto3" json:"erased,omitempty"`
+	// car share the user was created for, until they sign up
+	LinkedCarShareId string `protobuf:"bytes,7,opt,name=linked_car_share_id,json=linkedCarShareId,proto3" json:"linked_car_share_id,omitempty"`
+}
+
+func (x *User) Reset() {
+	*x = User{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *User) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*User) ProtoMessage() {}
+
+func (x *User) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use User.ProtoReflect.Descriptor instead.
+func (*User) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *User) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *User) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *User) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *User) GetPhotoUrl() string {
+	if x != nil {
+		return x.PhotoUrl
+	}
+	return ""
+}
+
+func (x *User) GetIsAnon() bool {
+	if x != nil {
+		return x.IsAnon
+	}
+	return false
+}
+
+func (x *User) GetErased() bool {
+	if x != nil {
+		return x.Erased
+	}
+	return false
+}
+
+func (x *User) GetLinkedCarShareId() string {
+	if x != nil {
+		return x.LinkedCarShareId
+	}
+	return ""
+}
+
+// Score of a member of a car share as of a trip. Distances are in metres and costs in the
+// smallest unit of currency
+type Score struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserId            string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MetresAsDriver    int64  `protobuf:"varint,2,opt,name=metres_as_driver,json=metresAsDriver,proto3" json:"metres_as_driver,omitempty"`
+	MetresAsPassenger int64  `protobuf:"varint,3,opt,name=metres_as_passenger,json=metresAsPassenger,proto3" json:"metres_as_passenger,omitempty"`
+	PointsAsDriver    int64  `protobuf:"varint,4,opt,name=points_as_driver,json=pointsAsDriver,proto3" json:"points_as_driver,omitempty"`
+	PointsAsPassenger int64  `protobuf:"varint,5,opt,name=points_as_passenger,json=pointsAsPassenger,proto3" json:"points_as_passenger,omitempty"`
+	CostAsDriver      int64  `protobuf:"varint,6,opt,name=cost_as_driver,json=costAsDriver,proto3" json:"cost_as_driver,omitempty"`
+	CostAsPassenger   int64  `protobuf:"varint,7,opt,name=cost_as_passenger,json=costAsPassenger,proto3" json:"cost_as_passenger,omitempty"`
+}
+
+func (x *Score) Reset() {
+	*x = Score{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Score) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Score) ProtoMessage() {}
+
+func (x *Score) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Score.ProtoReflect.Descriptor instead.
+func (*Score) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Score) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Score) GetMetresAsDriver() int64 {
+	if x != nil {
+		return x.MetresAsDriver
+	}
+	return 0
+}
+
+func (x *Score) GetMetresAsPassenger() int64 {
+	if x != nil {
+		return x.MetresAsPassenger
+	}
+	return 0
+}
+
+func (x *Score) GetPointsAsDriver() int64 {
+	if x != nil {
+		return x.PointsAsDriver
+	}
+	return 0
+}
+
+func (x *Score) GetPointsAsPassenger() int64 {
+	if x != nil {
+		return x.PointsAsPassenger
+	}
+	return 0
+}
+
+func (x *Score) GetCostAsDriver() int64 {
+	if x != nil {
+		return x.CostAsDriver
+	}
+	return 0
+}
+
+func (x *Score) GetCostAsPassenger() int64 {
+	if x != nil {
+		return x.CostAsPassenger
+	}
+	return 0
+}
+
+type CarShare struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// km or miles
+	Unit string `protobuf:"bytes,3,opt,name=unit,proto3" json:"unit,omitempty"`
+	// distance, passenger-distance, trips or short-trip-discount
+	Scoring string `protobuf:"bytes,4,opt,name=scoring,proto3" json:"scoring,omitempty"`
+	// scores are windowed over the last window_days, or weighted with a half life of
+	// half_life_days, when given
+	WindowDays   int32    `protobuf:"varint,5,opt,name=window_days,json=windowDays,proto3" json:"window_days,omitempty"`
+	HalfLifeDays int32    `protobuf:"varint,6,opt,name=half_life_days,json=halfLifeDays,proto3" json:"half_life_days,omitempty"`
+	AdminIds     []string `protobuf:"bytes,7,rep,name=admin_ids,json=adminIds,proto3" json:"admin_ids,omitempty"`
+	MemberIds    []string `protobuf:"bytes,8,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	TripIds      []string `protobuf:"bytes,9,rep,name=trip_ids,json=tripIds,proto3" json:"trip_ids,omitempty"`
+}
+
+func (x *CarShare) Reset() {
+	*x = CarShare{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CarShare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CarShare) ProtoMessage() {}
+
+func (x *CarShare) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CarShare.ProtoReflect.Descriptor instead.
+func (*CarShare) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CarShare) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CarShare) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CarShare) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *CarShare) GetScoring() string {
+	if x != nil {
+		return x.Scoring
+	}
+	return ""
+}
+
+func (x *CarShare) GetWindowDays() int32 {
+	if x != nil {
+		return x.WindowDays
+	}
+	return 0
+}
+
+func (x *CarShare) GetHalfLifeDays() int32 {
+	if x != nil {
+		return x.HalfLifeDays
+	}
+	return 0
+}
+
+func (x *CarShare) GetAdminIds() []string {
+	if x != nil {
+		return x.AdminIds
+	}
+	return nil
+}
+
+func (x *CarShare) GetMemberIds() []string {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+func (x *CarShare) GetTripIds() []string {
+	if x != nil {
+		return x.TripIds
+	}
+	return nil
+}
+
+type Trip struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	CarShareId string                 `protobuf:"bytes,2,opt,name=car_share_id,json=carShareId,proto3" json:"car_share_id,omitempty"`
+	Timestamp  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Metres     int64                  `protobuf:"varint,4,opt,name=metres,proto3" json:"metres,omitempty"`
+	// draft, confirmed or disputed
+	Status       string   `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	DriverId     string   `protobuf:"bytes,6,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	PassengerIds []string `protobuf:"bytes,7,rep,name=passenger_ids,json=passengerIds,proto3" json:"passenger_ids,omitempty"`
+	RouteId      string   `protobuf:"bytes,8,opt,name=route_id,json=routeId,proto3" json:"route_id,omitempty"`
+	VehicleId    string   `protobuf:"bytes,9,opt,name=vehicle_id,json=vehicleId,proto3" json:"vehicle_id,omitempty"`
+	// cost of the trip and the contribution of each passenger towards it, in the smallest unit of
+	// currency
+	Cost         int64 `protobuf:"varint,10,opt,name=cost,proto3" json:"cost,omitempty"`
+	Contribution int64 `protobuf:"varint,11,opt,name=contribution,proto3" json:"contribution,omitempty"`
+	// of every member as of the trip
+	Scores []*Score `protobuf:"bytes,12,rep,name=scores,proto3" json:"scores,omitempty"`
+}
+
+func (x *Trip) Reset() {
+	*x = Trip{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Trip) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trip) ProtoMessage() {}
+
+func (x *Trip) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trip.ProtoReflect.Descriptor instead.
+func (*Trip) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Trip) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Trip) GetCarShareId() string {
+	if x != nil {
+		return x.CarShareId
+	}
+	return ""
+}
+
+func (x *Trip) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Trip) GetMetres() int64 {
+	if x != nil {
+		return x.Metres
+	}
+	return 0
+}
+
+func (x *Trip) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Trip) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *Trip) GetPassengerIds() []string {
+	if x != nil {
+		return x.PassengerIds
+	}
+	return nil
+}
+
+func (x *Trip) GetRouteId() string {
+	if x != nil {
+		return x.RouteId
+	}
+	return ""
+}
+
+func (x *Trip) GetVehicleId() string {
+	if x != nil {
+		return x.VehicleId
+	}
+	return ""
+}
+
+func (x *Trip) GetCost() int64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *Trip) GetContribution() int64 {
+	if x != nil {
+		return x.Contribution
+	}
+	return 0
+}
+
+func (x *Trip) GetScores() []*Score {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+type GetUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *CreateUserRequest) Reset() {
+	*x = CreateUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserRequest) ProtoMessage() {}
+
+func (x *CreateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserRequest.ProtoReflect.Descriptor instead.
+func (*CreateUserRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type UpdateUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+}
+
+func (x *UpdateUserRequest) Reset() {
+	*x = UpdateUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserRequest) ProtoMessage() {}
+
+func (x *UpdateUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateUserRequest) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+type DeleteUserRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteUserRequest) Reset() {
+	*x = DeleteUserRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserRequest) ProtoMessage() {}
+
+func (x *DeleteUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserRequest.ProtoReflect.Descriptor instead.
+func (*DeleteUserRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteUserRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListCarSharesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *ListCarSharesRequest) Reset() {
+	*x = ListCarSharesRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCarSharesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarSharesRequest) ProtoMessage() {}
+
+func (x *ListCarSharesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarSharesRequest.ProtoReflect.Descriptor instead.
+func (*ListCarSharesRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{8}
+}
+
+type ListCarSharesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CarShares []*CarShare `protobuf:"bytes,1,rep,name=car_shares,json=carShares,proto3" json:"car_shares,omitempty"`
+}
+
+func (x *ListCarSharesResponse) Reset() {
+	*x = ListCarSharesResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListCarSharesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCarSharesResponse) ProtoMessage() {}
+
+func (x *ListCarSharesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCarSharesResponse.ProtoReflect.Descriptor instead.
+func (*ListCarSharesResponse) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListCarSharesResponse) GetCarShares() []*CarShare {
+	if x != nil {
+		return x.CarShares
+	}
+	return nil
+}
+
+type GetCarShareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetCarShareRequest) Reset() {
+	*x = GetCarShareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetCarShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCarShareRequest) ProtoMessage() {}
+
+func (x *GetCarShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCarShareRequest.ProtoReflect.Descriptor instead.
+func (*GetCarShareRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetCarShareRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateCarShareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CarShare *CarShare `protobuf:"bytes,1,opt,name=car_share,json=carShare,proto3" json:"car_share,omitempty"`
+}
+
+func (x *CreateCarShareRequest) Reset() {
+	*x = CreateCarShareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateCarShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCarShareRequest) ProtoMessage() {}
+
+func (x *CreateCarShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCarShareRequest.ProtoReflect.Descriptor instead.
+func (*CreateCarShareRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateCarShareRequest) GetCarShare() *CarShare {
+	if x != nil {
+		return x.CarShare
+	}
+	return nil
+}
+
+type UpdateCarShareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CarShare *CarShare `protobuf:"bytes,1,opt,name=car_share,json=carShare,proto3" json:"car_share,omitempty"`
+}
+
+func (x *UpdateCarShareRequest) Reset() {
+	*x = UpdateCarShareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateCarShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCarShareRequest) ProtoMessage() {}
+
+func (x *UpdateCarShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCarShareRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCarShareRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateCarShareRequest) GetCarShare() *CarShare {
+	if x != nil {
+		return x.CarShare
+	}
+	return nil
+}
+
+type DeleteCarShareRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteCarShareRequest) Reset() {
+	*x = DeleteCarShareRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteCarShareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCarShareRequest) ProtoMessage() {}
+
+func (x *DeleteCarShareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCarShareRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCarShareRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteCarShareRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type MembersRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CarShareId string   `protobuf:"bytes,1,opt,name=car_share_id,json=carShareId,proto3" json:"car_share_id,omitempty"`
+	UserIds    []string `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+}
+
+func (x *MembersRequest) Reset() {
+	*x = MembersRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MembersRequest) ProtoMessage() {}
+
+func (x *MembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MembersRequest.ProtoReflect.Descriptor instead.
+func (*MembersRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MembersRequest) GetCarShareId() string {
+	if x != nil {
+		return x.CarShareId
+	}
+	return ""
+}
+
+func (x *MembersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type GetTripRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *GetTripRequest) Reset() {
+	*x = GetTripRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GetTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTripRequest) ProtoMessage() {}
+
+func (x *GetTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTripRequest.ProtoReflect.Descriptor instead.
+func (*GetTripRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetTripRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CreateTripRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Trip *Trip `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+}
+
+func (x *CreateTripRequest) Reset() {
+	*x = CreateTripRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTripRequest) ProtoMessage() {}
+
+func (x *CreateTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTripRequest.ProtoReflect.Descriptor instead.
+func (*CreateTripRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *CreateTripRequest) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type UpdateTripRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Trip *Trip `protobuf:"bytes,1,opt,name=trip,proto3" json:"trip,omitempty"`
+}
+
+func (x *UpdateTripRequest) Reset() {
+	*x = UpdateTripRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTripRequest) ProtoMessage() {}
+
+func (x *UpdateTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTripRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTripRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *UpdateTripRequest) GetTrip() *Trip {
+	if x != nil {
+		return x.Trip
+	}
+	return nil
+}
+
+type DeleteTripRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteTripRequest) Reset() {
+	*x = DeleteTripRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteTripRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTripRequest) ProtoMessage() {}
+
+func (x *DeleteTripRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTripRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTripRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeleteTripRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type WatchTripsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CarShareId string `protobuf:"bytes,1,opt,name=car_share_id,json=carShareId,proto3" json:"car_share_id,omitempty"`
+}
+
+func (x *WatchTripsRequest) Reset() {
+	*x = WatchTripsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_carsharepb_carshare_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchTripsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTripsRequest) ProtoMessage() {}
+
+func (x *WatchTripsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_carsharepb_carshare_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTripsRequest.ProtoReflect.Descriptor instead.
+func (*WatchTripsRequest) Descriptor() ([]byte, []int) {
+	return file_carsharepb_carshare_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *WatchTripsRequest) GetCarShareId() string {
+	if x != nil {
+		return x.CarShareId
+	}
+	return ""
+}
+
+var File_carsharepb_carshare_proto protoreflect.FileDescriptor
+
+var file_carsharepb_carshare_proto_rawDesc = []byte{
+	0x0a, 0x19, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x70, 0x62, 0x2f, 0x63, 0x61, 0x72,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x63, 0x61, 0x72,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x1a, 0x1b, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x65, 0x6d, 0x70, 0x74, 0x79, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xcc, 0x01, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x68, 0x6f, 0x74,
+	0x6f, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x68, 0x6f,
+	0x74, 0x6f, 0x55, 0x72, 0x6c, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x61, 0x6e, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x69, 0x73, 0x41, 0x6e, 0x6f, 0x6e, 0x12, 0x16,
+	0x0a, 0x06, 0x65, 0x72, 0x61, 0x73, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x65, 0x72, 0x61, 0x73, 0x65, 0x64, 0x12, 0x2d, 0x0a, 0x13, 0x6c, 0x69, 0x6e, 0x6b, 0x65, 0x64,
+	0x5f, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x6c, 0x69, 0x6e, 0x6b, 0x65, 0x64, 0x43, 0x61, 0x72, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x49, 0x64, 0x22, 0xa6, 0x02, 0x0a, 0x05, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12,
+	0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x6d, 0x65, 0x74, 0x72,
+	0x65, 0x73, 0x5f, 0x61, 0x73, 0x5f, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x0e, 0x6d, 0x65, 0x74, 0x72, 0x65, 0x73, 0x41, 0x73, 0x44, 0x72, 0x69, 0x76,
+	0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13, 0x6d, 0x65, 0x74, 0x72, 0x65, 0x73, 0x5f, 0x61, 0x73, 0x5f,
+	0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x11, 0x6d, 0x65, 0x74, 0x72, 0x65, 0x73, 0x41, 0x73, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67,
+	0x65, 0x72, 0x12, 0x28, 0x0a, 0x10, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x73, 0x5f,
+	0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0e, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x73, 0x41, 0x73, 0x44, 0x72, 0x69, 0x76, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x13,
+	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x73, 0x5f, 0x61, 0x73, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e,
+	0x67, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x11, 0x70, 0x6f, 0x69, 0x6e, 0x74,
+	0x73, 0x41, 0x73, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0e,
+	0x63, 0x6f, 0x73, 0x74, 0x5f, 0x61, 0x73, 0x5f, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x6f, 0x73, 0x74, 0x41, 0x73, 0x44, 0x72, 0x69, 0x76,
+	0x65, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x6f, 0x73, 0x74, 0x5f, 0x61, 0x73, 0x5f, 0x70, 0x61,
+	0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x63,
+	0x6f, 0x73, 0x74, 0x41, 0x73, 0x50, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x22, 0xfa,
+	0x01, 0x0a, 0x08, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75,
+	0x6e, 0x69, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x73, 0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a,
+	0x0b, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0a, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x44, 0x61, 0x79, 0x73, 0x12, 0x24,
+	0x0a, 0x0e, 0x68, 0x61, 0x6c, 0x66, 0x5f, 0x6c, 0x69, 0x66, 0x65, 0x5f, 0x64, 0x61, 0x79, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x68, 0x61, 0x6c, 0x66, 0x4c, 0x69, 0x66, 0x65,
+	0x44, 0x61, 0x79, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x69, 0x64,
+	0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x49, 0x64,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18,
+	0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x49, 0x64, 0x73,
+	0x12, 0x19, 0x0a, 0x08, 0x74, 0x72, 0x69, 0x70, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x09, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x74, 0x72, 0x69, 0x70, 0x49, 0x64, 0x73, 0x22, 0x82, 0x03, 0x0a, 0x04,
+	0x54, 0x72, 0x69, 0x70, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x02, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x0c, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x72, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x16, 0x0a, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x06, 0x6d, 0x65, 0x74, 0x72, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x1b, 0x0a, 0x09, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x64, 0x72, 0x69, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x23, 0x0a,
+	0x0d, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x07,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x0c, 0x70, 0x61, 0x73, 0x73, 0x65, 0x6e, 0x67, 0x65, 0x72, 0x49,
+	0x64, 0x73, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x6f, 0x75, 0x74, 0x65, 0x49, 0x64, 0x12, 0x1d, 0x0a,
+	0x0a, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x76, 0x65, 0x68, 0x69, 0x63, 0x6c, 0x65, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x73, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74,
+	0x12, 0x22, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x0c,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e,
+	0x76, 0x30, 0x2e, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73,
+	0x22, 0x20, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x22, 0x3a, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x30, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x3a,
+	0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x16, 0x0a, 0x14, 0x4c, 0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x4d, 0x0a, 0x15, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x34, 0x0a, 0x0a, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e,
+	0x76, 0x30, 0x2e, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x09, 0x63, 0x61, 0x72,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x22, 0x24, 0x0a, 0x12, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72,
+	0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x4b, 0x0a, 0x15,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x32, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52,
+	0x08, 0x63, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x22, 0x4b, 0x0a, 0x15, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x32, 0x0a, 0x09, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x30, 0x2e, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x08, 0x63, 0x61,
+	0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x22, 0x27, 0x0a, 0x15, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22,
+	0x4d, 0x0a, 0x0e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x20, 0x0a, 0x0c, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73, 0x22, 0x20,
+	0x0a, 0x0e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
+	0x22, 0x3a, 0x0a, 0x11, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x72, 0x69, 0x70, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x30, 0x2e, 0x54, 0x72, 0x69, 0x70, 0x52, 0x04, 0x74, 0x72, 0x69, 0x70, 0x22, 0x3a, 0x0a, 0x11,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x25, 0x0a, 0x04, 0x74, 0x72, 0x69, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x54, 0x72,
+	0x69, 0x70, 0x52, 0x04, 0x74, 0x72, 0x69, 0x70, 0x22, 0x23, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x35, 0x0a,
+	0x11, 0x57, 0x61, 0x74, 0x63, 0x68, 0x54, 0x72, 0x69, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x20, 0x0a, 0x0c, 0x63, 0x61, 0x72, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x61, 0x72, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x49, 0x64, 0x32, 0xe8, 0x08, 0x0a, 0x0f, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72,
+	0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x47, 0x65, 0x74, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x1b, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x30, 0x2e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x3f, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65,
+	0x72, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x12, 0x3f, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30,
+	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30,
+	0x2e, 0x55, 0x73, 0x65, 0x72, 0x12, 0x44, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55,
+	0x73, 0x65, 0x72, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x30, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x56, 0x0a, 0x0d, 0x4c,
+	0x69, 0x73, 0x74, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x12, 0x21, 0x2e, 0x63,
+	0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x43,
+	0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x22, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x4c, 0x69,
+	0x73, 0x74, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x45, 0x0a, 0x0b, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61,
+	0x72, 0x65, 0x12, 0x1f, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30,
+	0x2e, 0x47, 0x65, 0x74, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x30, 0x2e, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x43, 0x72,
+	0x65, 0x61, 0x74, 0x65, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x22, 0x2e, 0x63,
+	0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x43,
+	0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x4b, 0x0a, 0x0e, 0x55, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x22, 0x2e, 0x63, 0x61, 0x72, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x61,
+	0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x43, 0x61, 0x72, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x12, 0x4c, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x61,
+	0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x22, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72,
+	0x65, 0x2e, 0x76, 0x30, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x61, 0x72, 0x53, 0x68,
+	0x61, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70,
+	0x74, 0x79, 0x12, 0x40, 0x0a, 0x0a, 0x41, 0x64, 0x64, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73,
+	0x12, 0x1b, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x4d,
+	0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x15, 0x2e,
+	0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x43, 0x61, 0x72, 0x53,
+	0x68, 0x61, 0x72, 0x65, 0x12, 0x43, 0x0a, 0x0d, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65,
+	0x2e, 0x76, 0x30, 0x2e, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x15, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30,
+	0x2e, 0x43, 0x61, 0x72, 0x53, 0x68, 0x61, 0x72, 0x65, 0x12, 0x39, 0x0a, 0x07, 0x47, 0x65, 0x74,
+	0x54, 0x72, 0x69, 0x70, 0x12, 0x1b, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e,
+	0x76, 0x30, 0x2e, 0x47, 0x65, 0x74, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e,
+	0x54, 0x72, 0x69, 0x70, 0x12, 0x3f, 0x0a, 0x0a, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x72,
+	0x69, 0x70, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30,
+	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30,
+	0x2e, 0x54, 0x72, 0x69, 0x70, 0x12, 0x3f, 0x0a, 0x0a, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54,
+	0x72, 0x69, 0x70, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x30, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76,
+	0x30, 0x2e, 0x54, 0x72, 0x69, 0x70, 0x12, 0x44, 0x0a, 0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x54, 0x72, 0x69, 0x70, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61, 0x72, 0x65, 0x2e,
+	0x76, 0x30, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x54, 0x72, 0x69, 0x70, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x45, 0x6d, 0x70, 0x74, 0x79, 0x12, 0x41, 0x0a, 0x0a,
+	0x57, 0x61, 0x74, 0x63, 0x68, 0x54, 0x72, 0x69, 0x70, 0x73, 0x12, 0x1e, 0x2e, 0x63, 0x61, 0x72,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x54, 0x72,
+	0x69, 0x70, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x11, 0x2e, 0x63, 0x61, 0x72,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x2e, 0x76, 0x30, 0x2e, 0x54, 0x72, 0x69, 0x70, 0x30, 0x01, 0x42,
+	0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x4c, 0x65,
+	0x77, 0x69, 0x73, 0x57, 0x61, 0x74, 0x73, 0x6f, 0x6e, 0x2f, 0x63, 0x61, 0x72, 0x73, 0x68, 0x61,
+	0x72, 0x65, 0x2d, 0x62, 0x61, 0x63, 0x6b, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x63, 0x61, 0x72, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_carsharepb_carshare_proto_rawDescOnce sync.Once
+	file_carsharepb_carshare_proto_rawDescData = file_carsharepb_carshare_proto_rawDesc
+)
+
+func file_carsharepb_carshare_proto_rawDescGZIP() []byte {
+	file_carsharepb_carshare_proto_rawDescOnce.Do(func() {
+		file_carsharepb_carshare_proto_rawDescData = protoimpl.X.CompressGZIP(file_carsharepb_carshare_proto_rawDescData)
+	})
+	return file_carsharepb_carshare_proto_rawDescData
+}
+
+var file_carsharepb_carshare_proto_msgTypes = make([]protoimpl.MessageInfo, 20)
+var file_carsharepb_carshare_proto_goTypes = []any{
+	(*User)(nil),                  // 0: carshare.v0.User
+	(*Score)(nil),                 // 1: carshare.v0.Score
+	(*CarShare)(nil),              // 2: carshare.v0.CarShare
+	(*Trip)(nil),                  // 3: carshare.v0.Trip
+	(*GetUserRequest)(nil),        // 4: carshare.v0.GetUserRequest
+	(*CreateUserRequest)(nil),     // 5: carshare.v0.CreateUserRequest
+	(*UpdateUserRequest)(nil),     // 6: carshare.v0.UpdateUserRequest
+	(*DeleteUserRequest)(nil),     // 7: carshare.v0.DeleteUserRequest
+	(*ListCarSharesRequest)(nil),  // 8: carshare.v0.ListCarSharesRequest
+	(*ListCarSharesResponse)(nil), // 9: carshare.v0.ListCarSharesResponse
+	(*GetCarShareRequest)(nil),    // 10: carshare.v0.GetCarShareRequest
+	(*CreateCarShareRequest)(nil), // 11: carshare.v0.CreateCarShareRequest
+	(*UpdateCarShareRequest)(nil), // 12: carshare.v0.UpdateCarShareRequest
+	(*DeleteCarShareRequest)(nil), // 13: carshare.v0.DeleteCarShareRequest
+	(*MembersRequest)(nil),        // 14: carshare.v0.MembersRequest
+	(*GetTripRequest)(nil),        // 15: carshare.v0.GetTripRequest
+	(*CreateTripRequest)(nil),     // 16: carshare.v0.CreateTripRequest
+	(*UpdateTripRequest)(nil),     // 17: carshare.v0.UpdateTripRequest
+	(*DeleteTripRequest)(nil),     // 18: carshare.v0.DeleteTripRequest
+	(*WatchTripsRequest)(nil),     // 19: carshare.v0.WatchTripsRequest
+	(*timestamppb.Timestamp)(nil), // 20: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),         // 21: google.protobuf.Empty
+}
+var file_carsharepb_carshare_proto_depIdxs = []int32{
+	20, // 0: carshare.v0.Trip.timestamp:type_name -> google.protobuf.Timestamp
+	1,  // 1: carshare.v0.Trip.scores:type_name -> carshare.v0.Score
+	0,  // 2: carshare.v0.CreateUserRequest.user:type_name -> carshare.v0.User
+	0,  // 3: carshare.v0.UpdateUserRequest.user:type_name -> carshare.v0.User
+	2,  // 4: carshare.v0.ListCarSharesResponse.car_shares:type_name -> carshare.v0.CarShare
+	2,  // 5: carshare.v0.CreateCarShareRequest.car_share:type_name -> carshare.v0.CarShare
+	2,  // 6: carshare.v0.UpdateCarShareRequest.car_share:type_name -> carshare.v0.CarShare
+	3,  // 7: carshare.v0.CreateTripRequest.trip:type_name -> carshare.v0.Trip
+	3,  // 8: carshare.v0.UpdateTripRequest.trip:type_name -> carshare.v0.Trip
+	4,  // 9: carshare.v0.CarShareService.GetUser:input_type -> carshare.v0.GetUserRequest
+	5,  // 10: carshare.v0.CarShareService.CreateUser:input_type -> carshare.v0.CreateUserRequest
+	6,  // 11: carshare.v0.CarShareService.UpdateUser:input_type -> carshare.v0.UpdateUserRequest
+	7,  // 12: carshare.v0.CarShareService.DeleteUser:input_type -> carshare.v0.DeleteUserRequest
+	8,  // 13: carshare.v0.CarShareService.ListCarShares:input_type -> carshare.v0.ListCarSharesRequest
+	10, // 14: carshare.v0.CarShareService.GetCarShare:input_type -> carshare.v0.GetCarShareRequest
+	11, // 15: carshare.v0.CarShareService.CreateCarShare:input_type -> carshare.v0.CreateCarShareRequest
+	12, // 16: carshare.v0.CarShareService.UpdateCarShare:input_type -> carshare.v0.UpdateCarShareRequest
+	13, // 17: carshare.v0.CarShareService.DeleteCarShare:input_type -> carshare.v0.DeleteCarShareRequest
+	14, // 18: carshare.v0.CarShareService.AddMembers:input_type -> carshare.v0.MembersRequest
+	14, // 19: carshare.v0.CarShareService.RemoveMembers:input_type -> carshare.v0.MembersRequest
+	15, // 20: carshare.v0.CarShareService.GetTrip:input_type -> carshare.v0.GetTripRequest
+	16, // 21: carshare.v0.CarShareService.CreateTrip:input_type -> carshare.v0.CreateTripRequest
+	17, // 22: carshare.v0.CarShareService.UpdateTrip:input_type -> carshare.v0.UpdateTripRequest
+	18, // 23: carshare.v0.CarShareService.DeleteTrip:input_type -> carshare.v0.DeleteTripRequest
+	19, // 24: carshare.v0.CarShareService.WatchTrips:input_type -> carshare.v0.WatchTripsRequest
+	0,  // 25: carshare.v0.CarShareService.GetUser:output_type -> carshare.v0.User
+	0,  // 26: carshare.v0.CarShareService.CreateUser:output_type -> carshare.v0.User
+	0,  // 27: carshare.v0.CarShareService.UpdateUser:output_type -> carshare.v0.User
+	21, // 28: carshare.v0.CarShareService.DeleteUser:output_type -> google.protobuf.Empty
+	9,  // 29: carshare.v0.CarShareService.ListCarShares:output_type -> carshare.v0.ListCarSharesResponse
+	2,  // 30: carshare.v0.CarShareService.GetCarShare:output_type -> carshare.v0.CarShare
+	2,  // 31: carshare.v0.CarShareService.CreateCarShare:output_type -> carshare.v0.CarShare
+	2,  // 32: carshare.v0.CarShareService.UpdateCarShare:output_type -> carshare.v0.CarShare
+	21, // 33: carshare.v0.CarShareService.DeleteCarShare:output_type -> google.protobuf.Empty
+	2,  // 34: carshare.v0.CarShareService.AddMembers:output_type -> carshare.v0.CarShare
+	2,  // 35: carshare.v0.CarShareService.RemoveMembers:output_type -> carshare.v0.CarShare
+	3,  // 36: carshare.v0.CarShareService.GetTrip:output_type -> carshare.v0.Trip
+	3,  // 37: carshare.v0.CarShareService.CreateTrip:output_type -> carshare.v0.Trip
+	3,  // 38: carshare.v0.CarShareService.UpdateTrip:output_type -> carshare.v0.Trip
+	21, // 39: carshare.v0.CarShareService.DeleteTrip:output_type -> google.protobuf.Empty
+	3,  // 40: carshare.v0.CarShareService.WatchTrips:output_type -> carshare.v0.Trip
+	25, // [25:41] is the sub-list for method output_type
+	9,  // [9:25] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_carsharepb_carshare_proto_init() }
+func file_carsharepb_carshare_proto_init() {
+	if File_carsharepb_carshare_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_carsharepb_carshare_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*Score); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*CarShare); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*Trip); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*GetUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[6].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteUserRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*ListCarSharesRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*ListCarSharesResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*GetCarShareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateCarShareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateCarShareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteCarShareRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*MembersRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*GetTripRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateTripRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[17].Exporter = func(v any, i int) any {
+			switch v := v.(*UpdateTripRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*DeleteTripRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_carsharepb_carshare_proto_msgTypes[19].Exporter = func(v any, i int) any {
+			switch v := v.(*WatchTripsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_carsharepb_carshare_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   20,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_carsharepb_carshare_proto_goTypes,
+		DependencyIndexes: file_carsharepb_carshare_proto_depIdxs,
+		MessageInfos:      file_carsharepb_carshare_proto_msgTypes,
+	}.Build()
+	File_carsharepb_carshare_proto = out.File
+	file_carsharepb_carshare_proto_rawDesc = nil
+	file_carsharepb_carshare_proto_goTypes = nil
+	file_carsharepb_carshare_proto_depIdxs = nil
+}
@@ -0,0 +1,726 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: carsharepb/carshare.proto
+
+// Car shares, their trips, members and scores for services that would rather talk gRPC than
+// {json:api}. Calls are authenticated with the same firebase tokens as the rest of the API, given
+// as "authorization" metadata, and users see and change the same things they can through it.
+
+package carsharepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CarShareService_GetUser_FullMethodName        = "/carshare.v0.CarShareService/GetUser"
+	CarShareService_CreateUser_FullMethodName     = "/carshare.v0.CarShareService/CreateUser"
+	CarShareService_UpdateUser_FullMethodName     = "/carshare.v0.CarShareService/UpdateUser"
+	CarShareService_DeleteUser_FullMethodName     = "/carshare.v0.CarShareService/DeleteUser"
+	CarShareService_ListCarShares_FullMethodName  = "/carshare.v0.CarShareService/ListCarShares"
+	CarShareService_GetCarShare_FullMethodName    = "/carshare.v0.CarShareService/GetCarShare"
+	CarShareService_CreateCarShare_FullMethodName = "/carshare.v0.CarShareService/CreateCarShare"
+	CarShareService_UpdateCarShare_FullMethodName = "/carshare.v0.CarShareService/UpdateCarShare"
+	CarShareService_DeleteCarShare_FullMethodName = "/carshare.v0.CarShareService/DeleteCarShare"
+	CarShareService_AddMembers_FullMethodName     = "/carshare.v0.CarShareService/AddMembers"
+	CarShareService_RemoveMembers_FullMethodName  = "/carshare.v0.CarShareService/RemoveMembers"
+	CarShareService_GetTrip_FullMethodName        = "/carshare.v0.CarShareService/GetTrip"
+	CarShareService_CreateTrip_FullMethodName     = "/carshare.v0.CarShareService/CreateTrip"
+	CarShareService_UpdateTrip_FullMethodName     = "/carshare.v0.CarShareService/UpdateTrip"
+	CarShareService_DeleteTrip_FullMethodName     = "/carshare.v0.CarShareService/DeleteTrip"
+	CarShareService_WatchTrips_FullMethodName     = "/carshare.v0.CarShareService/WatchTrips"
+)
+
+// CarShareServiceClient is the client API for CarShareService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CarShareServiceClient interface {
+	// GetUser by id, or "me" for the requesting user
+	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error)
+	// CreateUser linked to a car share, for members who haven't signed up yet
+	CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// UpdateUser display name and photo
+	UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error)
+	// DeleteUser erases the requesting user, or a user linked to a car share they are an admin of
+	DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// ListCarShares the requesting user is a member of
+	ListCarShares(ctx context.Context, in *ListCarSharesRequest, opts ...grpc.CallOption) (*ListCarSharesResponse, error)
+	GetCarShare(ctx context.Context, in *GetCarShareRequest, opts ...grpc.CallOption) (*CarShare, error)
+	// CreateCarShare with the requesting user as a member and admin
+	CreateCarShare(ctx context.Context, in *CreateCarShareRequest, opts ...grpc.CallOption) (*CarShare, error)
+	// UpdateCarShare name, unit and scoring. Only admins can update a car share
+	UpdateCarShare(ctx context.Context, in *UpdateCarShareRequest, opts ...grpc.CallOption) (*CarShare, error)
+	DeleteCarShare(ctx context.Context, in *DeleteCarShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// AddMembers to a car share. Only admins can change the members of a car share
+	AddMembers(ctx context.Context, in *MembersRequest, opts ...grpc.CallOption) (*CarShare, error)
+	// RemoveMembers from a car share. Only admins can change the members of a car share
+	RemoveMembers(ctx context.Context, in *MembersRequest, opts ...grpc.CallOption) (*CarShare, error)
+	GetTrip(ctx context.Context, in *GetTripRequest, opts ...grpc.CallOption) (*Trip, error)
+	// CreateTrip now, scored against the latest trip of its car share
+	CreateTrip(ctx context.Context, in *CreateTripRequest, opts ...grpc.CallOption) (*Trip, error)
+	// UpdateTrip distance, driver, passengers and vehicle
+	UpdateTrip(ctx context.Context, in *UpdateTripRequest, opts ...grpc.CallOption) (*Trip, error)
+	DeleteTrip(ctx context.Context, in *DeleteTripRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// WatchTrips of a car share as they are created, for as long as the requesting user remains a
+	// member of it
+	WatchTrips(ctx context.Context, in *WatchTripsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Trip], error)
+}
+
+type carShareServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCarShareServiceClient(cc grpc.ClientConnInterface) CarShareServiceClient {
+	return &carShareServiceClient{cc}
+}
+
+func (c *carShareServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, CarShareService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) CreateUser(ctx context.Context, in *CreateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, CarShareService_CreateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) UpdateUser(ctx context.Context, in *UpdateUserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, CarShareService_UpdateUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) DeleteUser(ctx context.Context, in *DeleteUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CarShareService_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) ListCarShares(ctx context.Context, in *ListCarSharesRequest, opts ...grpc.CallOption) (*ListCarSharesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCarSharesResponse)
+	err := c.cc.Invoke(ctx, CarShareService_ListCarShares_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) GetCarShare(ctx context.Context, in *GetCarShareRequest, opts ...grpc.CallOption) (*CarShare, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CarShare)
+	err := c.cc.Invoke(ctx, CarShareService_GetCarShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) CreateCarShare(ctx context.Context, in *CreateCarShareRequest, opts ...grpc.CallOption) (*CarShare, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CarShare)
+	err := c.cc.Invoke(ctx, CarShareService_CreateCarShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) UpdateCarShare(ctx context.Context, in *UpdateCarShareRequest, opts ...grpc.CallOption) (*CarShare, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CarShare)
+	err := c.cc.Invoke(ctx, CarShareService_UpdateCarShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) DeleteCarShare(ctx context.Context, in *DeleteCarShareRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CarShareService_DeleteCarShare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) AddMembers(ctx context.Context, in *MembersRequest, opts ...grpc.CallOption) (*CarShare, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CarShare)
+	err := c.cc.Invoke(ctx, CarShareService_AddMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) RemoveMembers(ctx context.Context, in *MembersRequest, opts ...grpc.CallOption) (*CarShare, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CarShare)
+	err := c.cc.Invoke(ctx, CarShareService_RemoveMembers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) GetTrip(ctx context.Context, in *GetTripRequest, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, CarShareService_GetTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) CreateTrip(ctx context.Context, in *CreateTripRequest, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, CarShareService_CreateTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) UpdateTrip(ctx context.Context, in *UpdateTripRequest, opts ...grpc.CallOption) (*Trip, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trip)
+	err := c.cc.Invoke(ctx, CarShareService_UpdateTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) DeleteTrip(ctx context.Context, in *DeleteTripRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CarShareService_DeleteTrip_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *carShareServiceClient) WatchTrips(ctx context.Context, in *WatchTripsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Trip], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CarShareService_ServiceDesc.Streams[0], CarShareService_WatchTrips_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchTripsRequest, Trip]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CarShareService_WatchTripsClient = grpc.ServerStreamingClient[Trip]
+
+// CarShareServiceServer is the server API for CarShareService service.
+// All implementations must embed UnimplementedCarShareServiceServer
+// for forward compatibility.
+type CarShareServiceServer interface {
+	// GetUser by id, or "me" for the requesting user
+	GetUser(context.Context, *GetUserRequest) (*User, error)
+	// CreateUser linked to a car share, for members who haven't signed up yet
+	CreateUser(context.Context, *CreateUserRequest) (*User, error)
+	// UpdateUser display name and photo
+	UpdateUser(context.Context, *UpdateUserRequest) (*User, error)
+	// DeleteUser erases the requesting user, or a user linked to a car share they are an admin of
+	DeleteUser(context.Context, *DeleteUserRequest) (*emptypb.Empty, error)
+	// ListCarShares the requesting user is a member of
+	ListCarShares(context.Context, *ListCarSharesRequest) (*ListCarSharesResponse, error)
+	GetCarShare(context.Context, *GetCarShareRequest) (*CarShare, error)
+	// CreateCarShare with the requesting user as a member and admin
+	CreateCarShare(context.Context, *CreateCarShareRequest) (*CarShare, error)
+	// UpdateCarShare name, unit and scoring. Only admins can update a car share
+	UpdateCarShare(context.Context, *UpdateCarShareRequest) (*CarShare, error)
+	DeleteCarShare(context.Context, *DeleteCarShareRequest) (*emptypb.Empty, error)
+	// AddMembers to a car share. Only admins can change the members of a car share
+	AddMembers(context.Context, *MembersRequest) (*CarShare, error)
+	// RemoveMembers from a car share. Only admins can change the members of a car share
+	RemoveMembers(context.Context, *MembersRequest) (*CarShare, error)
+	GetTrip(context.Context, *GetTripRequest) (*Trip, error)
+	// CreateTrip now, scored against the latest trip of its car share
+	CreateTrip(context.Context, *CreateTripRequest) (*Trip, error)
+	// UpdateTrip distance, driver, passengers and vehicle
+	UpdateTrip(context.Context, *UpdateTripRequest) (*Trip, error)
+	DeleteTrip(context.Context, *DeleteTripRequest) (*emptypb.Empty, error)
+	// WatchTrips of a car share as they are created, for as long as the requesting user remains a
+	// member of it
+	WatchTrips(*WatchTripsRequest, grpc.ServerStreamingServer[Trip]) error
+	mustEmbedUnimplementedCarShareServiceServer()
+}
+
+// UnimplementedCarShareServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCarShareServiceServer struct{}
+
+func (UnimplementedCarShareServiceServer) GetUser(context.Context, *GetUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedCarShareServiceServer) CreateUser(context.Context, *CreateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateUser not implemented")
+}
+func (UnimplementedCarShareServiceServer) UpdateUser(context.Context, *UpdateUserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateUser not implemented")
+}
+func (UnimplementedCarShareServiceServer) DeleteUser(context.Context, *DeleteUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedCarShareServiceServer) ListCarShares(context.Context, *ListCarSharesRequest) (*ListCarSharesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCarShares not implemented")
+}
+func (UnimplementedCarShareServiceServer) GetCarShare(context.Context, *GetCarShareRequest) (*CarShare, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCarShare not implemented")
+}
+func (UnimplementedCarShareServiceServer) CreateCarShare(context.Context, *CreateCarShareRequest) (*CarShare, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCarShare not implemented")
+}
+func (UnimplementedCarShareServiceServer) UpdateCarShare(context.Context, *UpdateCarShareRequest) (*CarShare, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCarShare not implemented")
+}
+func (UnimplementedCarShareServiceServer) DeleteCarShare(context.Context, *DeleteCarShareRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCarShare not implemented")
+}
+func (UnimplementedCarShareServiceServer) AddMembers(context.Context, *MembersRequest) (*CarShare, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddMembers not implemented")
+}
+func (UnimplementedCarShareServiceServer) RemoveMembers(context.Context, *MembersRequest) (*CarShare, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveMembers not implemented")
+}
+func (UnimplementedCarShareServiceServer) GetTrip(context.Context, *GetTripRequest) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrip not implemented")
+}
+func (UnimplementedCarShareServiceServer) CreateTrip(context.Context, *CreateTripRequest) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTrip not implemented")
+}
+func (UnimplementedCarShareServiceServer) UpdateTrip(context.Context, *UpdateTripRequest) (*Trip, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTrip not implemented")
+}
+func (UnimplementedCarShareServiceServer) DeleteTrip(context.Context, *DeleteTripRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTrip not implemented")
+}
+func (UnimplementedCarShareServiceServer) WatchTrips(*WatchTripsRequest, grpc.ServerStreamingServer[Trip]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchTrips not implemented")
+}
+func (UnimplementedCarShareServiceServer) mustEmbedUnimplementedCarShareServiceServer() {}
+func (UnimplementedCarShareServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeCarShareServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CarShareServiceServer will
+// result in compilation errors.
+type UnsafeCarShareServiceServer interface {
+	mustEmbedUnimplementedCarShareServiceServer()
+}
+
+func RegisterCarShareServiceServer(s grpc.ServiceRegistrar, srv CarShareServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCarShareServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CarShareService_ServiceDesc, srv)
+}
+
+func _CarShareService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).GetUser(ctx, req.(*GetUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_CreateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).CreateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_CreateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).CreateUser(ctx, req.(*CreateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_UpdateUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).UpdateUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_UpdateUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).UpdateUser(ctx, req.(*UpdateUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).DeleteUser(ctx, req.(*DeleteUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_ListCarShares_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCarSharesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).ListCarShares(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_ListCarShares_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).ListCarShares(ctx, req.(*ListCarSharesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_GetCarShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCarShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).GetCarShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_GetCarShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).GetCarShare(ctx, req.(*GetCarShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_CreateCarShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCarShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).CreateCarShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_CreateCarShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).CreateCarShare(ctx, req.(*CreateCarShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_UpdateCarShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCarShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).UpdateCarShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_UpdateCarShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).UpdateCarShare(ctx, req.(*UpdateCarShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_DeleteCarShare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCarShareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).DeleteCarShare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_DeleteCarShare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).DeleteCarShare(ctx, req.(*DeleteCarShareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_AddMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).AddMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_AddMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).AddMembers(ctx, req.(*MembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_RemoveMembers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MembersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).RemoveMembers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_RemoveMembers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).RemoveMembers(ctx, req.(*MembersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_GetTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTripRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).GetTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_GetTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).GetTrip(ctx, req.(*GetTripRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_CreateTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTripRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).CreateTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_CreateTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).CreateTrip(ctx, req.(*CreateTripRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_UpdateTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTripRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).UpdateTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_UpdateTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).UpdateTrip(ctx, req.(*UpdateTripRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_DeleteTrip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTripRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CarShareServiceServer).DeleteTrip(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CarShareService_DeleteTrip_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CarShareServiceServer).DeleteTrip(ctx, req.(*DeleteTripRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CarShareService_WatchTrips_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchTripsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CarShareServiceServer).WatchTrips(m, &grpc.GenericServerStream[WatchTripsRequest, Trip]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CarShareService_WatchTripsServer = grpc.ServerStreamingServer[Trip]
+
+// CarShareService_ServiceDesc is the grpc.ServiceDesc for CarShareService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CarShareService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "carshare.v0.CarShareService",
+	HandlerType: (*CarShareServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUser",
+			Handler:    _CarShareService_GetUser_Handler,
+		},
+		{
+			MethodName: "CreateUser",
+			Handler:    _CarShareService_CreateUser_Handler,
+		},
+		{
+			MethodName: "UpdateUser",
+			Handler:    _CarShareService_UpdateUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _CarShareService_DeleteUser_Handler,
+		},
+		{
+			MethodName: "ListCarShares",
+			Handler:    _CarShareService_ListCarShares_Handler,
+		},
+		{
+			MethodName: "GetCarShare",
+			Handler:    _CarShareService_GetCarShare_Handler,
+		},
+		{
+			MethodName: "CreateCarShare",
+			Handler:    _CarShareService_CreateCarShare_Handler,
+		},
+		{
+			MethodName: "UpdateCarShare",
+			Handler:    _CarShareService_UpdateCarShare_Handler,
+		},
+		{
+			MethodName: "DeleteCarShare",
+			Handler:    _CarShareService_DeleteCarShare_Handler,
+		},
+		{
+			MethodName: "AddMembers",
+			Handler:    _CarShareService_AddMembers_Handler,
+		},
+		{
+			MethodName: "RemoveMembers",
+			Handler:    _CarShareService_RemoveMembers_Handler,
+		},
+		{
+			MethodName: "GetTrip",
+			Handler:    _CarShareService_GetTrip_Handler,
+		},
+		{
+			MethodName: "CreateTrip",
+			Handler:    _CarShareService_CreateTrip_Handler,
+		},
+		{
+			MethodName: "UpdateTrip",
+			Handler:    _CarShareService_UpdateTrip_Handler,
+		},
+		{
+			MethodName: "DeleteTrip",
+			Handler:    _CarShareService_DeleteTrip_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchTrips",
+			Handler:       _CarShareService_WatchTrips_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "carsharepb/carshare.proto",
+}
@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// userMessage of a user found by the user resource, which only includes their email when
+// they are looking at themselves
+func userMessage(res interface{}) *carsharepb.User {
+	switch user := res.(type) {
+	case model.PrivateUser:
+		message := userMessage(user.User)
+		message.Email = user.Email
+		return message
+	case model.User:
+		return &carsharepb.User{
+			Id:               user.GetID(),
+			DisplayName:      user.DisplayName,
+			PhotoUrl:         user.PhotoURL,
+			IsAnon:           user.IsAnon,
+			Erased:           user.Erased,
+			LinkedCarShareId: user.LinkedCarShareID,
+		}
+	default:
+		return &carsharepb.User{}
+	}
+}
+
+func carShareMessage(carShare model.CarShare) *carsharepb.CarShare {
+	return &carsharepb.CarShare{
+		Id:           carShare.GetID(),
+		Name:         carShare.Name,
+		Unit:         string(carShare.Unit),
+		Scoring:      string(carShare.Scoring),
+		WindowDays:   int32(carShare.WindowDays),
+		HalfLifeDays: int32(carShare.HalfLifeDays),
+		AdminIds:     carShare.AdminIDs,
+		MemberIds:    carShare.MemberIDs,
+		TripIds:      carShare.TripIDs,
+	}
+}
+
+// carShareModel of a car share given by the caller
+func carShareModel(message *carsharepb.CarShare) (model.CarShare, error) {
+	carShare := model.CarShare{
+		Name:         message.GetName(),
+		Unit:         model.Unit(message.GetUnit()),
+		Scoring:      model.Scoring(message.GetScoring()),
+		WindowDays:   int(message.GetWindowDays()),
+		HalfLifeDays: int(message.GetHalfLifeDays()),
+		AdminIDs:     message.GetAdminIds(),
+		MemberIDs:    message.GetMemberIds(),
+	}
+	err := carShare.SetID(message.GetId())
+	if err != nil {
+		return carShare, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return carShare, nil
+}
+
+func tripMessage(trip model.Trip) *carsharepb.Trip {
+	message := &carsharepb.Trip{
+		Id:           trip.GetID(),
+		CarShareId:   trip.CarShareID,
+		Timestamp:    timestamppb.New(trip.TimeStamp),
+		Metres:       int64(trip.Metres),
+		Status:       string(trip.Status),
+		DriverId:     trip.DriverID,
+		PassengerIds: trip.PassengerIDs,
+		RouteId:      trip.RouteID,
+		VehicleId:    trip.VehicleID,
+		Cost:         int64(trip.Cost),
+		Contribution: int64(trip.Contribution),
+	}
+
+	// in a stable order, as maps have none. Guests aren't users, so their scores are left out
+	userIDs := make([]string, 0, len(trip.Scores))
+	for userID := range trip.Scores {
+		if strings.HasPrefix(userID, model.GuestKeyPrefix) {
+			continue
+		}
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	for _, userID := range userIDs {
+		score := trip.Scores[userID]
+		message.Scores = append(message.Scores, &carsharepb.Score{
+			UserId:            userID,
+			MetresAsDriver:    int64(score.MetresAsDriver),
+			MetresAsPassenger: int64(score.MetresAsPassenger),
+			PointsAsDriver:    int64(score.PointsAsDriver),
+			PointsAsPassenger: int64(score.PointsAsPassenger),
+			CostAsDriver:      int64(score.CostAsDriver),
+			CostAsPassenger:   int64(score.CostAsPassenger),
+		})
+	}
+
+	return message
+}
+
+// tripModel of a trip given by the caller. Timestamps, statuses, costs and scores are worked
+// out by the trip resource rather than taken from the caller.
+func tripModel(message *carsharepb.Trip) (model.Trip, error) {
+	trip := model.Trip{
+		CarShareID:   message.GetCarShareId(),
+		Metres:       int(message.GetMetres()),
+		DriverID:     message.GetDriverId(),
+		PassengerIDs: message.GetPassengerIds(),
+		RouteID:      message.GetRouteId(),
+		VehicleID:    message.GetVehicleId(),
+	}
+	err := trip.SetID(message.GetId())
+	if err != nil {
+		return trip, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return trip, nil
+}
@@ -0,0 +1,7 @@
+package rpc
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("rpc")
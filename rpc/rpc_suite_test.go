@@ -0,0 +1,13 @@
+package rpc
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRPC(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RPC Suite")
+}
@@ -0,0 +1,100 @@
+/*
+Package rpc serves the CarShareService to other services over gRPC. Calls are answered by the
+{json:api} resources, so they are authenticated, authorized and validated the same way and notify
+webhooks, devices and event streams just the same.
+*/
+package rpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative carsharepb/carshare.proto
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/resource"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"github.com/manyminds/api2go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server of the CarShareService
+type Server struct {
+	carsharepb.UnimplementedCarShareServiceServer
+
+	Users     resource.UserResource
+	CarShares resource.CarShareResource
+	Trips     resource.TripResource
+	Events    *events.Bus
+
+	// NewContext the storage is given for each call, e.g. holding the database connection
+	NewContext func() api2go.APIContexter
+}
+
+// request for the resources on behalf of the caller, carrying their authorization token
+func (s Server) request(ctx context.Context) api2go.Request {
+	header := http.Header{}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, token := range md.Get("authorization") {
+			header.Add("Authorization", token)
+		}
+	}
+	plainRequest := (&http.Request{Header: header, URL: &url.URL{}}).WithContext(ctx)
+	var apiContext api2go.APIContexter = &api2go.APIContext{}
+	if s.NewContext != nil {
+		apiContext = s.NewContext()
+	}
+	return api2go.Request{
+		PlainRequest: plainRequest,
+		QueryParams:  url.Values{},
+		Header:       header,
+		Context:      apiContext,
+	}
+}
+
+// httpStatus of an error returned by a resource, with the message it would have given
+// {json:api} clients. api2go keeps both unexported, so they are read from its HTTPError by
+// reflection. Other errors are answered as api2go would, with an internal server error.
+func httpStatus(err error) (code int, msg string) {
+	code = http.StatusInternalServerError
+	msg = http.StatusText(code)
+	httpErr, ok := err.(api2go.HTTPError)
+	if !ok {
+		return code, msg
+	}
+	fields := reflect.ValueOf(httpErr)
+	status, message := fields.FieldByName("status"), fields.FieldByName("msg")
+	if status.Kind() != reflect.Int || message.Kind() != reflect.String {
+		return code, msg
+	}
+	return int(status.Int()), message.String()
+}
+
+// statusOf an error returned by a resource. The error itself, which may say more than the
+// caller should know, is only logged.
+func statusOf(err error) error {
+	code, msg := httpStatus(err)
+	if code >= http.StatusInternalServerError {
+		log.Errorf("error answering call, %s", err)
+	} else {
+		log.Infof("refused call, %s", err)
+	}
+	switch code {
+	case http.StatusBadRequest:
+		return status.Error(codes.InvalidArgument, msg)
+	case http.StatusForbidden:
+		return status.Error(codes.PermissionDenied, msg)
+	case http.StatusNotFound:
+		return status.Error(codes.NotFound, msg)
+	case http.StatusMethodNotAllowed:
+		return status.Error(codes.Unimplemented, msg)
+	case http.StatusConflict:
+		return status.Error(codes.FailedPrecondition, msg)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
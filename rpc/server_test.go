@@ -0,0 +1,387 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/resource"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gopkg.in/jose.v1/jwt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// mockTokenVerifier takes the token to be the firebase UID of the user
+type mockTokenVerifier struct{}
+
+func (mockTokenVerifier) Verify(accessToken string) (userID string, claims jwt.Claims, err error) {
+	if accessToken == "" {
+		return "", nil, errors.New("no token given")
+	}
+	return accessToken, jwt.Claims{"sub": accessToken}, nil
+}
+
+var _ = Describe("Server", func() {
+
+	var (
+		apiContext      *api2go.APIContext
+		carShareStorage *memory.CarShareStorage
+		tripStorage     *memory.TripStorage
+		userStorage     *memory.UserStorage
+		bus             *events.Bus
+		grpcServer      *grpc.Server
+		conn            *grpc.ClientConn
+		client          carsharepb.CarShareServiceClient
+		aliceID         string
+		bobID           string
+		carolID         string
+		commuteID       string
+		err             error
+	)
+
+	// as calls from the user with the given firebase UID
+	as := func(firebaseUID string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", firebaseUID)
+	}
+
+	code := func(err error) codes.Code {
+		return status.Code(err)
+	}
+
+	BeforeEach(func() {
+		apiContext = &api2go.APIContext{}
+		carShareStorage = memory.NewCarShareStorage()
+		tripStorage = memory.NewTripStorage()
+		userStorage = memory.NewUserStorage()
+		bus = events.NewBus(events.DefaultHistory, clock.NewMock())
+		tokenVerifier := mockTokenVerifier{}
+
+		aliceID, err = userStorage.Insert(model.User{FirebaseUID: "alice", DisplayName: "Alice", Email: "alice@example.com"}, apiContext)
+		Expect(err).ToNot(HaveOccurred())
+		bobID, err = userStorage.Insert(model.User{FirebaseUID: "bob", DisplayName: "Bob", Email: "bob@example.com"}, apiContext)
+		Expect(err).ToNot(HaveOccurred())
+		carolID, err = userStorage.Insert(model.User{FirebaseUID: "carol", DisplayName: "Carol"}, apiContext)
+		Expect(err).ToNot(HaveOccurred())
+		commuteID, err = carShareStorage.Insert(model.CarShare{
+			Name:      "Commute",
+			Unit:      model.Kilometres,
+			AdminIDs:  []string{aliceID},
+			MemberIDs: []string{aliceID, bobID},
+		}, apiContext)
+		Expect(err).ToNot(HaveOccurred())
+
+		listener := bufconn.Listen(1024 * 1024)
+		grpcServer = grpc.NewServer()
+		carsharepb.RegisterCarShareServiceServer(grpcServer, Server{
+			Users: resource.UserResource{
+				UserStorage:     userStorage,
+				CarShareStorage: carShareStorage,
+				TripStorage:     tripStorage,
+				TokenVerifier:   tokenVerifier,
+				Events:          bus,
+			},
+			CarShares: resource.CarShareResource{
+				CarShareStorage: carShareStorage,
+				TripStorage:     tripStorage,
+				UserStorage:     userStorage,
+				TokenVerifier:   tokenVerifier,
+				Events:          bus,
+			},
+			Trips: resource.TripResource{
+				TripStorage:     tripStorage,
+				UserStorage:     userStorage,
+				CarShareStorage: carShareStorage,
+				TokenVerifier:   tokenVerifier,
+				Clock:           clock.NewMock(),
+				Events:          bus,
+			},
+			Events: bus,
+		})
+		go grpcServer.Serve(listener)
+
+		conn, err = grpc.NewClient(
+			"passthrough:///bufconn",
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				return listener.DialContext(ctx)
+			}),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		client = carsharepb.NewCarShareServiceClient(conn)
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		grpcServer.Stop()
+	})
+
+	It("should refuse calls without a token", func() {
+		_, err = client.GetUser(context.Background(), &carsharepb.GetUserRequest{Id: "me"})
+		Expect(code(err)).To(Equal(codes.PermissionDenied))
+	})
+
+	Describe("users", func() {
+
+		It("should give users themselves with their email", func() {
+			user, err := client.GetUser(as("alice"), &carsharepb.GetUserRequest{Id: "me"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.GetId()).To(Equal(aliceID))
+			Expect(user.GetEmail()).To(Equal("alice@example.com"))
+		})
+
+		It("should give others they share a car share with without their email", func() {
+			user, err := client.GetUser(as("alice"), &carsharepb.GetUserRequest{Id: bobID})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.GetDisplayName()).To(Equal("Bob"))
+			Expect(user.GetEmail()).To(BeEmpty())
+		})
+
+		It("should not give anyone else", func() {
+			_, err = client.GetUser(as("alice"), &carsharepb.GetUserRequest{Id: carolID})
+			Expect(code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+		It("should update the requesting user", func() {
+			user, err := client.UpdateUser(as("alice"), &carsharepb.UpdateUserRequest{User: &carsharepb.User{Id: aliceID, DisplayName: "Ali"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(user.GetDisplayName()).To(Equal("Ali"))
+			Expect(user.GetEmail()).To(Equal("alice@example.com"))
+			alice, err := userStorage.GetOne(aliceID, apiContext)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(alice.DisplayName).To(Equal("Ali"))
+		})
+
+	})
+
+	Describe("car shares", func() {
+
+		It("should list those of the requesting user", func() {
+			response, err := client.ListCarShares(as("bob"), &carsharepb.ListCarSharesRequest{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.GetCarShares()).To(HaveLen(1))
+			Expect(response.GetCarShares()[0].GetName()).To(Equal("Commute"))
+		})
+
+		It("should only be seen by members", func() {
+			_, err = client.GetCarShare(as("carol"), &carsharepb.GetCarShareRequest{Id: commuteID})
+			Expect(code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+		It("should not be found when they don't exist", func() {
+			_, err = client.GetCarShare(as("alice"), &carsharepb.GetCarShareRequest{Id: "5a0d2c46c09aae1c6ea3e0c4"})
+			Expect(code(err)).To(Equal(codes.NotFound))
+		})
+
+		It("should be created with the requesting user as an admin", func() {
+			carShare, err := client.CreateCarShare(as("carol"), &carsharepb.CreateCarShareRequest{CarShare: &carsharepb.CarShare{Name: "School run", Unit: "miles"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.GetId()).ToNot(BeEmpty())
+			Expect(carShare.GetUnit()).To(Equal("miles"))
+			Expect(carShare.GetAdminIds()).To(ConsistOf(carolID))
+			Expect(carShare.GetMemberIds()).To(ConsistOf(carolID))
+		})
+
+		It("should be validated as they are created", func() {
+			_, err = client.CreateCarShare(as("carol"), &carsharepb.CreateCarShareRequest{CarShare: &carsharepb.CarShare{Name: "School run", Unit: "furlongs"}})
+			Expect(code(err)).To(Equal(codes.InvalidArgument))
+			Expect(status.Convert(err).Message()).To(Equal("unit must be km or miles"))
+		})
+
+		It("should be updated by admins", func() {
+			carShare, err := client.UpdateCarShare(as("alice"), &carsharepb.UpdateCarShareRequest{CarShare: &carsharepb.CarShare{Id: commuteID, Name: "Work", Unit: "km"}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.GetName()).To(Equal("Work"))
+			Expect(carShare.GetMemberIds()).To(ConsistOf(aliceID, bobID))
+		})
+
+		It("should not be updated by anyone else", func() {
+			_, err = client.UpdateCarShare(as("bob"), &carsharepb.UpdateCarShareRequest{CarShare: &carsharepb.CarShare{Id: commuteID, Name: "Bob's"}})
+			Expect(code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+		It("should have members added and removed by admins", func() {
+			carShare, err := client.AddMembers(as("alice"), &carsharepb.MembersRequest{CarShareId: commuteID, UserIds: []string{carolID, bobID}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.GetMemberIds()).To(ConsistOf(aliceID, bobID, carolID))
+			carShare, err = client.RemoveMembers(as("alice"), &carsharepb.MembersRequest{CarShareId: commuteID, UserIds: []string{bobID}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.GetMemberIds()).To(ConsistOf(aliceID, carolID))
+			Expect(carShare.GetAdminIds()).To(ConsistOf(aliceID))
+		})
+
+	})
+
+	Describe("trips", func() {
+
+		var trip *carsharepb.Trip
+
+		BeforeEach(func() {
+			trip, err = client.CreateTrip(as("alice"), &carsharepb.CreateTripRequest{Trip: &carsharepb.Trip{
+				CarShareId:   commuteID,
+				Metres:       16093,
+				DriverId:     aliceID,
+				PassengerIds: []string{bobID},
+			}})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should await confirmation from their passengers", func() {
+			Expect(trip.GetId()).ToNot(BeEmpty())
+			Expect(trip.GetStatus()).To(Equal(string(model.TripDraft)))
+		})
+
+		It("should be scored once confirmed", func() {
+			solo, err := client.CreateTrip(as("alice"), &carsharepb.CreateTripRequest{Trip: &carsharepb.Trip{
+				CarShareId: commuteID,
+				Metres:     8000,
+				DriverId:   aliceID,
+			}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(solo.GetStatus()).To(Equal(string(model.TripConfirmed)))
+			Expect(solo.GetScores()).To(HaveLen(1))
+			Expect(solo.GetScores()[0].GetUserId()).To(Equal(aliceID))
+			Expect(solo.GetScores()[0].GetMetresAsDriver()).To(Equal(int64(8000)))
+		})
+
+		It("should only give the scores of members", func() {
+			id, err := tripStorage.Insert(model.Trip{
+				CarShareID: commuteID,
+				Metres:     8000,
+				Status:     model.TripConfirmed,
+				DriverID:   aliceID,
+				Guests:     []model.Guest{{Name: "Visitor", Counted: true}},
+				Scores: map[string]model.Score{
+					aliceID:                            {MetresAsDriver: 8000},
+					model.Guest{Name: "Visitor"}.Key(): {MetresAsPassenger: 8000},
+				},
+			}, apiContext)
+			Expect(err).ToNot(HaveOccurred())
+			found, err := client.GetTrip(as("alice"), &carsharepb.GetTripRequest{Id: id})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found.GetScores()).To(HaveLen(1))
+			Expect(found.GetScores()[0].GetUserId()).To(Equal(aliceID))
+		})
+
+		It("should only be seen by members of their car share", func() {
+			found, err := client.GetTrip(as("bob"), &carsharepb.GetTripRequest{Id: trip.GetId()})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(found.GetMetres()).To(Equal(int64(16093)))
+			_, err = client.GetTrip(as("carol"), &carsharepb.GetTripRequest{Id: trip.GetId()})
+			Expect(code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+		It("should be updated", func() {
+			trip.Metres = 20000
+			updated, err := client.UpdateTrip(as("alice"), &carsharepb.UpdateTripRequest{Trip: trip})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.GetMetres()).To(Equal(int64(20000)))
+			Expect(updated.GetPassengerIds()).To(ConsistOf(bobID))
+		})
+
+		It("should be deleted", func() {
+			_, err = client.DeleteTrip(as("alice"), &carsharepb.DeleteTripRequest{Id: trip.GetId()})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.GetTrip(as("alice"), &carsharepb.GetTripRequest{Id: trip.GetId()})
+			Expect(code(err)).To(Equal(codes.NotFound))
+		})
+
+	})
+
+	Describe("watching trips", func() {
+
+		var (
+			cancel context.CancelFunc
+			stream carsharepb.CarShareService_WatchTripsClient
+		)
+
+		watch := func(firebaseUID string) {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(as(firebaseUID))
+			stream, err = client.WatchTrips(ctx, &carsharepb.WatchTripsRequest{CarShareId: commuteID})
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		AfterEach(func() {
+			cancel()
+		})
+
+		It("should stream trips as they are created", func() {
+			watch("bob")
+			_, err = stream.Header()
+			Expect(err).ToNot(HaveOccurred())
+
+			created := model.Trip{CarShareID: commuteID, Metres: 8000, DriverID: aliceID}
+			id, err := tripStorage.Insert(created, apiContext)
+			Expect(err).ToNot(HaveOccurred())
+			created.SetID(id)
+			Expect(bus.Publish(commuteID, events.Created, created)).To(Succeed())
+
+			trip, err := stream.Recv()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.GetId()).To(Equal(id))
+			Expect(trip.GetMetres()).To(Equal(int64(8000)))
+		})
+
+		It("should end when the watcher is removed from the car share", func() {
+			watch("bob")
+			_, err = stream.Header()
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = client.RemoveMembers(as("alice"), &carsharepb.MembersRequest{CarShareId: commuteID, UserIds: []string{bobID}})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = stream.Recv()
+			Expect(code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+		It("should not be allowed for anyone else", func() {
+			watch("carol")
+			_, err = stream.Recv()
+			Expect(code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+	})
+
+})
+
+var _ = Describe("Status of errors", func() {
+
+	It("should keep the status and message of resource errors", func() {
+		err := statusOf(api2go.NewHTTPError(errors.New("trip 1 not found"), "unable to find trip", 404))
+		Expect(status.Code(err)).To(Equal(codes.NotFound))
+		Expect(status.Convert(err).Message()).To(Equal("unable to find trip"))
+	})
+
+	It("should map each status the resources give", func() {
+		for code, expected := range map[int]codes.Code{
+			400: codes.InvalidArgument,
+			403: codes.PermissionDenied,
+			405: codes.Unimplemented,
+			409: codes.FailedPrecondition,
+			500: codes.Internal,
+		} {
+			err := statusOf(api2go.NewHTTPError(errors.New("cause"), "message", code))
+			Expect(status.Code(err)).To(Equal(expected))
+		}
+	})
+
+	It("should not give away the cause of other errors", func() {
+		err := statusOf(errors.New("connection to 10.0.0.1 refused"))
+		Expect(status.Code(err)).To(Equal(codes.Internal))
+		Expect(status.Convert(err).Message()).To(Equal("Internal Server Error"))
+	})
+
+})
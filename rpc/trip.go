@@ -0,0 +1,167 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/LewisWatson/carshare-back/events"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"github.com/manyminds/api2go"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+
+	/*
+	 * Metrics we shall be gathering
+	 */
+	tripWatchersOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "grpc_trip_watchers_open",
+		Help: "Number of calls currently watching the trips of a car share",
+	})
+)
+
+func init() {
+
+	/*
+	 * Register metric counters with prometheus
+	 */
+	prometheus.MustRegister(tripWatchersOpen)
+
+}
+
+// GetTrip of a car share the requesting user is a member of
+func (s Server) GetTrip(ctx context.Context, req *carsharepb.GetTripRequest) (*carsharepb.Trip, error) {
+	trip, err := s.findTrip(req.GetId(), s.request(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return tripMessage(trip), nil
+}
+
+// CreateTrip now, scored against the latest trip of its car share
+func (s Server) CreateTrip(ctx context.Context, req *carsharepb.CreateTripRequest) (*carsharepb.Trip, error) {
+	trip, err := tripModel(req.GetTrip())
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.Trips.Create(trip, s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return tripMessage(res.Result().(model.Trip)), nil
+}
+
+// UpdateTrip distance, driver, passengers and vehicle
+func (s Server) UpdateTrip(ctx context.Context, req *carsharepb.UpdateTripRequest) (*carsharepb.Trip, error) {
+
+	r := s.request(ctx)
+
+	trip, err := s.findTrip(req.GetTrip().GetId(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	// the distance was found in the unit of the car share, which would take precedence over
+	// the metres given
+	trip.Distance = 0
+	trip.Unit = ""
+	trip.Formatted = ""
+
+	trip.Metres = int(req.GetTrip().GetMetres())
+	trip.DriverID = req.GetTrip().GetDriverId()
+	trip.PassengerIDs = req.GetTrip().GetPassengerIds()
+	trip.VehicleID = req.GetTrip().GetVehicleId()
+
+	res, err := s.Trips.Update(trip, r)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return tripMessage(res.Result().(model.Trip)), nil
+}
+
+// DeleteTrip, rescoring the trips after it
+func (s Server) DeleteTrip(ctx context.Context, req *carsharepb.DeleteTripRequest) (*emptypb.Empty, error) {
+	_, err := s.Trips.Delete(req.GetId(), s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// WatchTrips of a car share as they are created. The stream ends when the caller goes away,
+// falls too far behind or stops being a member of the car share.
+func (s Server) WatchTrips(req *carsharepb.WatchTripsRequest, stream carsharepb.CarShareService_WatchTripsServer) error {
+
+	r := s.request(stream.Context())
+
+	_, err := s.findCarShare(req.GetCarShareId(), r)
+	if err != nil {
+		return err
+	}
+
+	tripWatchersOpen.Inc()
+	defer tripWatchersOpen.Dec()
+
+	subscription := s.Events.Subscribe(req.GetCarShareId(), "")
+	defer subscription.Close()
+
+	// headers are sent once subscribed, so callers waiting on them know that no trip created
+	// afterwards will be missed
+	err = stream.SendHeader(metadata.MD{})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return status.Error(codes.Unavailable, "fell too far behind the trips of the car share")
+			}
+			switch {
+			case event.Type == "trips."+events.Created:
+				var document struct {
+					Data struct {
+						ID string `json:"id"`
+					} `json:"data"`
+				}
+				err = json.Unmarshal(event.Data, &document)
+				if err != nil {
+					log.Errorf("error reading trip created in car share %s, %s", event.CarShareID, err)
+					continue
+				}
+				// found as the caller, so they only see it if they still can
+				trip, err := s.findTrip(document.Data.ID, r)
+				if err != nil {
+					return err
+				}
+				err = stream.Send(tripMessage(trip))
+				if err != nil {
+					return err
+				}
+			case strings.HasPrefix(event.Type, "carShares."):
+				_, err = s.findCarShare(req.GetCarShareId(), r)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+func (s Server) findTrip(id string, r api2go.Request) (model.Trip, error) {
+	res, err := s.Trips.FindOne(id, r)
+	if err != nil {
+		return model.Trip{}, statusOf(err)
+	}
+	return res.Result().(model.Trip), nil
+}
@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/rpc/carsharepb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// GetUser by id, or "me" for the requesting user
+func (s Server) GetUser(ctx context.Context, req *carsharepb.GetUserRequest) (*carsharepb.User, error) {
+	res, err := s.Users.FindOne(req.GetId(), s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return userMessage(res.Result()), nil
+}
+
+// CreateUser linked to a car share the requesting user is an admin of
+func (s Server) CreateUser(ctx context.Context, req *carsharepb.CreateUserRequest) (*carsharepb.User, error) {
+	user := model.User{
+		DisplayName:      req.GetUser().GetDisplayName(),
+		Email:            req.GetUser().GetEmail(),
+		PhotoURL:         req.GetUser().GetPhotoUrl(),
+		LinkedCarShareID: req.GetUser().GetLinkedCarShareId(),
+	}
+	res, err := s.Users.Create(user, s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return userMessage(res.Result()), nil
+}
+
+// UpdateUser display name and photo
+func (s Server) UpdateUser(ctx context.Context, req *carsharepb.UpdateUserRequest) (*carsharepb.User, error) {
+
+	r := s.request(ctx)
+
+	res, err := s.Users.FindOne(req.GetUser().GetId(), r)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+
+	// users updating themselves are found privately
+	obj := res.Result()
+	switch user := obj.(type) {
+	case model.PrivateUser:
+		user.DisplayName = req.GetUser().GetDisplayName()
+		user.PhotoURL = req.GetUser().GetPhotoUrl()
+		obj = user
+	case model.User:
+		user.DisplayName = req.GetUser().GetDisplayName()
+		user.PhotoURL = req.GetUser().GetPhotoUrl()
+		obj = user
+	}
+
+	_, err = s.Users.Update(obj, r)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return userMessage(obj), nil
+}
+
+// DeleteUser erases the requesting user, or a user linked to a car share they are an admin of
+func (s Server) DeleteUser(ctx context.Context, req *carsharepb.DeleteUserRequest) (*emptypb.Empty, error) {
+	_, err := s.Users.Delete(req.GetId(), s.request(ctx))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &emptypb.Empty{}, nil
+}
@@ -0,0 +1,7 @@
+package scheduler
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("scheduler")
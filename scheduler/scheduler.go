@@ -0,0 +1,141 @@
+/*
+Package scheduler turns the schedules of car shares into draft trips as they fall due.
+*/
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/ledger"
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+)
+
+// Scheduler creates a draft trip for each occurrence of a schedule once its time arrives.
+// Drafts don't count towards scores until a member confirms them.
+type Scheduler struct {
+	ScheduleStorage storage.ScheduleStorage
+	TripStorage     storage.TripStorage
+	CarShareStorage storage.CarShareStorage
+	Clock           clock.Clock
+}
+
+// Run checks for due schedules immediately and then every interval, until stop is closed
+func (s Scheduler) Run(interval time.Duration, ctx api2go.APIContexter, stop <-chan struct{}) {
+	ticker := s.Clock.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		drafts, err := s.Tick(ctx)
+		if err != nil {
+			log.Errorf("Error materialising schedules, %s", err)
+		}
+		if len(drafts) > 0 {
+			log.Infof("created %d draft trips", len(drafts))
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick creates draft trips for every schedule occurrence that has fallen due, returning
+// their IDs. Occurrences missed while the scheduler wasn't running are caught up on.
+// A problem with one schedule doesn't stop the others, the first error is returned.
+func (s Scheduler) Tick(ctx api2go.APIContexter) ([]string, error) {
+
+	now := s.Clock.Now().UTC()
+
+	schedules, err := s.ScheduleStorage.GetDue(now, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving due schedules, %s", err)
+	}
+
+	drafts := []string{}
+	var firstErr error
+	for _, schedule := range schedules {
+		ids, err := s.materialise(schedule, now, ctx)
+		drafts = append(drafts, ids...)
+		if err != nil {
+			log.Errorf("Error materialising schedule %s, %s", schedule.GetID(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return drafts, firstErr
+}
+
+// materialise the occurrences of a schedule up to now
+func (s Scheduler) materialise(schedule model.Schedule, now time.Time, ctx api2go.APIContexter) ([]string, error) {
+
+	carShare, err := s.CarShareStorage.GetOne(schedule.CarShareID, ctx)
+	switch err {
+	case nil:
+		break
+	case storage.ErrNotFound:
+		// the car share has gone, so the schedule goes with it
+		log.Infof("deleting schedule %s for missing car share %s", schedule.GetID(), schedule.CarShareID)
+		return nil, s.ScheduleStorage.Delete(schedule.GetID(), ctx)
+	default:
+		return nil, fmt.Errorf("error retrieving car share %s, %s", schedule.CarShareID, err)
+	}
+
+	latest, err := s.TripStorage.GetLatest(carShare.GetID(), ctx)
+	if err != nil && err != storage.ErrNotFound {
+		return nil, fmt.Errorf("error retrieving latest trip for car share %s, %s", carShare.GetID(), err)
+	}
+
+	// only possible when catching up, but then the draft must be slotted into the history
+	outOfOrder := false
+
+	ids := []string{}
+	for !schedule.Next.After(now) {
+
+		trip := schedule.Draft(schedule.Next)
+
+		// drafts carry the scores of the trip before them
+		for userID, score := range latest.Scores {
+			trip.Scores[userID] = score
+		}
+		if latest.TimeStamp.After(trip.TimeStamp) {
+			outOfOrder = true
+		}
+
+		id, err := s.TripStorage.Insert(trip, ctx)
+		if err != nil {
+			return ids, fmt.Errorf("error inserting draft trip, %s", err)
+		}
+		ids = append(ids, id)
+		carShare.TripIDs = append(carShare.TripIDs, id)
+
+		// moved on straight away so a failure later doesn't repeat the occurrence
+		schedule.Next, err = schedule.NextOccurrence(schedule.Next)
+		if err != nil {
+			return ids, fmt.Errorf("error finding next occurrence, %s", err)
+		}
+		err = s.ScheduleStorage.Update(schedule, ctx)
+		if err != nil {
+			return ids, fmt.Errorf("error updating schedule, %s", err)
+		}
+	}
+
+	err = s.CarShareStorage.Update(carShare, ctx)
+	if err != nil {
+		return ids, fmt.Errorf("error updating car share %s, %s", carShare.GetID(), err)
+	}
+
+	if outOfOrder {
+		err = ledger.Rebuild(carShare, s.TripStorage, ctx)
+		if err != nil {
+			return ids, fmt.Errorf("error rebuilding scores for car share %s, %s", carShare.GetID(), err)
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,183 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduler", func() {
+
+	var (
+		scheduler       Scheduler
+		mockClock       *clock.Mock
+		context         *api2go.APIContext
+		scheduleStorage *memory.ScheduleStorage
+		tripStorage     *memory.TripStorage
+		carShareStorage *memory.CarShareStorage
+		carShareID      string
+		scheduleID      string
+		drafts          []string
+		err             error
+	)
+
+	BeforeEach(func() {
+		mockClock = clock.NewMock()
+		context = &api2go.APIContext{}
+		scheduleStorage = memory.NewScheduleStorage()
+		tripStorage = memory.NewTripStorage()
+		carShareStorage = memory.NewCarShareStorage()
+		scheduler = Scheduler{
+			ScheduleStorage: scheduleStorage,
+			TripStorage:     tripStorage,
+			CarShareStorage: carShareStorage,
+			Clock:           mockClock,
+		}
+
+		carShareID, err = carShareStorage.Insert(model.CarShare{Name: "Commute"}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = tripStorage.Insert(model.Trip{
+			Metres:     1000,
+			TimeStamp:  time.Date(2017, 11, 3, 17, 0, 0, 0, time.UTC),
+			CarShareID: carShareID,
+			DriverID:   "driver",
+			Scores:     map[string]model.Score{"driver": {MetresAsDriver: 1000}},
+		}, context)
+		Expect(err).ToNot(HaveOccurred())
+
+		schedule := model.Schedule{
+			Name:         "Commute",
+			Weekdays:     []string{"monday", "wednesday"},
+			Time:         "08:00",
+			TimeZone:     "Europe/London",
+			Metres:       15000,
+			CarShareID:   carShareID,
+			DriverID:     "driver",
+			PassengerIDs: []string{"passenger"},
+		}
+		schedule.Next, err = schedule.NextOccurrence(time.Date(2017, 11, 5, 12, 0, 0, 0, time.UTC))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(schedule.Next).To(Equal(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC)))
+		scheduleID, err = scheduleStorage.Insert(schedule, context)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Context("before the next occurrence", func() {
+
+		BeforeEach(func() {
+			mockClock.Set(time.Date(2017, 11, 6, 7, 59, 0, 0, time.UTC))
+			drafts, err = scheduler.Tick(context)
+		})
+
+		It("should not create any trips", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drafts).To(BeEmpty())
+		})
+
+	})
+
+	Context("once an occurrence is due", func() {
+
+		BeforeEach(func() {
+			mockClock.Set(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC))
+			drafts, err = scheduler.Tick(context)
+		})
+
+		It("should create a draft trip", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drafts).To(HaveLen(1))
+			trip, err := tripStorage.GetOne(drafts[0], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.Status).To(Equal(model.TripDraft))
+			Expect(trip.TimeStamp).To(Equal(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC)))
+			Expect(trip.Metres).To(Equal(15000))
+			Expect(trip.DriverID).To(Equal("driver"))
+			Expect(trip.PassengerIDs).To(Equal([]string{"passenger"}))
+			Expect(trip.ScheduleID).To(Equal(scheduleID))
+		})
+
+		It("should carry the scores of the previous trip without counting the draft", func() {
+			trip, err := tripStorage.GetOne(drafts[0], context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(trip.Scores).To(Equal(map[string]model.Score{"driver": {MetresAsDriver: 1000}}))
+		})
+
+		It("should add the draft to the car share", func() {
+			carShare, err := carShareStorage.GetOne(carShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(carShare.TripIDs).To(Equal(drafts))
+		})
+
+		It("should move the schedule on to the next occurrence", func() {
+			schedule, err := scheduleStorage.GetOne(scheduleID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedule.Next).To(Equal(time.Date(2017, 11, 8, 8, 0, 0, 0, time.UTC)))
+		})
+
+		It("should not create the same trip twice", func() {
+			drafts, err = scheduler.Tick(context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drafts).To(BeEmpty())
+		})
+
+	})
+
+	Context("after missing occurrences", func() {
+
+		BeforeEach(func() {
+			mockClock.Set(time.Date(2017, 11, 13, 9, 0, 0, 0, time.UTC))
+			drafts, err = scheduler.Tick(context)
+		})
+
+		It("should catch up on every missed occurrence", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drafts).To(HaveLen(3))
+		})
+
+	})
+
+	Context("in summer time", func() {
+
+		BeforeEach(func() {
+			schedule, err := scheduleStorage.GetOne(scheduleID, context)
+			Expect(err).ToNot(HaveOccurred())
+			schedule.Next, err = schedule.NextOccurrence(time.Date(2018, 6, 3, 12, 0, 0, 0, time.UTC))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(scheduleStorage.Update(schedule, context)).To(Succeed())
+		})
+
+		It("should follow the time zone of the schedule", func() {
+			schedule, err := scheduleStorage.GetOne(scheduleID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedule.Next).To(Equal(time.Date(2018, 6, 4, 7, 0, 0, 0, time.UTC)))
+		})
+
+	})
+
+	Context("for a car share that no longer exists", func() {
+
+		BeforeEach(func() {
+			Expect(carShareStorage.Delete(carShareID, context)).To(Succeed())
+			mockClock.Set(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC))
+			drafts, err = scheduler.Tick(context)
+		})
+
+		It("should delete the schedule", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(drafts).To(BeEmpty())
+			_, err = scheduleStorage.GetOne(scheduleID, context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
@@ -1,6 +1,8 @@
 package memory
 
 import (
+	"time"
+
 	"gopkg.in/mgo.v2/bson"
 
 	"github.com/LewisWatson/carshare-back/model"
@@ -69,3 +71,14 @@ func (s *CarShareStorage) Update(c model.CarShare, context api2go.APIContexter)
 
 	return nil
 }
+
+// GetNotificationsDue to satisfy storage.CarShareStoreage interface
+func (s CarShareStorage) GetNotificationsDue(now time.Time, context api2go.APIContexter) ([]model.CarShare, error) {
+	result := []model.CarShare{}
+	for _, cs := range s.carShares {
+		if !cs.NextDigest.After(now) || (cs.Reminder != nil && !cs.NextReminder.After(now)) {
+			result = append(result, *cs)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// NewDeviceStorage initializes the storage
+func NewDeviceStorage() *DeviceStorage {
+	return &DeviceStorage{make(map[string]*model.Device)}
+}
+
+// DeviceStorage in memory device store
+type DeviceStorage struct {
+	devices map[string]*model.Device
+}
+
+// GetAll to satisfy storage.DeviceStorage interface
+func (s DeviceStorage) GetAll(userID string, context api2go.APIContexter) ([]model.Device, error) {
+	result := []model.Device{}
+	for _, device := range s.devices {
+		if device.UserID == userID {
+			result = append(result, *device)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Created.Before(result[j].Created) })
+	return result, nil
+}
+
+// GetOne to satisfy storage.DeviceStorage interface
+func (s DeviceStorage) GetOne(id string, context api2go.APIContexter) (model.Device, error) {
+	device, ok := s.devices[id]
+	if !ok {
+		return model.Device{}, storage.ErrNotFound
+	}
+	return *device, nil
+}
+
+// Insert to satisfy storage.DeviceStorage interface
+func (s *DeviceStorage) Insert(d model.Device, context api2go.APIContexter) (string, error) {
+	d.ID = bson.NewObjectId()
+	s.devices[d.GetID()] = &d
+	return d.GetID(), nil
+}
+
+// Delete to satisfy storage.DeviceStorage interface
+func (s *DeviceStorage) Delete(id string, context api2go.APIContexter) error {
+	_, exists := s.devices[id]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	delete(s.devices, id)
+	return nil
+}
+
+// DeleteToken to satisfy storage.DeviceStorage interface
+func (s *DeviceStorage) DeleteToken(token string, context api2go.APIContexter) error {
+	for id, device := range s.devices {
+		if device.Token == token {
+			delete(s.devices, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// NewExpenseStorage initializes the storage
+func NewExpenseStorage() *ExpenseStorage {
+	return &ExpenseStorage{make(map[string]*model.Expense)}
+}
+
+// ExpenseStorage in memory expense store
+type ExpenseStorage struct {
+	expenses map[string]*model.Expense
+}
+
+// GetAll to satisfy storage.ExpenseStorage interface
+func (s ExpenseStorage) GetAll(carShareID string, context api2go.APIContexter) ([]model.Expense, error) {
+	result := []model.Expense{}
+	for _, expense := range s.expenses {
+		if expense.CarShareID == carShareID {
+			result = append(result, *expense)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TimeStamp.Before(result[j].TimeStamp) })
+	return result, nil
+}
+
+// GetOne to satisfy storage.ExpenseStorage interface
+func (s ExpenseStorage) GetOne(id string, context api2go.APIContexter) (model.Expense, error) {
+	expense, ok := s.expenses[id]
+	if !ok {
+		return model.Expense{}, storage.ErrNotFound
+	}
+	return *expense, nil
+}
+
+// Insert to satisfy storage.ExpenseStorage interface
+func (s *ExpenseStorage) Insert(e model.Expense, context api2go.APIContexter) (string, error) {
+	e.ID = bson.NewObjectId()
+	s.expenses[e.GetID()] = &e
+	return e.GetID(), nil
+}
+
+// Delete to satisfy storage.ExpenseStorage interface
+func (s *ExpenseStorage) Delete(id string, context api2go.APIContexter) error {
+	_, exists := s.expenses[id]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	delete(s.expenses, id)
+	return nil
+}
+
+// Update to satisfy storage.ExpenseStorage interface
+func (s *ExpenseStorage) Update(e model.Expense, context api2go.APIContexter) error {
+	_, exists := s.expenses[e.GetID()]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	s.expenses[e.GetID()] = &e
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// NewRouteStorage initializes the storage
+func NewRouteStorage() *RouteStorage {
+	return &RouteStorage{make(map[string]*model.Route)}
+}
+
+// RouteStorage in memory route store
+type RouteStorage struct {
+	routes map[string]*model.Route
+}
+
+// GetAll to satisfy storage.RouteStorage interface
+func (s RouteStorage) GetAll(carShareID string, context api2go.APIContexter) ([]model.Route, error) {
+	result := []model.Route{}
+	for _, route := range s.routes {
+		if route.CarShareID == carShareID {
+			result = append(result, *route)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetOne to satisfy storage.RouteStorage interface
+func (s RouteStorage) GetOne(id string, context api2go.APIContexter) (model.Route, error) {
+	route, ok := s.routes[id]
+	if !ok {
+		return model.Route{}, storage.ErrNotFound
+	}
+	return *route, nil
+}
+
+// Insert to satisfy storage.RouteStorage interface
+func (s *RouteStorage) Insert(rt model.Route, context api2go.APIContexter) (string, error) {
+	rt.ID = bson.NewObjectId()
+	s.routes[rt.GetID()] = &rt
+	return rt.GetID(), nil
+}
+
+// Delete to satisfy storage.RouteStorage interface
+func (s *RouteStorage) Delete(id string, context api2go.APIContexter) error {
+	_, exists := s.routes[id]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	delete(s.routes, id)
+	return nil
+}
+
+// Update to satisfy storage.RouteStorage interface
+func (s *RouteStorage) Update(rt model.Route, context api2go.APIContexter) error {
+	_, exists := s.routes[rt.GetID()]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	s.routes[rt.GetID()] = &rt
+	return nil
+}
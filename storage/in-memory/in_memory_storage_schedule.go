@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// NewScheduleStorage initializes the storage
+func NewScheduleStorage() *ScheduleStorage {
+	return &ScheduleStorage{make(map[string]*model.Schedule)}
+}
+
+// ScheduleStorage in memory schedule store
+type ScheduleStorage struct {
+	schedules map[string]*model.Schedule
+}
+
+// GetAll to satisfy storage.ScheduleStorage interface
+func (s ScheduleStorage) GetAll(carShareID string, context api2go.APIContexter) ([]model.Schedule, error) {
+	result := []model.Schedule{}
+	for _, schedule := range s.schedules {
+		if schedule.CarShareID == carShareID {
+			result = append(result, *schedule)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetDue to satisfy storage.ScheduleStorage interface
+func (s ScheduleStorage) GetDue(now time.Time, context api2go.APIContexter) ([]model.Schedule, error) {
+	result := []model.Schedule{}
+	for _, schedule := range s.schedules {
+		if !schedule.Next.After(now) {
+			result = append(result, *schedule)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Next.Before(result[j].Next) })
+	return result, nil
+}
+
+// GetOne to satisfy storage.ScheduleStorage interface
+func (s ScheduleStorage) GetOne(id string, context api2go.APIContexter) (model.Schedule, error) {
+	schedule, ok := s.schedules[id]
+	if !ok {
+		return model.Schedule{}, storage.ErrNotFound
+	}
+	return *schedule, nil
+}
+
+// Insert to satisfy storage.ScheduleStorage interface
+func (s *ScheduleStorage) Insert(sc model.Schedule, context api2go.APIContexter) (string, error) {
+	sc.ID = bson.NewObjectId()
+	s.schedules[sc.GetID()] = &sc
+	return sc.GetID(), nil
+}
+
+// Delete to satisfy storage.ScheduleStorage interface
+func (s *ScheduleStorage) Delete(id string, context api2go.APIContexter) error {
+	_, exists := s.schedules[id]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	delete(s.schedules, id)
+	return nil
+}
+
+// Update to satisfy storage.ScheduleStorage interface
+func (s *ScheduleStorage) Update(sc model.Schedule, context api2go.APIContexter) error {
+	_, exists := s.schedules[sc.GetID()]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	s.schedules[sc.GetID()] = &sc
+	return nil
+}
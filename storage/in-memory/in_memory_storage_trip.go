@@ -25,6 +25,20 @@ func (t byID) Less(i, j int) bool {
 	return t[i].GetID() < t[j].GetID()
 }
 
+type byTimeStamp []model.Trip
+
+func (t byTimeStamp) Len() int {
+	return len(t)
+}
+
+func (t byTimeStamp) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+}
+
+func (t byTimeStamp) Less(i, j int) bool {
+	return t[i].TimeStamp.Before(t[j].TimeStamp)
+}
+
 // NewTripStorage initializes the storage
 func NewTripStorage() *TripStorage {
 	return &TripStorage{make(map[string]*model.Trip)}
@@ -99,3 +113,77 @@ func (s *TripStorage) GetLatest(carShareID string, context api2go.APIContexter)
 
 	return latestTrip, nil
 }
+
+// Iterate to satisfy storage.TripStorage interface
+func (s *TripStorage) Iterate(carShareID string, fn func(model.Trip) error, context api2go.APIContexter) error {
+
+	result := []model.Trip{}
+	for _, trip := range s.trips {
+		if trip.CarShareID == carShareID {
+			result = append(result, *trip)
+		}
+	}
+	sort.Sort(byTimeStamp(result))
+
+	for _, trip := range result {
+		err := fn(trip)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Stats to satisfy storage.TripStorage interface
+func (s *TripStorage) Stats(userID string, carShareIDs []string, context api2go.APIContexter) (model.Stats, error) {
+	stats := model.Stats{
+		UserID:       userID,
+		CarShareIDs:  carShareIDs,
+		CoPassengers: []model.CoPassenger{},
+		Months:       []model.MonthStats{},
+	}
+	inCarShares := make(map[string]bool)
+	for _, carShareID := range carShareIDs {
+		inCarShares[carShareID] = true
+	}
+
+	months := make(map[string]*model.MonthStats)
+	coPassengers := make(map[string]int)
+	for _, trip := range s.trips {
+		if !inCarShares[trip.CarShareID] || !trip.Status.Counts() || !trip.IsParticipant(userID) {
+			continue
+		}
+		key := trip.TimeStamp.UTC().Format("2006-01")
+		if months[key] == nil {
+			months[key] = &model.MonthStats{Month: key}
+		}
+		if trip.DriverID == userID {
+			months[key].MetresAsDriver += trip.Metres
+			months[key].TripsAsDriver++
+		} else {
+			months[key].MetresAsPassenger += trip.Metres
+			months[key].TripsAsPassenger++
+		}
+		for _, occupant := range append([]string{trip.DriverID}, trip.PassengerIDs...) {
+			if occupant != userID {
+				coPassengers[occupant]++
+			}
+		}
+	}
+
+	for _, month := range months {
+		stats.Months = append(stats.Months, *month)
+	}
+	sort.Slice(stats.Months, func(i, j int) bool {
+		return stats.Months[i].Month < stats.Months[j].Month
+	})
+	stats.Total()
+
+	for occupant, trips := range coPassengers {
+		stats.CoPassengers = append(stats.CoPassengers, model.CoPassenger{UserID: occupant, Trips: trips})
+	}
+	stats.SortCoPassengers()
+
+	return stats, nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"sort"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// NewVehicleStorage initializes the storage
+func NewVehicleStorage() *VehicleStorage {
+	return &VehicleStorage{make(map[string]*model.Vehicle)}
+}
+
+// VehicleStorage in memory vehicle store
+type VehicleStorage struct {
+	vehicles map[string]*model.Vehicle
+}
+
+// GetAll to satisfy storage.VehicleStorage interface
+func (s VehicleStorage) GetAll(carShareID string, context api2go.APIContexter) ([]model.Vehicle, error) {
+	result := []model.Vehicle{}
+	for _, vehicle := range s.vehicles {
+		if vehicle.CarShareID == carShareID {
+			result = append(result, *vehicle)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// GetOne to satisfy storage.VehicleStorage interface
+func (s VehicleStorage) GetOne(id string, context api2go.APIContexter) (model.Vehicle, error) {
+	vehicle, ok := s.vehicles[id]
+	if !ok {
+		return model.Vehicle{}, storage.ErrNotFound
+	}
+	return *vehicle, nil
+}
+
+// Insert to satisfy storage.VehicleStorage interface
+func (s *VehicleStorage) Insert(v model.Vehicle, context api2go.APIContexter) (string, error) {
+	v.ID = bson.NewObjectId()
+	s.vehicles[v.GetID()] = &v
+	return v.GetID(), nil
+}
+
+// Delete to satisfy storage.VehicleStorage interface
+func (s *VehicleStorage) Delete(id string, context api2go.APIContexter) error {
+	_, exists := s.vehicles[id]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	delete(s.vehicles, id)
+	return nil
+}
+
+// Update to satisfy storage.VehicleStorage interface
+func (s *VehicleStorage) Update(v model.Vehicle, context api2go.APIContexter) error {
+	_, exists := s.vehicles[v.GetID()]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	s.vehicles[v.GetID()] = &v
+	return nil
+}
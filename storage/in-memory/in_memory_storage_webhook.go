@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// NewWebhookStorage initializes the storage
+func NewWebhookStorage() *WebhookStorage {
+	return &WebhookStorage{make(map[string]*model.Webhook)}
+}
+
+// WebhookStorage in memory webhook store
+type WebhookStorage struct {
+	webhooks map[string]*model.Webhook
+}
+
+// GetAll to satisfy storage.WebhookStorage interface
+func (s WebhookStorage) GetAll(carShareID string, context api2go.APIContexter) ([]model.Webhook, error) {
+	result := []model.Webhook{}
+	for _, webhook := range s.webhooks {
+		if webhook.CarShareID == carShareID {
+			result = append(result, *webhook)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].URL < result[j].URL })
+	return result, nil
+}
+
+// GetOne to satisfy storage.WebhookStorage interface
+func (s WebhookStorage) GetOne(id string, context api2go.APIContexter) (model.Webhook, error) {
+	webhook, ok := s.webhooks[id]
+	if !ok {
+		return model.Webhook{}, storage.ErrNotFound
+	}
+	return *webhook, nil
+}
+
+// Insert to satisfy storage.WebhookStorage interface
+func (s *WebhookStorage) Insert(w model.Webhook, context api2go.APIContexter) (string, error) {
+	w.ID = bson.NewObjectId()
+	s.webhooks[w.GetID()] = &w
+	return w.GetID(), nil
+}
+
+// Delete to satisfy storage.WebhookStorage interface
+func (s *WebhookStorage) Delete(id string, context api2go.APIContexter) error {
+	_, exists := s.webhooks[id]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	delete(s.webhooks, id)
+	return nil
+}
+
+// Update to satisfy storage.WebhookStorage interface
+func (s *WebhookStorage) Update(w model.Webhook, context api2go.APIContexter) error {
+	_, exists := s.webhooks[w.GetID()]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	s.webhooks[w.GetID()] = &w
+	return nil
+}
+
+// NewDeliveryStorage initializes the storage
+func NewDeliveryStorage() *DeliveryStorage {
+	return &DeliveryStorage{make(map[string]*model.Delivery)}
+}
+
+// DeliveryStorage in memory queue of deliveries to webhooks
+type DeliveryStorage struct {
+	deliveries map[string]*model.Delivery
+}
+
+// GetAll to satisfy storage.DeliveryStorage interface
+func (s DeliveryStorage) GetAll(webhookID string, context api2go.APIContexter) ([]model.Delivery, error) {
+	result := []model.Delivery{}
+	for _, delivery := range s.deliveries {
+		if delivery.WebhookID == webhookID {
+			result = append(result, *delivery)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Created.After(result[j].Created) })
+	return result, nil
+}
+
+// GetDue to satisfy storage.DeliveryStorage interface
+func (s DeliveryStorage) GetDue(now time.Time, context api2go.APIContexter) ([]model.Delivery, error) {
+	result := []model.Delivery{}
+	for _, delivery := range s.deliveries {
+		if delivery.Status == model.DeliveryPending && !delivery.NextAttempt.After(now) {
+			result = append(result, *delivery)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].NextAttempt.Before(result[j].NextAttempt) })
+	return result, nil
+}
+
+// GetOne to satisfy storage.DeliveryStorage interface
+func (s DeliveryStorage) GetOne(id string, context api2go.APIContexter) (model.Delivery, error) {
+	delivery, ok := s.deliveries[id]
+	if !ok {
+		return model.Delivery{}, storage.ErrNotFound
+	}
+	return *delivery, nil
+}
+
+// Insert to satisfy storage.DeliveryStorage interface
+func (s *DeliveryStorage) Insert(d model.Delivery, context api2go.APIContexter) (string, error) {
+	d.ID = bson.NewObjectId()
+	s.deliveries[d.GetID()] = &d
+	return d.GetID(), nil
+}
+
+// Update to satisfy storage.DeliveryStorage interface
+func (s *DeliveryStorage) Update(d model.Delivery, context api2go.APIContexter) error {
+	_, exists := s.deliveries[d.GetID()]
+	if !exists {
+		return storage.ErrNotFound
+	}
+	s.deliveries[d.GetID()] = &d
+	return nil
+}
@@ -1,6 +1,8 @@
 package mongodb
 
 import (
+	"time"
+
 	mgo "gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 
@@ -10,7 +12,9 @@ import (
 )
 
 // CarShareStorage stores all car shares
-type CarShareStorage struct{}
+type CarShareStorage struct {
+	Config
+}
 
 // GetAll to satisfy storage.CarShareStorage interface
 func (s CarShareStorage) GetAll(userID string, ctx api2go.APIContexter) ([]model.CarShare, error) {
@@ -20,7 +24,7 @@ func (s CarShareStorage) GetAll(userID string, ctx api2go.APIContexter) ([]model
 	}
 	defer ms.Close()
 	result := []model.CarShare{}
-	err = ms.DB(CarShareDB).C(CarSharesColl).Find(bson.M{"members": userID}).All(&result)
+	err = s.Collection(ms, CarSharesColl).Find(bson.M{"members": userID}).All(&result)
 	return result, err
 }
 
@@ -37,7 +41,7 @@ func (s CarShareStorage) GetOne(id string, ctx api2go.APIContexter) (model.CarSh
 	}
 	defer ms.Close()
 	result := model.CarShare{}
-	err = ms.DB(CarShareDB).C(CarSharesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	err = s.Collection(ms, CarSharesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
 	if err != nil {
 		log.Errorf("Error finding car share %s, %s", id, err)
 		if err == mgo.ErrNotFound {
@@ -55,7 +59,7 @@ func (s *CarShareStorage) Insert(c model.CarShare, context api2go.APIContexter)
 	}
 	defer mgoSession.Close()
 	c.ID = bson.NewObjectId()
-	err = mgoSession.DB(CarShareDB).C(CarSharesColl).Insert(&c)
+	err = s.Collection(mgoSession, CarSharesColl).Insert(&c)
 	if err != nil {
 		log.Errorf("Error inserting car share, %s", err)
 	}
@@ -72,7 +76,7 @@ func (s *CarShareStorage) Delete(id string, context api2go.APIContexter) error {
 		return err
 	}
 	defer mgoSession.Close()
-	err = mgoSession.DB(CarShareDB).C(CarSharesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	err = s.Collection(mgoSession, CarSharesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
 	if err != nil {
 		log.Errorf("Error inserting car share, %s", err)
 		if err == mgo.ErrNotFound {
@@ -92,7 +96,7 @@ func (s *CarShareStorage) Update(c model.CarShare, context api2go.APIContexter)
 		return err
 	}
 	defer mgoSession.Close()
-	err = mgoSession.DB(CarShareDB).C(CarSharesColl).Update(bson.M{"_id": c.ID}, &c)
+	err = s.Collection(mgoSession, CarSharesColl).Update(bson.M{"_id": c.ID}, &c)
 	if err != nil {
 		log.Errorf("Error updating car share, %s", err)
 		if err == mgo.ErrNotFound {
@@ -101,3 +105,28 @@ func (s *CarShareStorage) Update(c model.CarShare, context api2go.APIContexter)
 	}
 	return err
 }
+
+// GetNotificationsDue to satisfy storage.CarShareStoreage interface
+func (s CarShareStorage) GetNotificationsDue(now time.Time, ctx api2go.APIContexter) ([]model.CarShare, error) {
+	ms, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer ms.Close()
+	result := []model.CarShare{}
+	err = s.Collection(ms, CarSharesColl).Find(bson.M{"$or": []bson.M{
+		// car shares have no next digest or reminder until the notifier first works them out
+		{"next-digest": bson.M{"$exists": false}},
+		{"next-digest": bson.M{"$lte": now}},
+		{"reminder": bson.M{"$ne": nil}, "next-reminder": bson.M{"$exists": false}},
+		{"reminder": bson.M{"$ne": nil}, "next-reminder": bson.M{"$lte": now}},
+	}}).All(&result)
+	if err != nil {
+		log.Errorf("Error finding car shares with notifications due by %s, %s", now, err)
+	}
+	for i := range result {
+		result[i].NextDigest = result[i].NextDigest.UTC()
+		result[i].NextReminder = result[i].NextReminder.UTC()
+	}
+	return result, err
+}
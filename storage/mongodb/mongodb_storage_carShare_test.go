@@ -1,6 +1,8 @@
 package mongodb
 
 import (
+	"time"
+
 	"github.com/LewisWatson/carshare-back/model"
 	"github.com/LewisWatson/carshare-back/storage"
 
@@ -77,6 +79,50 @@ var _ = Describe("Car Share Storage", func() {
 
 	})
 
+	Describe("get notifications due", func() {
+
+		var (
+			now    time.Time
+			result []model.CarShare
+			err    error
+		)
+
+		BeforeEach(func() {
+			now = time.Date(2017, time.March, 6, 9, 0, 0, 0, time.UTC)
+			reminder := &model.Reminder{Weekdays: []string{"monday"}, Time: "08:00", TimeZone: "UTC"}
+			err = db.DB(CarShareDB).C(CarSharesColl).DropCollection()
+			Expect(err).ToNot(HaveOccurred())
+			err = db.DB(CarShareDB).C(CarSharesColl).Insert(
+				&model.CarShare{Name: "never notified"},
+				&model.CarShare{Name: "digest due", NextDigest: now.Add(-time.Minute)},
+				&model.CarShare{Name: "digest not due", NextDigest: now.Add(time.Minute)},
+				&model.CarShare{Name: "reminder due", NextDigest: now.Add(time.Minute), Reminder: reminder, NextReminder: now},
+				&model.CarShare{Name: "reminder unset", NextDigest: now.Add(time.Minute), Reminder: reminder},
+				&model.CarShare{Name: "reminder not due", NextDigest: now.Add(time.Minute), Reminder: reminder, NextReminder: now.Add(time.Hour)},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			result, err = carShareStorage.GetNotificationsDue(now, context)
+		})
+
+		It("should return the car shares with a digest or reminder due", func() {
+			Expect(err).ToNot(HaveOccurred())
+			names := []string{}
+			for _, carShare := range result {
+				names = append(names, carShare.Name)
+			}
+			Expect(names).To(ConsistOf("never notified", "digest due", "reminder due", "reminder unset"))
+		})
+
+		It("should return times in UTC", func() {
+			for _, carShare := range result {
+				if carShare.Name == "digest due" {
+					Expect(carShare.NextDigest).To(Equal(now.Add(-time.Minute)))
+				}
+			}
+		})
+
+	})
+
 	Describe("get one", func() {
 
 		var (
@@ -0,0 +1,106 @@
+package mongodb
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// DeviceStorage stores all devices registered for push notifications
+type DeviceStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.DeviceStorage interface
+func (s DeviceStorage) GetAll(userID string, ctx api2go.APIContexter) ([]model.Device, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Device{}
+	err = s.Collection(mgoSession, DevicesColl).Find(bson.M{"user": userID}).Sort("created").All(&result)
+	if err != nil {
+		log.Errorf("Error finding devices for user %s, %s", userID, err)
+	}
+	for i := range result {
+		result[i].Created = result[i].Created.UTC()
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.DeviceStorage interface
+func (s DeviceStorage) GetOne(id string, ctx api2go.APIContexter) (model.Device, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Device{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Device{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Device{}
+	err = s.Collection(mgoSession, DevicesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding device %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	result.Created = result.Created.UTC()
+	return result, err
+}
+
+// Insert to satisfy storage.DeviceStorage interface
+func (s *DeviceStorage) Insert(d model.Device, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	d.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, DevicesColl).Insert(&d)
+	if err != nil {
+		log.Errorf("Error inserting device, %s", err)
+	}
+	return d.GetID(), err
+}
+
+// Delete to satisfy storage.DeviceStorage interface
+func (s *DeviceStorage) Delete(id string, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(id) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, DevicesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	if err != nil {
+		log.Errorf("Error deleting device %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// DeleteToken to satisfy storage.DeviceStorage interface
+func (s *DeviceStorage) DeleteToken(token string, ctx api2go.APIContexter) error {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	_, err = s.Collection(mgoSession, DevicesColl).RemoveAll(bson.M{"token": token})
+	if err != nil {
+		log.Errorf("Error deleting devices with token, %s", err)
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Device Storage", func() {
+
+	var (
+		deviceStorage *DeviceStorage
+		context       *api2go.APIContext
+		userID        = bson.NewObjectId().Hex()
+		device1ID     = bson.NewObjectId()
+		created       = time.Date(2017, time.March, 6, 8, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		deviceStorage = &DeviceStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(DevicesColl).Insert(
+			&model.Device{
+				ID:       device1ID,
+				Token:    "phone",
+				Platform: model.Android,
+				Created:  created,
+				UserID:   userID,
+			},
+			&model.Device{
+				Token:    "laptop",
+				Platform: model.Web,
+				Created:  created.Add(time.Hour),
+				UserID:   userID,
+			},
+			&model.Device{
+				Token:    "phone",
+				Platform: model.Android,
+				Created:  created,
+				UserID:   bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		It("should return the devices of the user oldest first", func() {
+			result, err := deviceStorage.GetAll(userID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Token).To(Equal("phone"))
+			Expect(result[0].Created).To(Equal(created))
+			Expect(result[1].Token).To(Equal("laptop"))
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should return the device", func() {
+			result, err := deviceStorage.GetOne(device1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Token).To(Equal("phone"))
+			Expect(result.UserID).To(Equal(userID))
+		})
+
+		It("should return ErrNotFound for a missing device", func() {
+			_, err := deviceStorage.GetOne(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should return ErrInvalidID for an invalid id", func() {
+			_, err := deviceStorage.GetOne("invalid", context)
+			Expect(err).To(Equal(storage.ErrInvalidID))
+		})
+
+	})
+
+	Describe("inserting", func() {
+
+		It("should insert the device", func() {
+			id, err := deviceStorage.Insert(model.Device{Token: "tablet", Platform: model.IOS, UserID: userID}, context)
+			Expect(err).ToNot(HaveOccurred())
+			result, err := deviceStorage.GetOne(id, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Token).To(Equal("tablet"))
+		})
+
+	})
+
+	Describe("deleting", func() {
+
+		It("should delete the device", func() {
+			Expect(deviceStorage.Delete(device1ID.Hex(), context)).To(Succeed())
+			_, err := deviceStorage.GetOne(device1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should return ErrNotFound for a missing device", func() {
+			Expect(deviceStorage.Delete(bson.NewObjectId().Hex(), context)).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+	Describe("deleting a token", func() {
+
+		It("should delete every registration of the token", func() {
+			Expect(deviceStorage.DeleteToken("phone", context)).To(Succeed())
+			count, err := db.DB(CarShareDB).C(DevicesColl).Find(bson.M{"token": "phone"}).Count()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(0))
+			result, err := deviceStorage.GetAll(userID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+		})
+
+	})
+
+})
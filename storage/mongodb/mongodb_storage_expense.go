@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// ExpenseStorage stores all expenses
+type ExpenseStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.ExpenseStorage interface
+func (s ExpenseStorage) GetAll(carShareID string, ctx api2go.APIContexter) ([]model.Expense, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Expense{}
+	err = s.Collection(mgoSession, ExpensesColl).Find(bson.M{"car-share": carShareID}).Sort("timestamp").All(&result)
+	if err != nil {
+		log.Errorf("Error finding expenses for car share %s, %s", carShareID, err)
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.ExpenseStorage interface
+func (s ExpenseStorage) GetOne(id string, ctx api2go.APIContexter) (model.Expense, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Expense{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Expense{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Expense{}
+	err = s.Collection(mgoSession, ExpensesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding expense %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return result, err
+}
+
+// Insert to satisfy storage.ExpenseStorage interface
+func (s *ExpenseStorage) Insert(e model.Expense, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	e.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, ExpensesColl).Insert(&e)
+	if err != nil {
+		log.Errorf("Error inserting expense, %s", err)
+	}
+	return e.GetID(), err
+}
+
+// Delete to satisfy storage.ExpenseStorage interface
+func (s *ExpenseStorage) Delete(id string, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(id) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, ExpensesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	if err != nil {
+		log.Errorf("Error deleting expense %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// Update to satisfy storage.ExpenseStorage interface
+func (s *ExpenseStorage) Update(e model.Expense, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(e.GetID()) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, ExpensesColl).Update(bson.M{"_id": e.ID}, &e)
+	if err != nil {
+		log.Errorf("Error updating expense %s, %s", e.GetID(), err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,177 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Expense Storage", func() {
+
+	var (
+		expenseStorage *ExpenseStorage
+		context        *api2go.APIContext
+		carShareID     = bson.NewObjectId().Hex()
+		expense1ID     = bson.NewObjectId()
+	)
+
+	BeforeEach(func() {
+		expenseStorage = &ExpenseStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(ExpensesColl).Insert(
+			&model.Expense{
+				ID:          expense1ID,
+				Description: "Parking",
+				Amount:      450,
+				TimeStamp:   time.Date(2017, 11, 7, 0, 0, 0, 0, time.UTC),
+				CarShareID:  carShareID,
+			},
+			&model.Expense{
+				Description: "Fuel",
+				Amount:      6000,
+				TimeStamp:   time.Date(2017, 11, 6, 0, 0, 0, 0, time.UTC),
+				CarShareID:  carShareID,
+			},
+			&model.Expense{
+				Description: "Another car share",
+				CarShareID:  bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		var (
+			result []model.Expense
+			err    error
+		)
+
+		BeforeEach(func() {
+			result, err = expenseStorage.GetAll(carShareID, context)
+		})
+
+		It("should return the expenses for the car share oldest first", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Description).To(Equal("Fuel"))
+			Expect(result[1].Description).To(Equal("Parking"))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			BeforeEach(func() {
+				context.Reset()
+				result, err = expenseStorage.GetAll(carShareID, context)
+			})
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		var (
+			result model.Expense
+			err    error
+		)
+
+		BeforeEach(func() {
+			result, err = expenseStorage.GetOne(expense1ID.Hex(), context)
+		})
+
+		It("should return the specified expense", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Description).To(Equal("Parking"))
+			Expect(result.Amount).To(Equal(450))
+		})
+
+		Context("targeting an expense that does not exist", func() {
+
+			BeforeEach(func() {
+				result, err = expenseStorage.GetOne(bson.NewObjectId().Hex(), context)
+			})
+
+			It("should throw an ErrNotFound error", func() {
+				Expect(err).To(Equal(storage.ErrNotFound))
+			})
+
+		})
+
+		Context("using invalid id", func() {
+
+			BeforeEach(func() {
+				result, err = expenseStorage.GetOne("invalid id", context)
+			})
+
+			It("should throw an ErrInvalidID error", func() {
+				Expect(err).To(Equal(storage.ErrInvalidID))
+			})
+
+		})
+
+	})
+
+	Describe("inserting", func() {
+
+		It("should insert a new expense", func() {
+			id, err := expenseStorage.Insert(model.Expense{Description: "Toll", CarShareID: carShareID}, context)
+			Expect(err).ToNot(HaveOccurred())
+			result := model.Expense{}
+			err = db.DB(CarShareDB).C(ExpensesColl).FindId(bson.ObjectIdHex(id)).One(&result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Description).To(Equal("Toll"))
+		})
+
+	})
+
+	Describe("updating", func() {
+
+		It("should update the expense", func() {
+			expense, err := expenseStorage.GetOne(expense1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			expense.Amount = 500
+			Expect(expenseStorage.Update(expense, context)).To(Succeed())
+			expense, err = expenseStorage.GetOne(expense1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expense.Amount).To(Equal(500))
+		})
+
+		It("should throw an ErrNotFound error for an expense that does not exist", func() {
+			err := expenseStorage.Update(model.Expense{ID: bson.NewObjectId()}, context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+	Describe("deleting", func() {
+
+		It("should delete the expense", func() {
+			Expect(expenseStorage.Delete(expense1ID.Hex(), context)).To(Succeed())
+			_, err := expenseStorage.GetOne(expense1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should throw an ErrNotFound error for an expense that does not exist", func() {
+			err := expenseStorage.Delete(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// RouteStorage stores all routes
+type RouteStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.RouteStorage interface
+func (s RouteStorage) GetAll(carShareID string, ctx api2go.APIContexter) ([]model.Route, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Route{}
+	err = s.Collection(mgoSession, RoutesColl).Find(bson.M{"car-share": carShareID}).Sort("name").All(&result)
+	if err != nil {
+		log.Errorf("Error finding routes for car share %s, %s", carShareID, err)
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.RouteStorage interface
+func (s RouteStorage) GetOne(id string, ctx api2go.APIContexter) (model.Route, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Route{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Route{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Route{}
+	err = s.Collection(mgoSession, RoutesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding route %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return result, err
+}
+
+// Insert to satisfy storage.RouteStorage interface
+func (s *RouteStorage) Insert(rt model.Route, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	rt.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, RoutesColl).Insert(&rt)
+	if err != nil {
+		log.Errorf("Error inserting route, %s", err)
+	}
+	return rt.GetID(), err
+}
+
+// Delete to satisfy storage.RouteStorage interface
+func (s *RouteStorage) Delete(id string, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(id) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, RoutesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	if err != nil {
+		log.Errorf("Error deleting route %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// Update to satisfy storage.RouteStorage interface
+func (s *RouteStorage) Update(rt model.Route, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(rt.GetID()) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, RoutesColl).Update(bson.M{"_id": rt.ID}, &rt)
+	if err != nil {
+		log.Errorf("Error updating route %s, %s", rt.GetID(), err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
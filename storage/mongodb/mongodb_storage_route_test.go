@@ -0,0 +1,173 @@
+package mongodb
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Route Storage", func() {
+
+	var (
+		routeStorage *RouteStorage
+		context      *api2go.APIContext
+		carShareID   = bson.NewObjectId().Hex()
+		route1ID     = bson.NewObjectId()
+	)
+
+	BeforeEach(func() {
+		routeStorage = &RouteStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(RoutesColl).Insert(
+			&model.Route{
+				ID:         route1ID,
+				Name:       "Work",
+				Metres:     12000,
+				CarShareID: carShareID,
+			},
+			&model.Route{
+				Name:       "Football",
+				Metres:     8000,
+				CarShareID: carShareID,
+			},
+			&model.Route{
+				Name:       "Another car share",
+				CarShareID: bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		var (
+			result []model.Route
+			err    error
+		)
+
+		BeforeEach(func() {
+			result, err = routeStorage.GetAll(carShareID, context)
+		})
+
+		It("should return the routes for the car share ordered by name", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Name).To(Equal("Football"))
+			Expect(result[1].Name).To(Equal("Work"))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			BeforeEach(func() {
+				context.Reset()
+				result, err = routeStorage.GetAll(carShareID, context)
+			})
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		var (
+			result model.Route
+			err    error
+		)
+
+		BeforeEach(func() {
+			result, err = routeStorage.GetOne(route1ID.Hex(), context)
+		})
+
+		It("should return the specified route", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("Work"))
+			Expect(result.Metres).To(Equal(12000))
+		})
+
+		Context("targeting a route that does not exist", func() {
+
+			BeforeEach(func() {
+				result, err = routeStorage.GetOne(bson.NewObjectId().Hex(), context)
+			})
+
+			It("should throw an ErrNotFound error", func() {
+				Expect(err).To(Equal(storage.ErrNotFound))
+			})
+
+		})
+
+		Context("using invalid id", func() {
+
+			BeforeEach(func() {
+				result, err = routeStorage.GetOne("invalid id", context)
+			})
+
+			It("should throw an ErrInvalidID error", func() {
+				Expect(err).To(Equal(storage.ErrInvalidID))
+			})
+
+		})
+
+	})
+
+	Describe("inserting", func() {
+
+		It("should insert a new route", func() {
+			id, err := routeStorage.Insert(model.Route{Name: "School run", CarShareID: carShareID}, context)
+			Expect(err).ToNot(HaveOccurred())
+			result := model.Route{}
+			err = db.DB(CarShareDB).C(RoutesColl).FindId(bson.ObjectIdHex(id)).One(&result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("School run"))
+		})
+
+	})
+
+	Describe("updating", func() {
+
+		It("should update the route", func() {
+			route, err := routeStorage.GetOne(route1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			route.Metres = 12500
+			Expect(routeStorage.Update(route, context)).To(Succeed())
+			route, err = routeStorage.GetOne(route1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(route.Metres).To(Equal(12500))
+		})
+
+		It("should throw an ErrNotFound error for a route that does not exist", func() {
+			err := routeStorage.Update(model.Route{ID: bson.NewObjectId()}, context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+	Describe("deleting", func() {
+
+		It("should delete the route", func() {
+			Expect(routeStorage.Delete(route1ID.Hex(), context)).To(Succeed())
+			_, err := routeStorage.GetOne(route1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should throw an ErrNotFound error for a route that does not exist", func() {
+			err := routeStorage.Delete(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
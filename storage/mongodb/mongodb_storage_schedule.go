@@ -0,0 +1,132 @@
+package mongodb
+
+import (
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// ScheduleStorage stores all schedules
+type ScheduleStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.ScheduleStorage interface
+func (s ScheduleStorage) GetAll(carShareID string, ctx api2go.APIContexter) ([]model.Schedule, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Schedule{}
+	err = s.Collection(mgoSession, SchedulesColl).Find(bson.M{"car-share": carShareID}).Sort("name").All(&result)
+	if err != nil {
+		log.Errorf("Error finding schedules for car share %s, %s", carShareID, err)
+	}
+	for i := range result {
+		result[i].Next = result[i].Next.UTC()
+	}
+	return result, err
+}
+
+// GetDue to satisfy storage.ScheduleStorage interface
+func (s ScheduleStorage) GetDue(now time.Time, ctx api2go.APIContexter) ([]model.Schedule, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Schedule{}
+	err = s.Collection(mgoSession, SchedulesColl).Find(bson.M{"next": bson.M{"$lte": now}}).Sort("next").All(&result)
+	if err != nil {
+		log.Errorf("Error finding schedules due by %s, %s", now, err)
+	}
+	for i := range result {
+		result[i].Next = result[i].Next.UTC()
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.ScheduleStorage interface
+func (s ScheduleStorage) GetOne(id string, ctx api2go.APIContexter) (model.Schedule, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Schedule{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Schedule{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Schedule{}
+	err = s.Collection(mgoSession, SchedulesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding schedule %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	result.Next = result.Next.UTC()
+	return result, err
+}
+
+// Insert to satisfy storage.ScheduleStorage interface
+func (s *ScheduleStorage) Insert(sc model.Schedule, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	sc.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, SchedulesColl).Insert(&sc)
+	if err != nil {
+		log.Errorf("Error inserting schedule, %s", err)
+	}
+	return sc.GetID(), err
+}
+
+// Delete to satisfy storage.ScheduleStorage interface
+func (s *ScheduleStorage) Delete(id string, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(id) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, SchedulesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	if err != nil {
+		log.Errorf("Error deleting schedule %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// Update to satisfy storage.ScheduleStorage interface
+func (s *ScheduleStorage) Update(sc model.Schedule, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(sc.GetID()) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, SchedulesColl).Update(bson.M{"_id": sc.ID}, &sc)
+	if err != nil {
+		log.Errorf("Error updating schedule %s, %s", sc.GetID(), err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
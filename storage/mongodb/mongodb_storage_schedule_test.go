@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Schedule Storage", func() {
+
+	var (
+		scheduleStorage *ScheduleStorage
+		context         *api2go.APIContext
+		carShareID      = bson.NewObjectId().Hex()
+		schedule1ID     = bson.NewObjectId()
+		now             = time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		scheduleStorage = &ScheduleStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(SchedulesColl).Insert(
+			&model.Schedule{
+				ID:         schedule1ID,
+				Name:       "Commute",
+				Next:       now,
+				CarShareID: carShareID,
+			},
+			&model.Schedule{
+				Name:       "Football",
+				Next:       now.Add(time.Hour),
+				CarShareID: carShareID,
+			},
+			&model.Schedule{
+				Name:       "Another car share",
+				Next:       now.Add(-time.Hour),
+				CarShareID: bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		It("should return the schedules for the car share ordered by name", func() {
+			result, err := scheduleStorage.GetAll(carShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Name).To(Equal("Commute"))
+			Expect(result[1].Name).To(Equal("Football"))
+		})
+
+	})
+
+	Describe("get due", func() {
+
+		It("should return schedules from every car share that are due, soonest first", func() {
+			result, err := scheduleStorage.GetDue(now, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Name).To(Equal("Another car share"))
+			Expect(result[1].GetID()).To(Equal(schedule1ID.Hex()))
+			Expect(result[1].Next).To(Equal(now))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				context.Reset()
+				_, err := scheduleStorage.GetDue(now, context)
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should return the specified schedule", func() {
+			result, err := scheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("Commute"))
+		})
+
+		It("should throw an ErrNotFound error for a schedule that does not exist", func() {
+			_, err := scheduleStorage.GetOne(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+	Describe("updating", func() {
+
+		It("should update the schedule", func() {
+			schedule, err := scheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			schedule.Next = now.AddDate(0, 0, 2)
+			Expect(scheduleStorage.Update(schedule, context)).To(Succeed())
+			schedule, err = scheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(schedule.Next).To(Equal(now.AddDate(0, 0, 2)))
+		})
+
+	})
+
+	Describe("deleting", func() {
+
+		It("should delete the schedule", func() {
+			Expect(scheduleStorage.Delete(schedule1ID.Hex(), context)).To(Succeed())
+			_, err := scheduleStorage.GetOne(schedule1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
@@ -11,6 +11,7 @@ import (
 
 // TripStorage a place to store car share trips
 type TripStorage struct {
+	Config
 	CarshareStorage *CarShareStorage
 }
 
@@ -23,7 +24,7 @@ func (s *TripStorage) GetAll(context api2go.APIContexter) ([]model.Trip, error)
 	defer mgoSession.Close()
 
 	result := []model.Trip{}
-	err = mgoSession.DB(CarShareDB).C(TripsColl).Find(nil).Sort("-timestamp").All(&result)
+	err = s.Collection(mgoSession, TripsColl).Find(nil).Sort("-timestamp").All(&result)
 	s.setTimezonesToUTC(&result)
 	return result, err
 }
@@ -39,7 +40,7 @@ func (s *TripStorage) GetOne(id string, context api2go.APIContexter) (model.Trip
 	}
 	defer mgoSession.Close()
 	result := model.Trip{}
-	err = mgoSession.DB(CarShareDB).C(TripsColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	err = s.Collection(mgoSession, TripsColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -58,7 +59,7 @@ func (s *TripStorage) Insert(t model.Trip, context api2go.APIContexter) (string,
 	defer mgoSession.Close()
 
 	t.ID = bson.NewObjectId()
-	err = mgoSession.DB(CarShareDB).C(TripsColl).Insert(&t)
+	err = s.Collection(mgoSession, TripsColl).Insert(&t)
 	if err != nil {
 		return "", err
 	}
@@ -75,7 +76,7 @@ func (s *TripStorage) Delete(id string, context api2go.APIContexter) error {
 		return err
 	}
 	defer mgoSession.Close()
-	err = mgoSession.DB(CarShareDB).C(TripsColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	err = s.Collection(mgoSession, TripsColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -90,7 +91,7 @@ func (s *TripStorage) Update(t model.Trip, context api2go.APIContexter) error {
 	}
 	defer mgoSession.Close()
 
-	err = mgoSession.DB(CarShareDB).C(TripsColl).Update(bson.M{"_id": t.ID}, &t)
+	err = s.Collection(mgoSession, TripsColl).Update(bson.M{"_id": t.ID}, &t)
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -105,7 +106,7 @@ func (s *TripStorage) GetLatest(carShareID string, context api2go.APIContexter)
 	}
 	defer mgoSession.Close()
 	latestTrip := model.Trip{}
-	err = mgoSession.DB(CarShareDB).C(TripsColl).Find(bson.M{"car-share": carShareID}).Sort("-timestamp").One(&latestTrip)
+	err = s.Collection(mgoSession, TripsColl).Find(bson.M{"car-share": carShareID}).Sort("-timestamp").One(&latestTrip)
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -113,6 +114,81 @@ func (s *TripStorage) GetLatest(carShareID string, context api2go.APIContexter)
 	return latestTrip, err
 }
 
+// Iterate to satisfy storage.TripStorage interface
+func (s *TripStorage) Iterate(carShareID string, fn func(model.Trip) error, context api2go.APIContexter) error {
+	mgoSession, err := getMgoSession(context)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	iter := s.Collection(mgoSession, TripsColl).Find(bson.M{"car-share": carShareID}).Sort("timestamp").Iter()
+	trip := model.Trip{}
+	for iter.Next(&trip) {
+		s.setTimezoneToUTC(&trip)
+		err = fn(trip)
+		if err != nil {
+			iter.Close()
+			return err
+		}
+		// start afresh so maps from the previous trip are not merged into the next
+		trip = model.Trip{}
+	}
+	return iter.Close()
+}
+
+// Stats to satisfy storage.TripStorage interface
+func (s *TripStorage) Stats(userID string, carShareIDs []string, context api2go.APIContexter) (model.Stats, error) {
+	stats := model.Stats{
+		UserID:       userID,
+		CarShareIDs:  carShareIDs,
+		CoPassengers: []model.CoPassenger{},
+		Months:       []model.MonthStats{},
+	}
+	mgoSession, err := getMgoSession(context)
+	if err != nil {
+		return stats, err
+	}
+	defer mgoSession.Close()
+
+	// trips without a status predate them and count
+	match := bson.M{"$match": bson.M{
+		"car-share": bson.M{"$in": carShareIDs},
+		"status":    bson.M{"$in": []interface{}{nil, model.TripConfirmed}},
+		"$or":       []bson.M{{"driver": userID}, {"passengers": userID}},
+	}}
+	asDriver := bson.M{"$eq": []interface{}{"$driver", userID}}
+
+	err = s.Collection(mgoSession, TripsColl).Pipe([]bson.M{
+		match,
+		{"$group": bson.M{
+			"_id":                 bson.M{"$dateToString": bson.M{"format": "%Y-%m", "date": "$timestamp"}},
+			"metres-as-driver":    bson.M{"$sum": bson.M{"$cond": []interface{}{asDriver, "$metres", 0}}},
+			"metres-as-passenger": bson.M{"$sum": bson.M{"$cond": []interface{}{asDriver, 0, "$metres"}}},
+			"trips-as-driver":     bson.M{"$sum": bson.M{"$cond": []interface{}{asDriver, 1, 0}}},
+			"trips-as-passenger":  bson.M{"$sum": bson.M{"$cond": []interface{}{asDriver, 0, 1}}},
+		}},
+		{"$sort": bson.M{"_id": 1}},
+	}).All(&stats.Months)
+	if err != nil {
+		return stats, err
+	}
+	stats.Total()
+
+	err = s.Collection(mgoSession, TripsColl).Pipe([]bson.M{
+		match,
+		{"$project": bson.M{"occupants": bson.M{"$setUnion": []interface{}{
+			[]interface{}{"$driver"},
+			bson.M{"$ifNull": []interface{}{"$passengers", []string{}}},
+		}}}},
+		{"$unwind": "$occupants"},
+		{"$match": bson.M{"occupants": bson.M{"$ne": userID}}},
+		{"$group": bson.M{"_id": "$occupants", "trips": bson.M{"$sum": 1}}},
+		{"$sort": bson.D{{Name: "trips", Value: -1}, {Name: "_id", Value: 1}}},
+		{"$limit": model.CoPassengerLimit},
+	}).All(&stats.CoPassengers)
+	return stats, err
+}
+
 func (s *TripStorage) setTimezonesToUTC(trips *[]model.Trip) {
 	for _, trip := range *trips {
 		s.setTimezoneToUTC(&trip)
@@ -134,6 +210,6 @@ func (s TripStorage) findCarShareWithTrip(id string, context api2go.APIContexter
 	}
 	defer mgoSession.Close()
 	carShare := model.CarShare{}
-	err = mgoSession.DB(CarShareDB).C(CarSharesColl).Find(bson.M{"trips._id": bson.ObjectIdHex(id)}).One(&carShare)
+	err = s.Collection(mgoSession, CarSharesColl).Find(bson.M{"trips._id": bson.ObjectIdHex(id)}).One(&carShare)
 	return carShare, err
 }
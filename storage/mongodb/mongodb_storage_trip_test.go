@@ -1,6 +1,9 @@
 package mongodb
 
 import (
+	"errors"
+	"time"
+
 	"github.com/LewisWatson/carshare-back/model"
 	"github.com/LewisWatson/carshare-back/storage"
 
@@ -335,4 +338,142 @@ var _ = Describe("Trip Storage", func() {
 
 	})
 
+	Describe("iterating", func() {
+
+		var (
+			carShareID = bson.NewObjectId().Hex()
+			result     []model.Trip
+			err        error
+		)
+
+		BeforeEach(func() {
+			result = nil
+			err = db.DB(CarShareDB).C(TripsColl).Insert(
+				model.Trip{
+					ID:         bson.NewObjectId(),
+					Metres:     2,
+					CarShareID: carShareID,
+					TimeStamp:  time.Date(2017, 11, 2, 0, 0, 0, 0, time.UTC),
+				},
+				model.Trip{
+					ID:         bson.NewObjectId(),
+					Metres:     1,
+					CarShareID: carShareID,
+					TimeStamp:  time.Date(2017, 11, 1, 0, 0, 0, 0, time.UTC),
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+			err = tripStorage.Iterate(carShareID, func(trip model.Trip) error {
+				result = append(result, trip)
+				return nil
+			}, context)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should visit only trips in the car share, oldest first", func() {
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Metres).To(Equal(1))
+			Expect(result[1].Metres).To(Equal(2))
+		})
+
+		Context("when the callback returns an error", func() {
+
+			BeforeEach(func() {
+				result = nil
+				err = tripStorage.Iterate(carShareID, func(trip model.Trip) error {
+					result = append(result, trip)
+					return errors.New("stop")
+				}, context)
+			})
+
+			It("should stop and return the error", func() {
+				Expect(err).To(MatchError("stop"))
+				Expect(result).To(HaveLen(1))
+			})
+
+		})
+
+		Context("with missing mgo connection", func() {
+
+			BeforeEach(func() {
+				context.Reset()
+				err = tripStorage.Iterate(carShareID, func(trip model.Trip) error { return nil }, context)
+			})
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("stats", func() {
+
+		var (
+			carShareID = bson.NewObjectId().Hex()
+			userID     = bson.NewObjectId().Hex()
+			friendID   = bson.NewObjectId().Hex()
+			strangerID = bson.NewObjectId().Hex()
+			result     model.Stats
+			err        error
+		)
+
+		BeforeEach(func() {
+			for _, trip := range []model.Trip{
+				{Metres: 1000, TimeStamp: time.Date(2017, 10, 31, 8, 0, 0, 0, time.UTC), DriverID: userID, PassengerIDs: []string{friendID}},
+				{Metres: 2000, TimeStamp: time.Date(2017, 11, 1, 8, 0, 0, 0, time.UTC), DriverID: friendID, PassengerIDs: []string{userID, strangerID}, Status: model.TripConfirmed},
+				{Metres: 4000, TimeStamp: time.Date(2017, 11, 2, 8, 0, 0, 0, time.UTC), DriverID: userID, Status: model.TripDisputed},
+				{Metres: 8000, TimeStamp: time.Date(2017, 11, 3, 8, 0, 0, 0, time.UTC), DriverID: friendID},
+			} {
+				trip.CarShareID = carShareID
+				_, err := tripStorage.Insert(trip, context)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			result, err = tripStorage.Stats(userID, []string{carShareID}, context)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should total the counted trips the user was on", func() {
+			Expect(result.MetresAsDriver).To(Equal(1000))
+			Expect(result.MetresAsPassenger).To(Equal(2000))
+			Expect(result.TripsAsDriver).To(Equal(1))
+			Expect(result.TripsAsPassenger).To(Equal(1))
+		})
+
+		It("should break the trips down by month", func() {
+			Expect(result.Months).To(Equal([]model.MonthStats{
+				{Month: "2017-10", MetresAsDriver: 1000, TripsAsDriver: 1},
+				{Month: "2017-11", MetresAsPassenger: 2000, TripsAsPassenger: 1},
+			}))
+		})
+
+		It("should list who the user travelled with most first", func() {
+			Expect(result.CoPassengers).To(Equal([]model.CoPassenger{
+				{UserID: friendID, Trips: 2},
+				{UserID: strangerID, Trips: 1},
+			}))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			BeforeEach(func() {
+				context.Reset()
+				result, err = tripStorage.Stats(userID, []string{carShareID}, context)
+			})
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
 })
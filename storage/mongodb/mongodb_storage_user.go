@@ -10,7 +10,9 @@ import (
 )
 
 // UserStorage stores all users
-type UserStorage struct{}
+type UserStorage struct {
+	Config
+}
 
 // GetAll to satisfy storage.UserStorage interface
 func (s UserStorage) GetAll(context api2go.APIContexter) ([]model.User, error) {
@@ -20,7 +22,7 @@ func (s UserStorage) GetAll(context api2go.APIContexter) ([]model.User, error) {
 	}
 	defer mgoSession.Close()
 	result := []model.User{}
-	err = mgoSession.DB(CarShareDB).C(UsersColl).Find(nil).All(&result)
+	err = s.Collection(mgoSession, UsersColl).Find(nil).All(&result)
 	return result, err
 }
 
@@ -35,7 +37,7 @@ func (s UserStorage) GetOne(id string, context api2go.APIContexter) (model.User,
 	}
 	defer mgoSession.Close()
 	result := model.User{}
-	err = mgoSession.DB(CarShareDB).C(UsersColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	err = s.Collection(mgoSession, UsersColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -53,7 +55,7 @@ func (s UserStorage) GetByFirebaseUID(firebaseUID string, context api2go.APICont
 	}
 	defer mgoSession.Close()
 	result := model.User{}
-	err = mgoSession.DB(CarShareDB).C(UsersColl).Find(bson.M{"firebase-uid": firebaseUID}).One(&result)
+	err = s.Collection(mgoSession, UsersColl).Find(bson.M{"firebase-uid": firebaseUID}).One(&result)
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -68,7 +70,7 @@ func (s *UserStorage) Insert(u model.User, context api2go.APIContexter) (string,
 	}
 	defer mgoSession.Close()
 	u.ID = bson.NewObjectId()
-	err = mgoSession.DB(CarShareDB).C(UsersColl).Insert(&u)
+	err = s.Collection(mgoSession, UsersColl).Insert(&u)
 	return u.GetID(), err
 }
 
@@ -82,7 +84,7 @@ func (s *UserStorage) Delete(id string, context api2go.APIContexter) error {
 		return err
 	}
 	defer mgoSession.Close()
-	err = mgoSession.DB(CarShareDB).C(UsersColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	err = s.Collection(mgoSession, UsersColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
@@ -99,7 +101,7 @@ func (s *UserStorage) Update(u model.User, context api2go.APIContexter) error {
 		return err
 	}
 	defer mgoSession.Close()
-	err = mgoSession.DB(CarShareDB).C(UsersColl).Update(bson.M{"_id": u.ID}, &u)
+	err = s.Collection(mgoSession, UsersColl).Update(bson.M{"_id": u.ID}, &u)
 	if err == mgo.ErrNotFound {
 		err = storage.ErrNotFound
 	}
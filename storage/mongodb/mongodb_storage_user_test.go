@@ -398,4 +398,44 @@ var _ = Describe("User Storage", func() {
 
 	})
 
+	Describe("with custom config", func() {
+
+		var (
+			id     string
+			err    error
+			config = Config{DBName: "carshare_isolated", CollPrefix: "staging_"}
+		)
+
+		BeforeEach(func() {
+			err = db.DB(config.DBName).DropDatabase()
+			Expect(err).ToNot(HaveOccurred())
+			userStorage = &UserStorage{Config: config}
+			id, err = userStorage.Insert(model.User{DisplayName: "Isolated User"}, context)
+		})
+
+		It("should not throw an error", func() {
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should store the user in the configured database and prefixed collection", func() {
+			result := model.User{}
+			err = db.DB("carshare_isolated").C("staging_users").FindId(bson.ObjectIdHex(id)).One(&result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.DisplayName).To(Equal("Isolated User"))
+		})
+
+		It("should not store the user in the default database", func() {
+			count, err := db.DB(CarShareDB).C(UsersColl).FindId(bson.ObjectIdHex(id)).Count()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(count).To(Equal(0))
+		})
+
+		It("should only see users in the configured database", func() {
+			result, err := userStorage.GetAll(context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+		})
+
+	})
+
 })
@@ -0,0 +1,108 @@
+package mongodb
+
+import (
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// VehicleStorage stores all vehicles
+type VehicleStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.VehicleStorage interface
+func (s VehicleStorage) GetAll(carShareID string, ctx api2go.APIContexter) ([]model.Vehicle, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Vehicle{}
+	err = s.Collection(mgoSession, VehiclesColl).Find(bson.M{"car-share": carShareID}).Sort("name").All(&result)
+	if err != nil {
+		log.Errorf("Error finding vehicles for car share %s, %s", carShareID, err)
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.VehicleStorage interface
+func (s VehicleStorage) GetOne(id string, ctx api2go.APIContexter) (model.Vehicle, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Vehicle{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Vehicle{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Vehicle{}
+	err = s.Collection(mgoSession, VehiclesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding vehicle %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return result, err
+}
+
+// Insert to satisfy storage.VehicleStorage interface
+func (s *VehicleStorage) Insert(v model.Vehicle, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	v.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, VehiclesColl).Insert(&v)
+	if err != nil {
+		log.Errorf("Error inserting vehicle, %s", err)
+	}
+	return v.GetID(), err
+}
+
+// Delete to satisfy storage.VehicleStorage interface
+func (s *VehicleStorage) Delete(id string, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(id) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, VehiclesColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	if err != nil {
+		log.Errorf("Error deleting vehicle %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// Update to satisfy storage.VehicleStorage interface
+func (s *VehicleStorage) Update(v model.Vehicle, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(v.GetID()) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, VehiclesColl).Update(bson.M{"_id": v.ID}, &v)
+	if err != nil {
+		log.Errorf("Error updating vehicle %s, %s", v.GetID(), err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
@@ -0,0 +1,173 @@
+package mongodb
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Vehicle Storage", func() {
+
+	var (
+		vehicleStorage *VehicleStorage
+		context        *api2go.APIContext
+		carShareID     = bson.NewObjectId().Hex()
+		vehicle1ID     = bson.NewObjectId()
+	)
+
+	BeforeEach(func() {
+		vehicleStorage = &VehicleStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(VehiclesColl).Insert(
+			&model.Vehicle{
+				ID:          vehicle1ID,
+				Name:        "Estate",
+				Consumption: 5.5,
+				CarShareID:  carShareID,
+			},
+			&model.Vehicle{
+				Name:        "Convertible",
+				Consumption: 7.2,
+				CarShareID:  carShareID,
+			},
+			&model.Vehicle{
+				Name:       "Another car share",
+				CarShareID: bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		var (
+			result []model.Vehicle
+			err    error
+		)
+
+		BeforeEach(func() {
+			result, err = vehicleStorage.GetAll(carShareID, context)
+		})
+
+		It("should return the vehicles for the car share ordered by name", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Name).To(Equal("Convertible"))
+			Expect(result[1].Name).To(Equal("Estate"))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			BeforeEach(func() {
+				context.Reset()
+				result, err = vehicleStorage.GetAll(carShareID, context)
+			})
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		var (
+			result model.Vehicle
+			err    error
+		)
+
+		BeforeEach(func() {
+			result, err = vehicleStorage.GetOne(vehicle1ID.Hex(), context)
+		})
+
+		It("should return the specified vehicle", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("Estate"))
+			Expect(result.Consumption).To(Equal(5.5))
+		})
+
+		Context("targeting a vehicle that does not exist", func() {
+
+			BeforeEach(func() {
+				result, err = vehicleStorage.GetOne(bson.NewObjectId().Hex(), context)
+			})
+
+			It("should throw an ErrNotFound error", func() {
+				Expect(err).To(Equal(storage.ErrNotFound))
+			})
+
+		})
+
+		Context("using invalid id", func() {
+
+			BeforeEach(func() {
+				result, err = vehicleStorage.GetOne("invalid id", context)
+			})
+
+			It("should throw an ErrInvalidID error", func() {
+				Expect(err).To(Equal(storage.ErrInvalidID))
+			})
+
+		})
+
+	})
+
+	Describe("inserting", func() {
+
+		It("should insert a new vehicle", func() {
+			id, err := vehicleStorage.Insert(model.Vehicle{Name: "Van", CarShareID: carShareID}, context)
+			Expect(err).ToNot(HaveOccurred())
+			result := model.Vehicle{}
+			err = db.DB(CarShareDB).C(VehiclesColl).FindId(bson.ObjectIdHex(id)).One(&result)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Name).To(Equal("Van"))
+		})
+
+	})
+
+	Describe("updating", func() {
+
+		It("should update the vehicle", func() {
+			vehicle, err := vehicleStorage.GetOne(vehicle1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			vehicle.Consumption = 6
+			Expect(vehicleStorage.Update(vehicle, context)).To(Succeed())
+			vehicle, err = vehicleStorage.GetOne(vehicle1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(vehicle.Consumption).To(Equal(6.0))
+		})
+
+		It("should throw an ErrNotFound error for a vehicle that does not exist", func() {
+			err := vehicleStorage.Update(model.Vehicle{ID: bson.NewObjectId()}, context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+	Describe("deleting", func() {
+
+		It("should delete the vehicle", func() {
+			Expect(vehicleStorage.Delete(vehicle1ID.Hex(), context)).To(Succeed())
+			_, err := vehicleStorage.GetOne(vehicle1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should throw an ErrNotFound error for a vehicle that does not exist", func() {
+			err := vehicleStorage.Delete(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
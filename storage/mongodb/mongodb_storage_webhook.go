@@ -0,0 +1,220 @@
+package mongodb
+
+import (
+	"time"
+
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/manyminds/api2go"
+)
+
+// WebhookStorage stores all webhooks
+type WebhookStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.WebhookStorage interface
+func (s WebhookStorage) GetAll(carShareID string, ctx api2go.APIContexter) ([]model.Webhook, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Webhook{}
+	err = s.Collection(mgoSession, WebhooksColl).Find(bson.M{"car-share": carShareID}).Sort("url").All(&result)
+	if err != nil {
+		log.Errorf("Error finding webhooks for car share %s, %s", carShareID, err)
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.WebhookStorage interface
+func (s WebhookStorage) GetOne(id string, ctx api2go.APIContexter) (model.Webhook, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Webhook{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Webhook{}
+	err = s.Collection(mgoSession, WebhooksColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding webhook %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return result, err
+}
+
+// Insert to satisfy storage.WebhookStorage interface
+func (s *WebhookStorage) Insert(w model.Webhook, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	w.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, WebhooksColl).Insert(&w)
+	if err != nil {
+		log.Errorf("Error inserting webhook, %s", err)
+	}
+	return w.GetID(), err
+}
+
+// Delete to satisfy storage.WebhookStorage interface
+func (s *WebhookStorage) Delete(id string, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(id) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, WebhooksColl).Remove(bson.M{"_id": bson.ObjectIdHex(id)})
+	if err != nil {
+		log.Errorf("Error deleting webhook %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// Update to satisfy storage.WebhookStorage interface
+func (s *WebhookStorage) Update(w model.Webhook, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(w.GetID()) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, WebhooksColl).Update(bson.M{"_id": w.ID}, &w)
+	if err != nil {
+		log.Errorf("Error updating webhook %s, %s", w.GetID(), err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// DeliveryStorage stores the queue of deliveries to webhooks
+type DeliveryStorage struct {
+	Config
+}
+
+// GetAll to satisfy storage.DeliveryStorage interface
+func (s DeliveryStorage) GetAll(webhookID string, ctx api2go.APIContexter) ([]model.Delivery, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Delivery{}
+	err = s.Collection(mgoSession, DeliveriesColl).Find(bson.M{"webhook": webhookID}).Sort("-created").All(&result)
+	if err != nil {
+		log.Errorf("Error finding deliveries for webhook %s, %s", webhookID, err)
+	}
+	for i := range result {
+		setDeliveryTimezoneToUTC(&result[i])
+	}
+	return result, err
+}
+
+// GetDue to satisfy storage.DeliveryStorage interface
+func (s DeliveryStorage) GetDue(now time.Time, ctx api2go.APIContexter) ([]model.Delivery, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer mgoSession.Close()
+	result := []model.Delivery{}
+	err = s.Collection(mgoSession, DeliveriesColl).Find(bson.M{
+		"status":       model.DeliveryPending,
+		"next-attempt": bson.M{"$lte": now},
+	}).Sort("next-attempt").All(&result)
+	if err != nil {
+		log.Errorf("Error finding deliveries due by %s, %s", now, err)
+	}
+	for i := range result {
+		setDeliveryTimezoneToUTC(&result[i])
+	}
+	return result, err
+}
+
+// GetOne to satisfy storage.DeliveryStorage interface
+func (s DeliveryStorage) GetOne(id string, ctx api2go.APIContexter) (model.Delivery, error) {
+
+	if !bson.IsObjectIdHex(id) {
+		return model.Delivery{}, storage.ErrInvalidID
+	}
+
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return model.Delivery{}, err
+	}
+	defer mgoSession.Close()
+	result := model.Delivery{}
+	err = s.Collection(mgoSession, DeliveriesColl).Find(bson.M{"_id": bson.ObjectIdHex(id)}).One(&result)
+	if err != nil {
+		log.Errorf("Error finding delivery %s, %s", id, err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	setDeliveryTimezoneToUTC(&result)
+	return result, err
+}
+
+// Insert to satisfy storage.DeliveryStorage interface
+func (s *DeliveryStorage) Insert(d model.Delivery, ctx api2go.APIContexter) (string, error) {
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer mgoSession.Close()
+	d.ID = bson.NewObjectId()
+	err = s.Collection(mgoSession, DeliveriesColl).Insert(&d)
+	if err != nil {
+		log.Errorf("Error inserting delivery, %s", err)
+	}
+	return d.GetID(), err
+}
+
+// Update to satisfy storage.DeliveryStorage interface
+func (s *DeliveryStorage) Update(d model.Delivery, ctx api2go.APIContexter) error {
+	if !bson.IsObjectIdHex(d.GetID()) {
+		return storage.ErrInvalidID
+	}
+	mgoSession, err := getMgoSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer mgoSession.Close()
+	err = s.Collection(mgoSession, DeliveriesColl).Update(bson.M{"_id": d.ID}, &d)
+	if err != nil {
+		log.Errorf("Error updating delivery %s, %s", d.GetID(), err)
+		if err == mgo.ErrNotFound {
+			err = storage.ErrNotFound
+		}
+	}
+	return err
+}
+
+// setDeliveryTimezoneToUTC as times come back from mongo in the local timezone
+func setDeliveryTimezoneToUTC(d *model.Delivery) {
+	d.Created = d.Created.UTC()
+	d.NextAttempt = d.NextAttempt.UTC()
+	d.Delivered = d.Delivered.UTC()
+}
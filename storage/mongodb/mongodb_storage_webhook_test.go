@@ -0,0 +1,236 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+
+	"github.com/manyminds/api2go"
+	"gopkg.in/mgo.v2/bson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhook Storage", func() {
+
+	var (
+		webhookStorage *WebhookStorage
+		context        *api2go.APIContext
+		carShareID     = bson.NewObjectId().Hex()
+		webhook1ID     = bson.NewObjectId()
+	)
+
+	BeforeEach(func() {
+		webhookStorage = &WebhookStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(WebhooksColl).Insert(
+			&model.Webhook{
+				ID:         webhook1ID,
+				URL:        "https://a.example.com/hook",
+				Secret:     "shh",
+				Events:     []string{model.TripCreated},
+				CarShareID: carShareID,
+			},
+			&model.Webhook{
+				URL:        "https://b.example.com/hook",
+				Secret:     "shh",
+				Events:     []string{model.MemberAdded},
+				CarShareID: carShareID,
+			},
+			&model.Webhook{
+				URL:        "https://another.example.com/hook",
+				Secret:     "shh",
+				Events:     []string{model.TripCreated},
+				CarShareID: bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		It("should return the webhooks for the car share ordered by url", func() {
+			result, err := webhookStorage.GetAll(carShareID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].GetID()).To(Equal(webhook1ID.Hex()))
+			Expect(result[1].URL).To(Equal("https://b.example.com/hook"))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				context.Reset()
+				_, err := webhookStorage.GetAll(carShareID, context)
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should return the specified webhook, including its secret", func() {
+			result, err := webhookStorage.GetOne(webhook1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.URL).To(Equal("https://a.example.com/hook"))
+			Expect(result.Secret).To(Equal("shh"))
+		})
+
+		It("should throw an ErrNotFound error for a webhook that does not exist", func() {
+			_, err := webhookStorage.GetOne(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+		It("should throw an ErrInvalidID error for an invalid id", func() {
+			_, err := webhookStorage.GetOne("invalid", context)
+			Expect(err).To(Equal(storage.ErrInvalidID))
+		})
+
+	})
+
+	Describe("updating", func() {
+
+		It("should update the webhook", func() {
+			webhook, err := webhookStorage.GetOne(webhook1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			webhook.Events = []string{model.TripCreated, model.TripDeleted}
+			Expect(webhookStorage.Update(webhook, context)).To(Succeed())
+			webhook, err = webhookStorage.GetOne(webhook1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(webhook.Events).To(Equal([]string{model.TripCreated, model.TripDeleted}))
+		})
+
+	})
+
+	Describe("deleting", func() {
+
+		It("should delete the webhook", func() {
+			Expect(webhookStorage.Delete(webhook1ID.Hex(), context)).To(Succeed())
+			_, err := webhookStorage.GetOne(webhook1ID.Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+})
+
+var _ = Describe("Delivery Storage", func() {
+
+	var (
+		deliveryStorage *DeliveryStorage
+		context         *api2go.APIContext
+		webhookID       = bson.NewObjectId().Hex()
+		delivery1ID     = bson.NewObjectId()
+		now             = time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		deliveryStorage = &DeliveryStorage{}
+		context = &api2go.APIContext{}
+		db, pool, containerResource = ConnectToMongoDB(db, pool, containerResource)
+		err := db.DB(CarShareDB).DropDatabase()
+		Expect(err).ToNot(HaveOccurred())
+		context.Set("db", db)
+		err = db.DB(CarShareDB).C(DeliveriesColl).Insert(
+			&model.Delivery{
+				ID:          delivery1ID,
+				Event:       model.TripCreated,
+				Status:      model.DeliveryPending,
+				Created:     now.Add(-time.Hour),
+				NextAttempt: now,
+				WebhookID:   webhookID,
+			},
+			&model.Delivery{
+				Event:     model.TripUpdated,
+				Status:    model.DeliveryDelivered,
+				Created:   now.Add(-2 * time.Hour),
+				Delivered: now.Add(-2 * time.Hour),
+				WebhookID: webhookID,
+			},
+			&model.Delivery{
+				Event:       model.TripDeleted,
+				Status:      model.DeliveryPending,
+				Created:     now,
+				NextAttempt: now.Add(time.Minute),
+				WebhookID:   webhookID,
+			},
+			&model.Delivery{
+				Event:       model.MemberAdded,
+				Status:      model.DeliveryPending,
+				Created:     now.Add(-3 * time.Hour),
+				NextAttempt: now.Add(-time.Hour),
+				WebhookID:   bson.NewObjectId().Hex(),
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("get all", func() {
+
+		It("should return the deliveries to the webhook, newest first", func() {
+			result, err := deliveryStorage.GetAll(webhookID, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(3))
+			Expect(result[0].Event).To(Equal(model.TripDeleted))
+			Expect(result[1].GetID()).To(Equal(delivery1ID.Hex()))
+			Expect(result[2].Event).To(Equal(model.TripUpdated))
+		})
+
+	})
+
+	Describe("get due", func() {
+
+		It("should return pending deliveries to any webhook that are due, soonest first", func() {
+			result, err := deliveryStorage.GetDue(now, context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(result[0].Event).To(Equal(model.MemberAdded))
+			Expect(result[1].GetID()).To(Equal(delivery1ID.Hex()))
+			Expect(result[1].NextAttempt).To(Equal(now))
+		})
+
+		Context("with missing mgo connection", func() {
+
+			It("should return an ErrorNoDBSessionInContext error", func() {
+				context.Reset()
+				_, err := deliveryStorage.GetDue(now, context)
+				Expect(err).To(Equal(ErrorNoDBSessionInContext))
+			})
+
+		})
+
+	})
+
+	Describe("get one", func() {
+
+		It("should throw an ErrNotFound error for a delivery that does not exist", func() {
+			_, err := deliveryStorage.GetOne(bson.NewObjectId().Hex(), context)
+			Expect(err).To(Equal(storage.ErrNotFound))
+		})
+
+	})
+
+	Describe("updating", func() {
+
+		It("should update the delivery", func() {
+			delivery, err := deliveryStorage.GetOne(delivery1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			delivery.Succeeded(now, 200)
+			Expect(deliveryStorage.Update(delivery, context)).To(Succeed())
+			delivery, err = deliveryStorage.GetOne(delivery1ID.Hex(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(delivery.Status).To(Equal(model.DeliveryDelivered))
+			Expect(delivery.Delivered).To(Equal(now))
+		})
+
+	})
+
+})
@@ -7,8 +7,8 @@ import (
 	mgo "gopkg.in/mgo.v2"
 )
 
-var (
-	// CarShareDB mongo database name
+const (
+	// CarShareDB default mongo database name
 	CarShareDB = "carshare"
 
 	// UsersColl mongo collection name for users
@@ -20,6 +20,29 @@ var (
 	// CarSharesColl mongo collection name for car shares
 	CarSharesColl = "carshares"
 
+	// RoutesColl mongo collection name for routes
+	RoutesColl = "routes"
+
+	// SchedulesColl mongo collection name for schedules
+	SchedulesColl = "schedules"
+
+	// ExpensesColl mongo collection name for expenses
+	ExpensesColl = "expenses"
+
+	// VehiclesColl mongo collection name for vehicles
+	VehiclesColl = "vehicles"
+
+	// WebhooksColl mongo collection name for webhooks
+	WebhooksColl = "webhooks"
+
+	// DeliveriesColl mongo collection name for deliveries to webhooks
+	DeliveriesColl = "deliveries"
+
+	// DevicesColl mongo collection name for devices registered for push notifications
+	DevicesColl = "devices"
+)
+
+var (
 	// ErrorNoDBSessionInContext request context is missing database session
 	ErrorNoDBSessionInContext = errors.New("Error retrieving mongodb session from context")
 
@@ -27,6 +50,30 @@ var (
 	ErrorInvalidDBSession = errors.New("Error asserting type of mongodb session from context")
 )
 
+// Config identifies the database and collections a storage struct works with.
+// The zero value uses the CarShareDB database with unprefixed collection names.
+type Config struct {
+	// DBName mongo database name, defaults to CarShareDB when empty
+	DBName string
+
+	// CollPrefix prepended to every collection name
+	CollPrefix string
+}
+
+// Database returns the configured database for the provided session
+func (c Config) Database(mgoSession *mgo.Session) *mgo.Database {
+	name := c.DBName
+	if name == "" {
+		name = CarShareDB
+	}
+	return mgoSession.DB(name)
+}
+
+// Collection returns the configured (prefixed) collection for the provided session
+func (c Config) Collection(mgoSession *mgo.Session, name string) *mgo.Collection {
+	return c.Database(mgoSession).C(c.CollPrefix + name)
+}
+
 func getMgoSession(context api2go.APIContexter) (*mgo.Session, error) {
 	ctxMgoSession, ok := context.Get("db")
 	if !ok {
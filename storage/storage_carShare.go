@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"time"
+
 	"github.com/LewisWatson/carshare-back/model"
 	"github.com/manyminds/api2go"
 )
@@ -12,4 +14,8 @@ type CarShareStorage interface {
 	Insert(c model.CarShare, context api2go.APIContexter) (string, error)
 	Delete(id string, context api2go.APIContexter) error
 	Update(c model.CarShare, context api2go.APIContexter) error
+
+	// Get all car shares with a digest or reminder due at or before the given time, or that
+	// have yet to have one worked out
+	GetNotificationsDue(now time.Time, context api2go.APIContexter) ([]model.CarShare, error)
 }
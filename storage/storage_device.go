@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// DeviceStorage interface for device stores. All devices must be tied to a user.
+type DeviceStorage interface {
+
+	// Get all devices registered by a user
+	GetAll(userID string, context api2go.APIContexter) ([]model.Device, error)
+
+	// Get a device
+	GetOne(id string, context api2go.APIContexter) (model.Device, error)
+
+	// Insert a device
+	Insert(d model.Device, context api2go.APIContexter) (string, error)
+
+	// Delete a device
+	Delete(id string, context api2go.APIContexter) error
+
+	// Delete every registration of a push token, whichever user registered it
+	DeleteToken(token string, context api2go.APIContexter) error
+}
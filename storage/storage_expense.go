@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// ExpenseStorage interface for expense stores. All expenses must be tied to a car share.
+type ExpenseStorage interface {
+
+	// Get all expenses in a car share, oldest first
+	GetAll(carShareID string, context api2go.APIContexter) ([]model.Expense, error)
+
+	// Get an expense
+	GetOne(id string, context api2go.APIContexter) (model.Expense, error)
+
+	// Insert an expense
+	Insert(e model.Expense, context api2go.APIContexter) (string, error)
+
+	// Delete an expense
+	Delete(id string, context api2go.APIContexter) error
+
+	// Update an expense
+	Update(e model.Expense, context api2go.APIContexter) error
+}
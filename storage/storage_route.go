@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// RouteStorage interface for route stores. All routes must be tied to a car share.
+type RouteStorage interface {
+
+	// Get all routes in a car share
+	GetAll(carShareID string, context api2go.APIContexter) ([]model.Route, error)
+
+	// Get a route
+	GetOne(id string, context api2go.APIContexter) (model.Route, error)
+
+	// Insert a route
+	Insert(rt model.Route, context api2go.APIContexter) (string, error)
+
+	// Delete a route
+	Delete(id string, context api2go.APIContexter) error
+
+	// Update a route
+	Update(rt model.Route, context api2go.APIContexter) error
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// ScheduleStorage interface for schedule stores. All schedules must be tied to a car share.
+type ScheduleStorage interface {
+
+	// Get all schedules in a car share
+	GetAll(carShareID string, context api2go.APIContexter) ([]model.Schedule, error)
+
+	// Get all schedules, in any car share, with an occurrence due at or before the given time
+	GetDue(now time.Time, context api2go.APIContexter) ([]model.Schedule, error)
+
+	// Get a schedule
+	GetOne(id string, context api2go.APIContexter) (model.Schedule, error)
+
+	// Insert a schedule
+	Insert(s model.Schedule, context api2go.APIContexter) (string, error)
+
+	// Delete a schedule
+	Delete(id string, context api2go.APIContexter) error
+
+	// Update a schedule
+	Update(s model.Schedule, context api2go.APIContexter) error
+}
@@ -25,4 +25,12 @@ type TripStorage interface {
 
 	// Get latest trip in a car share
 	GetLatest(carShareID string, context api2go.APIContexter) (model.Trip, error)
+
+	// Iterate over every trip in a car share, oldest first, without loading them all
+	// into memory. Iteration stops at the first error returned by fn
+	Iterate(carShareID string, fn func(model.Trip) error, context api2go.APIContexter) error
+
+	// Stats of a user over the trips that count in the given car shares, worked out by the
+	// store rather than by loading the trips
+	Stats(userID string, carShareIDs []string, context api2go.APIContexter) (model.Stats, error)
 }
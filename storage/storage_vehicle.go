@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// VehicleStorage interface for vehicle stores. All vehicles must be tied to a car share.
+type VehicleStorage interface {
+
+	// Get all vehicles in a car share
+	GetAll(carShareID string, context api2go.APIContexter) ([]model.Vehicle, error)
+
+	// Get a vehicle
+	GetOne(id string, context api2go.APIContexter) (model.Vehicle, error)
+
+	// Insert a vehicle
+	Insert(v model.Vehicle, context api2go.APIContexter) (string, error)
+
+	// Delete a vehicle
+	Delete(id string, context api2go.APIContexter) error
+
+	// Update a vehicle
+	Update(v model.Vehicle, context api2go.APIContexter) error
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/manyminds/api2go"
+)
+
+// WebhookStorage interface for webhook stores. All webhooks must be tied to a car share.
+type WebhookStorage interface {
+
+	// Get all webhooks of a car share
+	GetAll(carShareID string, context api2go.APIContexter) ([]model.Webhook, error)
+
+	// Get a webhook
+	GetOne(id string, context api2go.APIContexter) (model.Webhook, error)
+
+	// Insert a webhook
+	Insert(w model.Webhook, context api2go.APIContexter) (string, error)
+
+	// Delete a webhook
+	Delete(id string, context api2go.APIContexter) error
+
+	// Update a webhook
+	Update(w model.Webhook, context api2go.APIContexter) error
+}
+
+// DeliveryStorage interface for the queue of deliveries to webhooks
+type DeliveryStorage interface {
+
+	// Get all deliveries to a webhook, newest first
+	GetAll(webhookID string, context api2go.APIContexter) ([]model.Delivery, error)
+
+	// Get all pending deliveries, to any webhook, due to be attempted at or before the given time
+	GetDue(now time.Time, context api2go.APIContexter) ([]model.Delivery, error)
+
+	// Get a delivery
+	GetOne(id string, context api2go.APIContexter) (model.Delivery, error)
+
+	// Insert a delivery
+	Insert(d model.Delivery, context api2go.APIContexter) (string, error)
+
+	// Update a delivery
+	Update(d model.Delivery, context api2go.APIContexter) error
+}
@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-Carshare-Event"
+	DeliveryHeader  = "X-Carshare-Delivery"
+	SignatureHeader = "X-Carshare-Signature"
+)
+
+// Deliverer posts queued deliveries to their webhooks
+type Deliverer struct {
+	WebhookStorage  storage.WebhookStorage
+	DeliveryStorage storage.DeliveryStorage
+	Client          *http.Client
+	Clock           clock.Clock
+}
+
+// Run sends due deliveries immediately and then every interval, until stop is closed
+func (d Deliverer) Run(interval time.Duration, ctx api2go.APIContexter, stop <-chan struct{}) {
+	ticker := d.Clock.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		sent, err := d.Tick(ctx)
+		if err != nil {
+			log.Errorf("Error delivering to webhooks, %s", err)
+		}
+		if sent > 0 {
+			log.Infof("sent %d webhook deliveries", sent)
+		}
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick attempts every delivery that has fallen due, returning how many were attempted.
+// A webhook failing to accept a delivery isn't an error, the delivery is retried later.
+// Only problems storing the outcome are returned, the first one if there are several.
+func (d Deliverer) Tick(ctx api2go.APIContexter) (int, error) {
+
+	deliveries, err := d.DeliveryStorage.GetDue(d.Clock.Now().UTC(), ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving due deliveries, %s", err)
+	}
+
+	var firstErr error
+	for _, delivery := range deliveries {
+		d.attempt(&delivery, ctx)
+		err = d.DeliveryStorage.Update(delivery, ctx)
+		if err != nil {
+			log.Errorf("Error updating delivery %s, %s", delivery.GetID(), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return len(deliveries), firstErr
+}
+
+// attempt to post the delivery to its webhook, recording the outcome
+func (d Deliverer) attempt(delivery *model.Delivery, ctx api2go.APIContexter) {
+
+	webhook, err := d.WebhookStorage.GetOne(delivery.WebhookID, ctx)
+	if err != nil {
+		// the webhook has been deleted, there's nowhere left to deliver to
+		delivery.Abandon(fmt.Sprintf("webhook unavailable, %s", err))
+		return
+	}
+
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		delivery.Failed(d.Clock.Now().UTC(), 0, err.Error())
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.GetID())
+	req.Header.Set(SignatureHeader, webhook.Sign(body))
+
+	resp, err := d.client().Do(req)
+	if err != nil {
+		delivery.Failed(d.Clock.Now().UTC(), 0, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+	// drain the body so the connection can be reused
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		delivery.Succeeded(d.Clock.Now().UTC(), resp.StatusCode)
+		return
+	}
+	delivery.Failed(d.Clock.Now().UTC(), resp.StatusCode, resp.Status)
+}
+
+func (d Deliverer) client() *http.Client {
+	if d.Client == nil {
+		return http.DefaultClient
+	}
+	return d.Client
+}
@@ -0,0 +1,7 @@
+package webhook
+
+import (
+	logging "github.com/op/go-logging"
+)
+
+var log = logging.MustGetLogger("webhook")
@@ -0,0 +1,84 @@
+/*
+Package webhook queues car share events for the webhooks that want them and delivers them,
+retrying failed deliveries with exponential backoff.
+*/
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage"
+	"github.com/benbjohnson/clock"
+	"github.com/manyminds/api2go"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+// Payload is the JSON body posted to a webhook. Data is the {json:api} resource object the
+// event is about.
+type Payload struct {
+	Event     string        `json:"event"`
+	CarShare  string        `json:"carShare"`
+	Timestamp time.Time     `json:"timestamp"`
+	Data      *jsonapi.Data `json:"data"`
+}
+
+// Notifier queues a delivery of an event for every webhook of the car share that wants it
+type Notifier struct {
+	WebhookStorage  storage.WebhookStorage
+	DeliveryStorage storage.DeliveryStorage
+	Clock           clock.Clock
+}
+
+// Notify the webhooks of a car share of an event about the resource. Deliveries are only
+// queued here, the Deliverer sends them.
+func (n Notifier) Notify(carShareID, event string, resource jsonapi.MarshalIdentifier, ctx api2go.APIContexter) error {
+
+	webhooks, err := n.WebhookStorage.GetAll(carShareID, ctx)
+	if err != nil {
+		return fmt.Errorf("error retrieving webhooks of car share %s, %s", carShareID, err)
+	}
+
+	var payload []byte
+	now := n.Clock.Now().UTC()
+	for _, webhook := range webhooks {
+		if !webhook.Wants(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = marshalPayload(carShareID, event, resource, now)
+			if err != nil {
+				return fmt.Errorf("error marshalling %s event, %s", event, err)
+			}
+		}
+		_, err = n.DeliveryStorage.Insert(model.Delivery{
+			Event:       event,
+			Payload:     string(payload),
+			Status:      model.DeliveryPending,
+			Created:     now,
+			NextAttempt: now,
+			WebhookID:   webhook.GetID(),
+			CarShareID:  carShareID,
+		}, ctx)
+		if err != nil {
+			return fmt.Errorf("error queuing %s delivery to webhook %s, %s", event, webhook.GetID(), err)
+		}
+	}
+
+	return nil
+}
+
+func marshalPayload(carShareID, event string, resource jsonapi.MarshalIdentifier, now time.Time) ([]byte, error) {
+	document, err := jsonapi.MarshalToStruct(resource, nil)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(Payload{
+		Event:     event,
+		CarShare:  carShareID,
+		Timestamp: now,
+		Data:      document.Data.DataObject,
+	})
+}
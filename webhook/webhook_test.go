@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/LewisWatson/carshare-back/model"
+	"github.com/LewisWatson/carshare-back/storage/in-memory"
+	"github.com/benbjohnson/clock"
+
+	"github.com/manyminds/api2go"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Webhooks", func() {
+
+	var (
+		notifier        Notifier
+		deliverer       Deliverer
+		mockClock       *clock.Mock
+		context         *api2go.APIContext
+		webhookStorage  *memory.WebhookStorage
+		deliveryStorage *memory.DeliveryStorage
+		server          *httptest.Server
+		status          int
+		received        []*http.Request
+		bodies          [][]byte
+		webhook         model.Webhook
+		trip            model.Trip
+		sent            int
+		err             error
+	)
+
+	BeforeEach(func() {
+		status = http.StatusOK
+		received = nil
+		bodies = nil
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			received = append(received, r)
+			bodies = append(bodies, body)
+			w.WriteHeader(status)
+		}))
+
+		mockClock = clock.NewMock()
+		mockClock.Set(time.Date(2017, 11, 6, 8, 0, 0, 0, time.UTC))
+		context = &api2go.APIContext{}
+		webhookStorage = memory.NewWebhookStorage()
+		deliveryStorage = memory.NewDeliveryStorage()
+		notifier = Notifier{
+			WebhookStorage:  webhookStorage,
+			DeliveryStorage: deliveryStorage,
+			Clock:           mockClock,
+		}
+		deliverer = Deliverer{
+			WebhookStorage:  webhookStorage,
+			DeliveryStorage: deliveryStorage,
+			Client:          server.Client(),
+			Clock:           mockClock,
+		}
+
+		webhook = model.Webhook{
+			URL:        server.URL,
+			Secret:     "shh",
+			Events:     []string{model.TripCreated},
+			CarShareID: "carShare1",
+		}
+		webhookID, err := webhookStorage.Insert(webhook, context)
+		Expect(err).ToNot(HaveOccurred())
+		webhook.SetID(webhookID)
+
+		trip = model.Trip{Metres: 1000, CarShareID: "carShare1", DriverID: "driver"}
+		trip.SetID("5a0b7a8b9bee1b0012345678")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Context("notify", func() {
+
+		It("should queue a delivery for webhooks wanting the event", func() {
+			Expect(notifier.Notify("carShare1", model.TripCreated, trip, context)).To(Succeed())
+			deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deliveries).To(HaveLen(1))
+			Expect(deliveries[0].Status).To(Equal(model.DeliveryPending))
+			Expect(deliveries[0].Event).To(Equal(model.TripCreated))
+
+			var payload struct {
+				Event    string `json:"event"`
+				CarShare string `json:"carShare"`
+				Data     struct {
+					Type string `json:"type"`
+					ID   string `json:"id"`
+				} `json:"data"`
+			}
+			Expect(json.Unmarshal([]byte(deliveries[0].Payload), &payload)).To(Succeed())
+			Expect(payload.Event).To(Equal(model.TripCreated))
+			Expect(payload.CarShare).To(Equal("carShare1"))
+			Expect(payload.Data.Type).To(Equal("trips"))
+			Expect(payload.Data.ID).To(Equal(trip.GetID()))
+		})
+
+		It("should not queue deliveries for events the webhook doesn't want", func() {
+			Expect(notifier.Notify("carShare1", model.TripDeleted, trip, context)).To(Succeed())
+			deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deliveries).To(BeEmpty())
+		})
+
+		It("should not queue deliveries for other car shares' webhooks", func() {
+			Expect(notifier.Notify("carShare2", model.TripCreated, trip, context)).To(Succeed())
+			deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deliveries).To(BeEmpty())
+		})
+
+	})
+
+	Context("deliver", func() {
+
+		BeforeEach(func() {
+			Expect(notifier.Notify("carShare1", model.TripCreated, trip, context)).To(Succeed())
+		})
+
+		Context("webhook accepts the delivery", func() {
+
+			BeforeEach(func() {
+				sent, err = deliverer.Tick(context)
+			})
+
+			It("should post the signed payload", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(1))
+				Expect(received).To(HaveLen(1))
+				Expect(received[0].Method).To(Equal(http.MethodPost))
+				Expect(received[0].Header.Get(EventHeader)).To(Equal(model.TripCreated))
+				Expect(received[0].Header.Get(SignatureHeader)).To(Equal(webhook.Sign(bodies[0])))
+			})
+
+			It("should mark the delivery delivered", func() {
+				deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deliveries[0].Status).To(Equal(model.DeliveryDelivered))
+				Expect(deliveries[0].ResponseCode).To(Equal(http.StatusOK))
+				Expect(received[0].Header.Get(DeliveryHeader)).To(Equal(deliveries[0].GetID()))
+			})
+
+			It("should not deliver it again", func() {
+				sent, err = deliverer.Tick(context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(0))
+				Expect(received).To(HaveLen(1))
+			})
+
+		})
+
+		Context("webhook rejects the delivery", func() {
+
+			BeforeEach(func() {
+				status = http.StatusInternalServerError
+				sent, err = deliverer.Tick(context)
+			})
+
+			It("should schedule a retry", func() {
+				Expect(err).ToNot(HaveOccurred())
+				deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deliveries[0].Status).To(Equal(model.DeliveryPending))
+				Expect(deliveries[0].Attempts).To(Equal(1))
+				Expect(deliveries[0].ResponseCode).To(Equal(http.StatusInternalServerError))
+				Expect(deliveries[0].NextAttempt).To(Equal(mockClock.Now().UTC().Add(model.FirstRetry)))
+			})
+
+			It("should not retry before the backoff has passed", func() {
+				sent, err = deliverer.Tick(context)
+				Expect(sent).To(Equal(0))
+			})
+
+			It("should retry once the backoff has passed", func() {
+				status = http.StatusNoContent
+				mockClock.Add(model.FirstRetry)
+				sent, err = deliverer.Tick(context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sent).To(Equal(1))
+				deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deliveries[0].Status).To(Equal(model.DeliveryDelivered))
+				Expect(deliveries[0].Attempts).To(Equal(2))
+			})
+
+			It("should give up after the maximum number of attempts", func() {
+				for i := 1; i < model.MaxDeliveryAttempts; i++ {
+					mockClock.Add(model.FirstRetry << uint(i))
+					_, err = deliverer.Tick(context)
+					Expect(err).ToNot(HaveOccurred())
+				}
+				Expect(received).To(HaveLen(model.MaxDeliveryAttempts))
+				deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deliveries[0].Status).To(Equal(model.DeliveryFailed))
+			})
+
+		})
+
+		Context("webhook deleted", func() {
+
+			BeforeEach(func() {
+				Expect(webhookStorage.Delete(webhook.GetID(), context)).To(Succeed())
+				sent, err = deliverer.Tick(context)
+			})
+
+			It("should abandon the delivery", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(received).To(BeEmpty())
+				deliveries, err := deliveryStorage.GetAll(webhook.GetID(), context)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(deliveries[0].Status).To(Equal(model.DeliveryFailed))
+			})
+
+		})
+
+	})
+
+})